@@ -1,17 +1,38 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/diagnosis/luxsuv-v4/internal/apikeys"
 	"github.com/diagnosis/luxsuv-v4/internal/auth"
 	"github.com/diagnosis/luxsuv-v4/internal/config"
 	"github.com/diagnosis/luxsuv-v4/internal/email"
+	"github.com/diagnosis/luxsuv-v4/internal/flights"
+	"github.com/diagnosis/luxsuv-v4/internal/geo"
 	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/idempotency"
+	"github.com/diagnosis/luxsuv-v4/internal/ledger"
 	"github.com/diagnosis/luxsuv-v4/internal/logger"
 	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/diagnosis/luxsuv-v4/internal/observer"
+	"github.com/diagnosis/luxsuv-v4/internal/organizations"
+	"github.com/diagnosis/luxsuv-v4/internal/payments"
+	"github.com/diagnosis/luxsuv-v4/internal/pricing"
+	"github.com/diagnosis/luxsuv-v4/internal/receipts"
+	"github.com/diagnosis/luxsuv-v4/internal/recurrence"
+	"github.com/diagnosis/luxsuv-v4/internal/reminders"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
 	"github.com/diagnosis/luxsuv-v4/internal/repository/postgres"
 	"github.com/diagnosis/luxsuv-v4/internal/routes"
+	"github.com/diagnosis/luxsuv-v4/internal/scheduler"
+	"github.com/diagnosis/luxsuv-v4/internal/tracking"
+	"github.com/diagnosis/luxsuv-v4/internal/webhooks"
+	"github.com/diagnosis/luxsuv-v4/internal/zones"
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	_ "github.com/lib/pq"
@@ -52,6 +73,11 @@ func main() {
 	// Initialize handlers
 	handlers := initializeHandlers(services, log)
 
+	// Start background jobs
+	go services.EventHub.Run(context.Background())
+	go services.Scheduler.Run(context.Background())
+	go services.Webhooks.Run(context.Background())
+
 	// Set up Echo server
 	e := echo.New()
 	e.HideBanner = true
@@ -59,6 +85,7 @@ func main() {
 
 	// Setup global middleware
 	middlewareConfig := routes.SetupGlobalMiddleware(e, cfg.Environment)
+	middlewareConfig.Idempotency = services.Idempotency.Middleware()
 	log.Info(fmt.Sprintf("Using %s CORS configuration", cfg.Environment))
 
 	// Setup all routes
@@ -76,9 +103,23 @@ func main() {
 
 // Services holds all initialized services
 type Services struct {
-	AuthService    *auth.Service
-	EmailService   *email.Service
-	AuthMiddleware *middleware.AuthMiddleware
+	AuthService     *auth.Service
+	EmailService    *email.Service
+	PaymentService  *payments.Service
+	Geocoder        geo.Geocoder
+	FlightTracker   *flights.Tracker
+	SeriesGenerator *recurrence.Generator
+	LedgerService   *ledger.Service
+	OrgService      *organizations.Service
+	EventHub        *observer.Hub
+	Tracker         *tracking.Service
+	SMSService      *notify.Service
+	Notifier        *notify.Dispatcher
+	Scheduler       *scheduler.Scheduler
+	Webhooks        *webhooks.Service
+	APIKeys         *apikeys.Service
+	Idempotency     *idempotency.Service
+	AuthMiddleware  *middleware.AuthMiddleware
 }
 
 // Handlers holds all initialized handlers
@@ -87,6 +128,23 @@ type Handlers struct {
 	UserHandler     *handlers.UserHandler
 	PasswordHandler *handlers.PasswordHandler
 	BookRideHandler *handlers.BookRideHandler
+	PaymentHandler  *handlers.PaymentHandler
+	PolicyHandler   *handlers.CancellationPolicyHandler
+	ZoneHandler     *handlers.ServiceZoneHandler
+	SeriesHandler   *handlers.BookingSeriesHandler
+	AddOnHandler    *handlers.AddOnHandler
+	RatingHandler   *handlers.RatingHandler
+	PostRideHandler *handlers.PostRideHandler
+	ReceiptHandler  *handlers.ReceiptHandler
+	EarningsHandler *handlers.EarningsHandler
+	OrgHandler      *handlers.OrganizationHandler
+	EventHandler    *handlers.EventHandler
+	TrackingHandler *handlers.TrackingHandler
+	SMSHandler      *handlers.SMSHandler
+	JobHandler      *handlers.JobHandler
+	NotifyHandler   *handlers.NotificationHandler
+	WebhookHandler  *handlers.WebhookHandler
+	APIKeyHandler   *handlers.APIKeyHandler
 }
 
 // initializeDatabase sets up database connection and runs migrations
@@ -149,16 +207,247 @@ func initializeServices(db *sqlx.DB, cfg *config.Config, log *logger.Logger) (*S
 		log.Warn("Please configure MAILERSEND_API_KEY and MAILERSEND_FROM_EMAIL in .env file")
 	}
 
+	// Initialize text messaging
+	smsService, err := initializeSMS(db, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize the notification dispatcher; every email and text goes through it
+	notifier := notify.NewDispatcher(postgres.NewNotificationPreferenceRepository(db), userRepo, emailService, smsService, log)
+
+	// Initialize booking event hub; every booking change made through bookRideRepo is published
+	eventHub := observer.NewHub(postgres.NewBookingEventRepository(db), cfg.DatabaseURL, time.Duration(cfg.EventRetentionHours)*time.Hour, log)
+	bookRideRepo := observer.ObserveRides(postgres.NewBookRideRepository(db), eventHub)
+
+	// Initialize outbound webhooks; organization booking events are queued for partner endpoints
+	webhookService := webhooks.NewService(postgres.NewWebhookRepository(db), cfg.WebhookDisableAfterFailures, cfg.WebhookAllowPrivateTargets, log)
+	eventHub.AddSink(webhookService)
+
+	// Initialize payment service
+	paymentService, err := initializePayments(db, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize geocoder
+	geocoder, err := initializeGeocoder(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize flight tracker
+	flightTracker, err := initializeFlightTracker(cfg, bookRideRepo, postgres.NewServiceZoneRepository(db), notifier, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize recurring booking generator
+	zoneResolver := zones.NewResolver(postgres.NewServiceZoneRepository(db), float64(cfg.CrossZoneApprovalKm))
+	seriesGenerator := recurrence.NewGenerator(postgres.NewBookingSeriesRepository(db), bookRideRepo, zoneResolver,
+		pricing.NewCalculator(models.DefaultCurrency), paymentService, log, cfg.SeriesHorizonDays)
+
+	// Initialize driver earnings ledger
+	ledgerService := ledger.NewService(postgres.NewLedgerRepository(db), cfg.DriverCommissionPercent, log)
+
+	// Initialize corporate accounts and their monthly invoicing
+	orgService := organizations.NewService(postgres.NewOrganizationRepository(db), log)
+
+	// Initialize live driver tracking
+	tracker := tracking.NewService(postgres.NewDriverLocationRepository(db), log, time.Duration(cfg.LocationPingIntervalSeconds)*time.Second, time.Duration(cfg.LocationRetentionHours)*time.Hour)
+
+	// Initialize Idempotency-Key handling for retried requests
+	idempotencyService := idempotency.NewService(postgres.NewIdempotencyKeyRepository(db), time.Duration(cfg.IdempotencyKeyTTLHours)*time.Hour, log)
+
+	// Initialize scheduled jobs
+	jobScheduler, err := initializeScheduler(db, cfg, bookRideRepo, userRepo, notifier, log)
+	if err != nil {
+		return nil, err
+	}
+	addServiceJobs(jobScheduler, cfg, flightTracker, seriesGenerator, ledgerService, orgService, tracker, idempotencyService)
+
+	// Initialize partner API keys; they authenticate through the same middleware as JWTs
+	apiKeyService := apikeys.NewService(postgres.NewAPIKeyRepository(db), postgres.NewOrganizationRepository(db), log)
+
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(authService, log)
+	authMiddleware := middleware.NewAuthMiddleware(authService, apiKeyService, log)
 
 	return &Services{
-		AuthService:    authService,
-		EmailService:   emailService,
-		AuthMiddleware: authMiddleware,
+		AuthService:     authService,
+		EmailService:    emailService,
+		PaymentService:  paymentService,
+		Geocoder:        geocoder,
+		FlightTracker:   flightTracker,
+		SeriesGenerator: seriesGenerator,
+		LedgerService:   ledgerService,
+		OrgService:      orgService,
+		EventHub:        eventHub,
+		Tracker:         tracker,
+		SMSService:      smsService,
+		Notifier:        notifier,
+		Scheduler:       jobScheduler,
+		Webhooks:        webhookService,
+		APIKeys:         apiKeyService,
+		Idempotency:     idempotencyService,
+		AuthMiddleware:  authMiddleware,
 	}, nil
 }
 
+// initializeFlightTracker selects the flight status provider configured by FLIGHT_STATUS_PROVIDER
+func initializeFlightTracker(cfg *config.Config, bookRideRepo repository.BookRideRepository, zoneRepo repository.ServiceZoneRepository, notifier *notify.Dispatcher, log *logger.Logger) (*flights.Tracker, error) {
+	var provider flights.FlightStatusProvider
+	switch cfg.FlightStatusProvider {
+	case flights.StubProviderName:
+		stub, err := flights.NewStubProvider(cfg.FlightStubFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Warn("Using stub flight status provider: " + cfg.FlightStubFile)
+		provider = stub
+	default:
+		return nil, fmt.Errorf("unsupported flight status provider: %s", cfg.FlightStatusProvider)
+	}
+
+	return flights.NewTracker(provider, bookRideRepo, zoneRepo, notifier, log), nil
+}
+
+// initializeSMS selects the SMS provider configured by SMS_PROVIDER
+func initializeSMS(db *sqlx.DB, cfg *config.Config, log *logger.Logger) (*notify.Service, error) {
+	var provider notify.SMSProvider
+	switch cfg.SMSProvider {
+	case notify.StubProviderName:
+		log.Warn("Using stub SMS provider - no text messages will be delivered")
+		provider = notify.NewStubProvider(cfg.SMSStubFile)
+	case notify.HTTPProviderName:
+		if cfg.SMSAPIURL == "" {
+			return nil, errors.New("SMS_API_URL is required for the http SMS provider")
+		}
+		log.Info("Using HTTP SMS provider: " + cfg.SMSAPIURL)
+		provider = notify.NewHTTPProvider(cfg.SMSAPIURL, cfg.SMSAPIKey, cfg.SMSFrom)
+	default:
+		return nil, fmt.Errorf("unsupported SMS provider: %s", cfg.SMSProvider)
+	}
+
+	return notify.NewService(provider, postgres.NewSMSOptOutRepository(db), cfg.SMSDefaultCountryCode, cfg.SMSWebhookSecret, log), nil
+}
+
+// initializeScheduler schedules the rider reminder, driver manifest and unassigned booking jobs
+func initializeScheduler(db *sqlx.DB, cfg *config.Config, bookRideRepo repository.BookRideRepository, userRepo repository.UserRepository, notifier *notify.Dispatcher, log *logger.Logger) (*scheduler.Scheduler, error) {
+	loc, err := time.LoadLocation(cfg.SchedulerTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduler time zone: %w", err)
+	}
+	manifestAt, err := time.Parse("15:04", cfg.DriverManifestTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid driver manifest time: %w", err)
+	}
+
+	reminderService := reminders.NewService(bookRideRepo, postgres.NewServiceZoneRepository(db), postgres.NewBookingNoticeRepository(db),
+		userRepo, notifier, log, reminders.Config{
+			ReminderOffsets:  cfg.ReminderOffsets,
+			UnassignedWindow: time.Duration(cfg.UnassignedAlertMinutes) * time.Minute,
+			DispatchEmail:    cfg.DispatchEmail,
+			ManifestLocation: loc,
+		})
+
+	jobScheduler := scheduler.New(postgres.NewJobLeaseRepository(db), log)
+	jobScheduler.Add(scheduler.Job{
+		Name:     "rider-reminders",
+		Schedule: scheduler.Every(5 * time.Minute),
+		Timeout:  4 * time.Minute,
+		Run:      reminderService.SendRiderReminders,
+	})
+	jobScheduler.Add(scheduler.Job{
+		Name:     "unassigned-alerts",
+		Schedule: scheduler.Every(5 * time.Minute),
+		Timeout:  4 * time.Minute,
+		Run:      reminderService.AlertUnassigned,
+	})
+	jobScheduler.Add(scheduler.Job{
+		Name:     "driver-manifests",
+		Schedule: scheduler.Daily(manifestAt.Hour(), manifestAt.Minute(), loc),
+		Timeout:  15 * time.Minute,
+		Run:      reminderService.SendDriverManifests,
+	})
+	return jobScheduler, nil
+}
+
+// addServiceJobs schedules the periodic work of the other services, so each run happens on one instance
+func addServiceJobs(jobScheduler *scheduler.Scheduler, cfg *config.Config, flightTracker *flights.Tracker, seriesGenerator *recurrence.Generator, ledgerService *ledger.Service, orgService *organizations.Service, tracker *tracking.Service, idempotencyService *idempotency.Service) {
+	flightInterval := time.Duration(cfg.FlightCheckIntervalMinutes) * time.Minute
+	jobScheduler.Add(scheduler.Job{
+		Name:     "flight-status",
+		Schedule: scheduler.Every(flightInterval),
+		Timeout:  max(flightInterval, 5*time.Minute),
+		Run:      flightTracker.CheckAll,
+	})
+	seriesInterval := time.Duration(cfg.SeriesGenerationIntervalMinutes) * time.Minute
+	jobScheduler.Add(scheduler.Job{
+		Name:     "series-generation",
+		Schedule: scheduler.Every(seriesInterval),
+		Timeout:  max(seriesInterval, 15*time.Minute),
+		Run:      seriesGenerator.GenerateAll,
+	})
+	jobScheduler.Add(scheduler.Job{
+		Name:     "payout-batches",
+		Schedule: scheduler.Every(time.Hour),
+		Timeout:  15 * time.Minute,
+		Run:      ledgerService.GenerateDueBatch,
+	})
+	jobScheduler.Add(scheduler.Job{
+		Name:     "organization-invoices",
+		Schedule: scheduler.Every(time.Hour),
+		Timeout:  15 * time.Minute,
+		Run:      orgService.InvoiceLastMonth,
+	})
+	jobScheduler.Add(scheduler.Job{
+		Name:     "driver-location-cleanup",
+		Schedule: scheduler.Every(time.Hour),
+		Timeout:  5 * time.Minute,
+		Run:      tracker.Cleanup,
+	})
+	jobScheduler.Add(scheduler.Job{
+		Name:     "idempotency-key-cleanup",
+		Schedule: scheduler.Every(time.Hour),
+		Timeout:  5 * time.Minute,
+		Run:      idempotencyService.DeleteExpired,
+	})
+}
+
+// initializeGeocoder selects the geocoder configured by GEOCODER_PROVIDER
+func initializeGeocoder(cfg *config.Config, log *logger.Logger) (geo.Geocoder, error) {
+	switch cfg.GeocoderProvider {
+	case "gazetteer":
+		gazetteer, err := geo.NewGazetteer(cfg.GeocoderGazetteerFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("Using offline gazetteer geocoder: " + cfg.GeocoderGazetteerFile)
+		return gazetteer, nil
+	case "http":
+		log.Info("Using HTTP geocoder: " + cfg.GeocoderURL)
+		return geo.NewHTTPGeocoder(cfg.GeocoderURL, cfg.GeocoderAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported geocoder provider: %s", cfg.GeocoderProvider)
+	}
+}
+
+// initializePayments selects the payment gateway configured by PAYMENT_PROVIDER
+func initializePayments(db *sqlx.DB, cfg *config.Config, log *logger.Logger) (*payments.Service, error) {
+	paymentRepo := postgres.NewPaymentRepository(db)
+
+	switch cfg.PaymentProvider {
+	case payments.FakeProviderName:
+		gateway := payments.NewFakeGateway(paymentRepo)
+		paymentService := payments.NewService(gateway, paymentRepo, cfg.PaymentWebhookSecret, log)
+		gateway.SetEventHandler(paymentService.HandleEvent)
+		log.Warn("Using fake payment gateway - no real payments will be processed")
+		return paymentService, nil
+	default:
+		return nil, fmt.Errorf("unsupported payment provider: %s", cfg.PaymentProvider)
+	}
+}
+
 // initializeHandlers creates all handlers
 func initializeHandlers(services *Services, log *logger.Logger) *Handlers {
 	// Get database connection for repositories
@@ -176,13 +465,37 @@ func initializeHandlers(services *Services, log *logger.Logger) *Handlers {
 	}
 	
 	userRepo := postgres.NewUserRepository(db)
-	bookRideRepo := postgres.NewBookRideRepository(db)
+	bookRideRepo := observer.ObserveRides(postgres.NewBookRideRepository(db), services.EventHub)
+	policyRepo := postgres.NewCancellationPolicyRepository(db)
+	zoneRepo := postgres.NewServiceZoneRepository(db)
+	addOnRepo := postgres.NewAddOnRepository(db)
+	legalEntityRepo := postgres.NewLegalEntityRepository(db)
+	calculator := pricing.NewCalculator(models.DefaultCurrency)
+	receiptService := receipts.NewService(postgres.NewReceiptRepository(db), legalEntityRepo, postgres.NewPaymentRepository(db), log)
+	bookRideHandler := handlers.NewBookRideHandler(bookRideRepo, log, services.AuthService, services.Notifier, calculator, services.PaymentService, policyRepo, zones.NewResolver(zoneRepo, float64(cfg.CrossZoneApprovalKm)), services.Geocoder, addOnRepo, receiptService, services.LedgerService, services.OrgService, cfg.SMSDefaultCountryCode)
 
 	return &Handlers{
-		AuthHandler:     handlers.NewAuthHandler(services.AuthService, services.EmailService, log),
+		AuthHandler:     handlers.NewAuthHandler(services.AuthService, services.Notifier, log),
 		UserHandler:     handlers.NewUserHandler(services.AuthService, userRepo, log),
-		PasswordHandler: handlers.NewPasswordHandler(services.AuthService, userRepo, services.EmailService, log),
-		BookRideHandler: handlers.NewBookRideHandler(bookRideRepo, log, services.AuthService, services.EmailService),
+		PasswordHandler: handlers.NewPasswordHandler(services.AuthService, userRepo, services.Notifier, log),
+		BookRideHandler: bookRideHandler,
+		PaymentHandler:  handlers.NewPaymentHandler(services.PaymentService, bookRideRepo, services.LedgerService, log),
+		PolicyHandler:   handlers.NewCancellationPolicyHandler(policyRepo, log),
+		ZoneHandler:     handlers.NewServiceZoneHandler(zoneRepo, log),
+		SeriesHandler:   handlers.NewBookingSeriesHandler(postgres.NewBookingSeriesRepository(db), bookRideHandler, services.SeriesGenerator, log),
+		AddOnHandler:    handlers.NewAddOnHandler(addOnRepo, log),
+		RatingHandler:   handlers.NewRatingHandler(postgres.NewRatingRepository(db), bookRideRepo, userRepo, services.AuthService, log, cfg.LowRatingThreshold),
+		PostRideHandler: handlers.NewPostRideHandler(postgres.NewPostRideAdjustmentRepository(db), bookRideRepo, services.PaymentService, calculator, services.Notifier, services.AuthService, log, models.Money(cfg.PostRideApprovalThreshold), services.LedgerService),
+		ReceiptHandler:  handlers.NewReceiptHandler(receiptService, legalEntityRepo, bookRideRepo, services.AuthService, log),
+		EarningsHandler: handlers.NewEarningsHandler(services.LedgerService, postgres.NewLedgerRepository(db), log),
+		EventHandler:    handlers.NewEventHandler(services.EventHub, log),
+		OrgHandler:      handlers.NewOrganizationHandler(postgres.NewOrganizationRepository(db), bookRideRepo, userRepo, services.OrgService, services.Notifier, log),
+		TrackingHandler: handlers.NewTrackingHandler(services.Tracker, bookRideRepo, services.AuthService, services.Notifier, log),
+		SMSHandler:      handlers.NewSMSHandler(services.SMSService, log),
+		JobHandler:      handlers.NewJobHandler(services.Scheduler, log),
+		NotifyHandler:   handlers.NewNotificationHandler(services.Notifier, log),
+		WebhookHandler:  handlers.NewWebhookHandler(services.Webhooks, postgres.NewOrganizationRepository(db), log),
+		APIKeyHandler:   handlers.NewAPIKeyHandler(services.APIKeys, log),
 	}
 }
 
@@ -195,10 +508,46 @@ func setupAllRoutes(e *echo.Echo, handlers *Handlers, authMiddleware *middleware
 	routes.SetupAuthRoutes(e, handlers.AuthHandler, handlers.PasswordHandler, authMiddleware, middlewareConfig.AuthRateLimiter)
 
 	// Admin routes
-	routes.SetupAdminRoutes(e, handlers.AuthHandler, handlers.UserHandler, authMiddleware)
+	routes.SetupAdminRoutes(e, handlers.AuthHandler, handlers.UserHandler, handlers.BookRideHandler, handlers.PaymentHandler, handlers.PolicyHandler, handlers.ZoneHandler, handlers.AddOnHandler, authMiddleware, middlewareConfig.Idempotency)
 
 	// Booking routes
-	routes.SetupBookingRoutes(e, handlers.BookRideHandler, authMiddleware)
+	routes.SetupBookingRoutes(e, handlers.BookRideHandler, handlers.SeriesHandler, handlers.AddOnHandler, authMiddleware, middlewareConfig.Idempotency)
+
+	// Rating routes
+	routes.SetupRatingRoutes(e, handlers.RatingHandler, authMiddleware)
+
+	// Post-ride adjustment routes
+	routes.SetupPostRideRoutes(e, handlers.PostRideHandler, authMiddleware, middlewareConfig.Idempotency)
+
+	// Receipt routes
+	routes.SetupReceiptRoutes(e, handlers.ReceiptHandler, authMiddleware)
+
+	// Driver earnings and payout routes
+	routes.SetupEarningsRoutes(e, handlers.EarningsHandler, authMiddleware)
+
+	// Corporate account routes
+	routes.SetupOrganizationRoutes(e, handlers.OrgHandler, authMiddleware)
+
+	// Real-time booking event routes
+	routes.SetupEventRoutes(e, handlers.EventHandler, authMiddleware)
+
+	// Live driver location routes
+	routes.SetupTrackingRoutes(e, handlers.TrackingHandler, authMiddleware)
+
+	// SMS reply and opt-out routes
+	routes.SetupSMSRoutes(e, handlers.SMSHandler, authMiddleware)
+
+	// Scheduled job routes
+	routes.SetupJobRoutes(e, handlers.JobHandler, authMiddleware)
+
+	// Notification preference routes
+	routes.SetupNotificationRoutes(e, handlers.NotifyHandler, authMiddleware)
+
+	// Outbound webhook routes
+	routes.SetupWebhookRoutes(e, handlers.WebhookHandler, authMiddleware)
+
+	// Partner API key routes
+	routes.SetupAPIKeyRoutes(e, handlers.APIKeyHandler, authMiddleware)
 }
 
 // logAvailableEndpoints logs all available API endpoints
@@ -216,6 +565,8 @@ func logAvailableEndpoints(log *logger.Logger) {
 	log.Info("  POST /auth/reset-password")
 	log.Info("  GET  /users/me (protected)")
 	log.Info("  PUT  /users/me/password (protected)")
+	log.Info("  GET  /users/me/notification-preferences (protected)")
+	log.Info("  PUT  /users/me/notification-preferences (protected)")
 	
 	// Admin endpoints
 	log.Info("  GET  /admin/users (admin only)")
@@ -223,15 +574,113 @@ func logAvailableEndpoints(log *logger.Logger) {
 	log.Info("  GET  /admin/users/:id (admin only)")
 	log.Info("  PUT  /admin/users/:id/role (admin only)")
 	log.Info("  DELETE /admin/users/:id (admin only)")
+	log.Info("  PUT  /admin/bookings/:id/fare (admin only)")
+	log.Info("  GET  /admin/bookings/:id/fare-adjustments (admin only)")
+	log.Info("  GET  /admin/bookings/pending-approval (admin only)")
+	log.Info("  PUT  /admin/bookings/:id/approve (admin only)")
+	log.Info("  GET  /admin/bookings/:id/payments (admin only)")
+	log.Info("  POST /admin/bookings/:id/refund (admin only)")
+	log.Info("  GET  /admin/bookings/:id/refunds (admin only)")
+	log.Info("  GET  /admin/bookings/payment-issues (admin only)")
+	log.Info("  PUT  /admin/bookings/:id/payment-issue/resolve (admin only)")
+	log.Info("  GET  /admin/cancellation-policies (admin only)")
+	log.Info("  PUT  /admin/cancellation-policies/:ride_type (admin only)")
+	log.Info("  DELETE /admin/cancellation-policies/:ride_type (admin only)")
+	log.Info("  GET  /admin/service-zones (admin only)")
+	log.Info("  GET  /admin/service-zones/:code (admin only)")
+	log.Info("  PUT  /admin/service-zones/:code (admin only)")
+	log.Info("  DELETE /admin/service-zones/:code (admin only)")
+	log.Info("  GET  /admin/ratings/flagged (admin only)")
+	log.Info("  PUT  /admin/ratings/:id/review (admin only)")
+	log.Info("  GET  /admin/users/:id/ratings (admin only)")
+	log.Info("  GET  /admin/bookings/:id/adjustments (admin only)")
+	log.Info("  PUT  /admin/bookings/:id/adjustments/:adjustmentId/approve (admin only)")
+	log.Info("  PUT  /admin/bookings/:id/adjustments/:adjustmentId/reject (admin only)")
+	log.Info("  GET  /admin/legal-entities (admin only)")
+	log.Info("  PUT  /admin/legal-entities/:code (admin only)")
+	log.Info("  POST /admin/payouts/batches (admin only)")
+	log.Info("  GET  /admin/payouts/batches (admin only)")
+	log.Info("  GET  /admin/payouts/batches/:id (admin only)")
+	log.Info("  GET  /admin/payouts/batches/:id/export (admin only)")
+	log.Info("  PUT  /admin/payouts/:id/paid (admin only)")
+	log.Info("  PUT  /admin/payouts/:id/failed (admin only)")
+	log.Info("  POST /admin/organizations (admin only)")
+	log.Info("  GET  /admin/organizations (admin only)")
+	log.Info("  PUT  /admin/organizations/:id (admin only)")
+	log.Info("  POST /admin/organizations/:id/invoices (admin only)")
+	log.Info("  GET  /admin/add-ons (admin only)")
+	log.Info("  PUT  /admin/add-ons/:code (admin only)")
+	log.Info("  DELETE /admin/add-ons/:code (admin only)")
+	log.Info("  GET  /admin/sms/opt-outs (admin only)")
+	log.Info("  POST /admin/sms/opt-outs (admin only)")
+	log.Info("  DELETE /admin/sms/opt-outs/:phone (admin only)")
+	log.Info("  GET  /admin/jobs (admin only)")
+	log.Info("  GET  /admin/api-keys[?organization_id=] (admin only)")
+	log.Info("  POST /admin/api-keys (admin only)")
+	log.Info("  PUT  /admin/api-keys/:id (admin only)")
+	log.Info("  POST /admin/api-keys/:id/rotate (admin only)")
+	log.Info("  DELETE /admin/api-keys/:id (admin only)")
 	
 	// Booking endpoints
 	log.Info("  POST /book-ride (public)")
+	log.Info("  GET  /add-ons[?ride_type=] (public)")
 	log.Info("  GET  /bookings/email/:email (public)")
 	log.Info("  POST /bookings/:id/update-link (public)")
 	log.Info("  GET  /bookings/my (protected)")
 	log.Info("  PUT  /bookings/:id (protected/token)")
-	log.Info("  DELETE /bookings/:id/cancel (protected/token)")
+	log.Info("  DELETE /bookings/:id/cancel[?preview=true] (protected/token)")
+	log.Info("  POST /bookings/series (protected)")
+	log.Info("  GET  /bookings/series (protected)")
+	log.Info("  GET  /bookings/series/:id (protected)")
+	log.Info("  PUT  /bookings/series/:id (protected)")
+	log.Info("  DELETE /bookings/series/:id/cancel (protected)")
+	log.Info("  POST /bookings/:id/rating (protected/token)")
+	log.Info("  POST /bookings/:id/tip (protected/token)")
+	log.Info("  GET  /bookings/:id/adjustments (protected/token)")
+	log.Info("  PUT  /bookings/:id/adjustments/:adjustmentId/approve (protected/token)")
+	log.Info("  PUT  /bookings/:id/adjustments/:adjustmentId/reject (protected/token)")
+	log.Info("  GET  /bookings/:id/receipt[?format=pdf] (protected/token)")
+	log.Info("  GET  /bookings/:id/tracking (protected/token)")
+	log.Info("  GET  /drivers/:id/profile (public)")
+	log.Info("  POST /driver/bookings/:id/rating (driver only)")
+	log.Info("  GET  /driver/bookings (driver only)")
+	log.Info("  GET  /driver/bookings/:id (driver only)")
 	log.Info("  PUT  /driver/bookings/:id/accept (driver only)")
+	log.Info("  PUT  /driver/bookings/:id/start (driver only)")
+	log.Info("  POST /driver/bookings/:id/locations (driver only)")
+	log.Info("  PUT  /driver/bookings/:id/arriving (driver only)")
+	log.Info("  PUT  /driver/bookings/:id/complete (driver only)")
+	log.Info("  POST /driver/bookings/:id/extras (driver only)")
+	log.Info("  GET  /driver/earnings[?from=&to=] (driver only)")
+	log.Info("  GET  /driver/payouts (driver only)")
+	log.Info("  GET  /organizations/mine (protected)")
+	log.Info("  GET  /organizations/:id (org admin)")
+	log.Info("  PUT  /organizations/:id/approval-rules (org admin)")
+	log.Info("  GET  /organizations/:id/members (org admin)")
+	log.Info("  POST /organizations/:id/members (org admin)")
+	log.Info("  PUT  /organizations/:id/members/:userId (org admin)")
+	log.Info("  DELETE /organizations/:id/members/:userId (org admin)")
+	log.Info("  GET  /organizations/:id/cost-centers (org admin)")
+	log.Info("  PUT  /organizations/:id/cost-centers/:code (org admin)")
+	log.Info("  GET  /organizations/:id/bookings[?approval=] (org admin)")
+	log.Info("  PUT  /organizations/:id/bookings/:bookingId/approve (org admin)")
+	log.Info("  PUT  /organizations/:id/bookings/:bookingId/reject (org admin)")
+	log.Info("  GET  /organizations/:id/invoices (org admin)")
+	log.Info("  GET  /organizations/:id/invoices/:invoiceId[?format=csv] (org admin)")
+	log.Info("  GET  /organizations/:id/webhooks (org admin)")
+	log.Info("  POST /organizations/:id/webhooks (org admin)")
+	log.Info("  PUT  /organizations/:id/webhooks/:webhookId (org admin)")
+	log.Info("  DELETE /organizations/:id/webhooks/:webhookId (org admin)")
+	log.Info("  GET  /organizations/:id/webhooks/:webhookId/deliveries[?status=&limit=] (org admin)")
+	log.Info("  POST /organizations/:id/webhooks/:webhookId/deliveries/:deliveryId/replay (org admin)")
+
+	// Event stream endpoints
+	log.Info("  GET  /events (protected, Server-Sent Events)")
+	log.Info("  GET  /events/ws (protected, WebSocket)")
+
+	// Payment endpoints
+	log.Info("  POST /payments/webhook (signed)")
+	log.Info("  POST /sms/inbound (signed)")
 }
 
 // GooseLogger adapts your logger to Goose's logger interface
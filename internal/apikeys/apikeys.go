@@ -0,0 +1,240 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// prefixLength is how many hex characters after APIKeyPrefix identify a key
+	prefixLength = 12
+	// secretBytes is the length of the random part of a key
+	secretBytes = 32
+	// DefaultGracePeriod is how long a rotated key keeps working
+	DefaultGracePeriod = 24 * time.Hour
+	// MaxGracePeriod is the longest a rotated key may keep working
+	MaxGracePeriod = 7 * 24 * time.Hour
+	// forgetLimitersAfter is how long an unused key's rate limiter is kept. A limiter refills within
+	// a minute, so dropping an idle one loses nothing.
+	forgetLimitersAfter = 10 * time.Minute
+	// touchEvery is how often a key's last use is written back; busy keys would otherwise write on every request
+	touchEvery = time.Minute
+)
+
+var (
+	ErrInvalidKey = errors.New("invalid API key")
+	ErrKeyExpired = errors.New("API key expired")
+	ErrKeyRevoked = errors.New("API key revoked")
+	ErrNotMember  = errors.New("API key user is not a member of its organization")
+)
+
+// Service issues partner API keys and authenticates requests made with them. Keys are stored as
+// SHA-256 hashes and found by their prefix. Each key's rate limit is enforced per instance.
+type Service struct {
+	repo   repository.APIKeyRepository
+	orgs   repository.OrganizationRepository
+	logger *logger.Logger
+
+	mu        sync.Mutex
+	limiters  map[int64]*keyLimiter
+	lastSweep time.Time
+}
+
+// keyLimiter is a key's rate limiter and when the key last used it
+type keyLimiter struct {
+	*rate.Limiter
+	lastUsed time.Time
+}
+
+func NewService(repo repository.APIKeyRepository, orgs repository.OrganizationRepository, logger *logger.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		orgs:     orgs,
+		logger:   logger,
+		limiters: make(map[int64]*keyLimiter),
+	}
+}
+
+// generate returns a new key and fills in the stored prefix and hash
+func generate(key *models.APIKey) (string, error) {
+	b := make([]byte, prefixLength/2+secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	key.Prefix = models.APIKeyPrefix + hex.EncodeToString(b[:prefixLength/2])
+	token := key.Prefix + "_" + hex.EncodeToString(b[prefixLength/2:])
+	key.KeyHash = hash(token)
+	return token, nil
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue creates a key for a member of an organization and returns it with the key itself, which
+// is not stored and cannot be shown again
+func (s *Service) Issue(ctx context.Context, req *models.CreateAPIKeyRequest, issuedBy int64) (*models.APIKey, string, error) {
+	member, err := s.orgs.GetMemberByUserID(ctx, req.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrNotMember
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if member.OrganizationID != req.OrganizationID {
+		return nil, "", ErrNotMember
+	}
+
+	key := &models.APIKey{
+		OrganizationID:     req.OrganizationID,
+		UserID:             req.UserID,
+		Name:               req.Name,
+		Scopes:             req.Scopes,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+		ExpiresAt:          req.ExpiresAt,
+		CreatedBy:          &issuedBy,
+	}
+	token, err := generate(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, token, nil
+}
+
+// Rotate replaces a key with a new one with the same settings. The old key keeps working for the
+// grace period, or until its own expiry if sooner.
+func (s *Service) Rotate(ctx context.Context, id int64, grace time.Duration, rotatedBy int64) (*models.APIKey, string, error) {
+	old, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if old.RevokedAt != nil {
+		return nil, "", ErrKeyRevoked
+	}
+
+	replacement := &models.APIKey{
+		OrganizationID:     old.OrganizationID,
+		UserID:             old.UserID,
+		Name:               old.Name,
+		Scopes:             old.Scopes,
+		RateLimitPerMinute: old.RateLimitPerMinute,
+		ExpiresAt:          old.ExpiresAt,
+		CreatedBy:          &rotatedBy,
+	}
+	token, err := generate(replacement)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.repo.Rotate(ctx, old.ID, replacement, time.Now().Add(grace)); err != nil {
+		return nil, "", err
+	}
+	return replacement, token, nil
+}
+
+// Authenticate returns the key presented with a request and the user it acts as
+func (s *Service) Authenticate(ctx context.Context, token string) (*models.APIKeyPrincipal, error) {
+	// lxk_<prefix>_<secret>
+	if len(token) <= len(models.APIKeyPrefix)+prefixLength+1 || token[len(models.APIKeyPrefix)+prefixLength] != '_' {
+		return nil, ErrInvalidKey
+	}
+	prefix := token[:len(models.APIKeyPrefix)+prefixLength]
+	principal, err := s.repo.GetPrincipal(ctx, prefix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hash(token)), []byte(principal.KeyHash)) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	switch {
+	case principal.RevokedAt != nil:
+		return nil, ErrKeyRevoked
+	case !principal.Usable(time.Now()):
+		return nil, ErrKeyExpired
+	case principal.MemberOrganizationID == nil || *principal.MemberOrganizationID != principal.OrganizationID:
+		return nil, ErrNotMember
+	}
+
+	if principal.LastUsedAt == nil || time.Since(*principal.LastUsedAt) >= touchEvery {
+		if err := s.repo.TouchLastUsed(ctx, principal.ID); err != nil {
+			s.logger.Warn(fmt.Sprintf("Failed to record use of API key %s: %s", principal.Prefix, err.Error()))
+		}
+	}
+	return principal, nil
+}
+
+// Allow reports whether a key is within its rate limit. A key may use its whole minute's allowance
+// at once; it refills evenly over the minute.
+func (s *Service) Allow(key *models.APIKey) bool {
+	now := time.Now()
+	s.mu.Lock()
+	limiter, ok := s.limiters[key.ID]
+	if !ok || limiter.Burst() != key.RateLimitPerMinute {
+		limiter = &keyLimiter{Limiter: rate.NewLimiter(rate.Limit(float64(key.RateLimitPerMinute)/60), key.RateLimitPerMinute)}
+		s.limiters[key.ID] = limiter
+	}
+	limiter.lastUsed = now
+
+	if now.Sub(s.lastSweep) > forgetLimitersAfter {
+		for id, l := range s.limiters {
+			if now.Sub(l.lastUsed) > forgetLimitersAfter {
+				delete(s.limiters, id)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.mu.Unlock()
+	return limiter.AllowN(now, 1)
+}
+
+// Get returns a key by ID
+func (s *Service) Get(ctx context.Context, id int64) (*models.APIKey, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List returns the keys of an organization, or of every organization when orgID is 0
+func (s *Service) List(ctx context.Context, orgID int64) ([]*models.APIKey, error) {
+	return s.repo.List(ctx, orgID)
+}
+
+// Update replaces a key's name, scopes, rate limit and expiry
+func (s *Service) Update(ctx context.Context, key *models.APIKey) error {
+	return s.repo.Update(ctx, key)
+}
+
+// Revoke stops a key from working immediately
+func (s *Service) Revoke(ctx context.Context, id int64) error {
+	if err := s.repo.Revoke(ctx, id); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.limiters, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// IsKey reports whether a bearer token is an API key rather than a JWT
+func IsKey(token string) bool {
+	return strings.HasPrefix(token, models.APIKeyPrefix)
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/joho/godotenv"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -20,6 +22,71 @@ type Config struct {
 	MailerSendAPIKey    string
 	MailerSendFromEmail string
 	MailerSendFromName  string
+
+	// Payment configuration
+	PaymentProvider      string
+	PaymentWebhookSecret string
+
+	// Geocoding configuration
+	GeocoderProvider      string
+	GeocoderGazetteerFile string
+	GeocoderURL           string
+	GeocoderAPIKey        string
+
+	// Service area configuration
+	CrossZoneApprovalKm int
+
+	// SMS configuration
+	SMSProvider           string
+	SMSStubFile           string
+	SMSAPIURL             string
+	SMSAPIKey             string
+	SMSFrom               string
+	SMSDefaultCountryCode string
+	SMSWebhookSecret      string
+
+	// Flight tracking configuration
+	FlightStatusProvider       string
+	FlightStubFile             string
+	FlightCheckIntervalMinutes int
+
+	// Recurring booking configuration
+	SeriesHorizonDays               int
+	SeriesGenerationIntervalMinutes int
+
+	// Ratings at or below this score are flagged for admin review
+	LowRatingThreshold int
+
+	// Driver extras above this total (minor units) need rider or admin approval
+	PostRideApprovalThreshold int64
+
+	// Share of fares kept by the platform, in percent
+	DriverCommissionPercent int
+
+	// Booking events older than this are no longer replayed to reconnecting streams
+	EventRetentionHours int
+
+	// Driver GPS pings older than this are deleted
+	LocationRetentionHours int
+
+	// Minimum time between a driver's location uploads
+	LocationPingIntervalSeconds int
+
+	// Scheduled jobs: rider reminders before pickup, the nightly driver manifest and
+	// alerts to dispatch about bookings still unassigned close to pickup
+	ReminderOffsets        []time.Duration
+	DriverManifestTime     string // HH:MM in SchedulerTimezone
+	SchedulerTimezone      string
+	UnassignedAlertMinutes int
+	DispatchEmail          string
+
+	// Webhook endpoints are disabled after this many failed delivery attempts in a row
+	WebhookDisableAfterFailures int
+	// Lets webhooks use http and reach localhost and private networks; for local development only
+	WebhookAllowPrivateTargets bool
+
+	// Responses stored for Idempotency-Key retries are kept this long
+	IdempotencyKeyTTLHours int
 }
 
 func LoadConfig(log *logger.Logger) (*Config, error) {
@@ -50,6 +117,177 @@ func LoadConfig(log *logger.Logger) (*Config, error) {
 	cfg.MailerSendFromEmail = getEnvWithDefault("MAILERSEND_FROM_EMAIL", "")
 	cfg.MailerSendFromName = getEnvWithDefault("MAILERSEND_FROM_NAME", "LuxSUV Support")
 
+	// Payment configuration
+	cfg.PaymentProvider = getEnvWithDefault("PAYMENT_PROVIDER", "fake")
+	cfg.PaymentWebhookSecret = getEnvWithDefault("PAYMENT_WEBHOOK_SECRET", "")
+
+	// Geocoding configuration
+	cfg.GeocoderProvider = getEnvWithDefault("GEOCODER_PROVIDER", "gazetteer")
+	cfg.GeocoderGazetteerFile = getEnvWithDefault("GEOCODER_GAZETTEER_FILE", "../../data/gazetteer.json")
+	cfg.GeocoderURL = getEnvWithDefault("GEOCODER_URL", "https://nominatim.openstreetmap.org")
+	cfg.GeocoderAPIKey = getEnvWithDefault("GEOCODER_API_KEY", "")
+
+	// Service area configuration
+	approvalKmStr := getEnvWithDefault("CROSS_ZONE_APPROVAL_KM", "50")
+	approvalKm, err := strconv.Atoi(approvalKmStr)
+	if err != nil || approvalKm < 0 {
+		log.Warn("Invalid CROSS_ZONE_APPROVAL_KM value, using default of 50")
+		approvalKm = 50
+	}
+	cfg.CrossZoneApprovalKm = approvalKm
+
+	// SMS configuration
+	cfg.SMSProvider = getEnvWithDefault("SMS_PROVIDER", "stub")
+	cfg.SMSStubFile = getEnvWithDefault("SMS_STUB_FILE", "")
+	cfg.SMSAPIURL = getEnvWithDefault("SMS_API_URL", "")
+	cfg.SMSAPIKey = getEnvWithDefault("SMS_API_KEY", "")
+	cfg.SMSFrom = getEnvWithDefault("SMS_FROM", "")
+	cfg.SMSWebhookSecret = getEnvWithDefault("SMS_WEBHOOK_SECRET", "")
+	countryCode := getEnvWithDefault("SMS_DEFAULT_COUNTRY_CODE", "1")
+	if code, err := strconv.Atoi(countryCode); err != nil || code < 1 || code > 999 || countryCode[0] == '0' {
+		log.Warn("Invalid SMS_DEFAULT_COUNTRY_CODE value, using default of 1")
+		countryCode = "1"
+	}
+	cfg.SMSDefaultCountryCode = countryCode
+
+	// Flight tracking configuration
+	cfg.FlightStatusProvider = getEnvWithDefault("FLIGHT_STATUS_PROVIDER", "stub")
+	cfg.FlightStubFile = getEnvWithDefault("FLIGHT_STUB_FILE", "../../data/flight_status.json")
+	intervalStr := getEnvWithDefault("FLIGHT_CHECK_INTERVAL_MINUTES", "5")
+	interval, err := strconv.Atoi(intervalStr)
+	if err != nil || interval <= 0 {
+		log.Warn("Invalid FLIGHT_CHECK_INTERVAL_MINUTES value, using default of 5")
+		interval = 5
+	}
+	cfg.FlightCheckIntervalMinutes = interval
+
+	// Recurring booking configuration
+	horizonStr := getEnvWithDefault("SERIES_HORIZON_DAYS", "14")
+	horizon, err := strconv.Atoi(horizonStr)
+	if err != nil || horizon <= 0 {
+		log.Warn("Invalid SERIES_HORIZON_DAYS value, using default of 14")
+		horizon = 14
+	}
+	cfg.SeriesHorizonDays = horizon
+	generationStr := getEnvWithDefault("SERIES_GENERATION_INTERVAL_MINUTES", "60")
+	generation, err := strconv.Atoi(generationStr)
+	if err != nil || generation <= 0 {
+		log.Warn("Invalid SERIES_GENERATION_INTERVAL_MINUTES value, using default of 60")
+		generation = 60
+	}
+	cfg.SeriesGenerationIntervalMinutes = generation
+
+	// Rating configuration
+	thresholdStr := getEnvWithDefault("LOW_RATING_THRESHOLD", "2")
+	threshold, err := strconv.Atoi(thresholdStr)
+	if err != nil || threshold < 0 || threshold > 5 {
+		log.Warn("Invalid LOW_RATING_THRESHOLD value, using default of 2")
+		threshold = 2
+	}
+	cfg.LowRatingThreshold = threshold
+
+	// Post-ride adjustment configuration
+	approvalStr := getEnvWithDefault("POST_RIDE_APPROVAL_THRESHOLD", "2500")
+	approval, err := strconv.ParseInt(approvalStr, 10, 64)
+	if err != nil || approval < 0 {
+		log.Warn("Invalid POST_RIDE_APPROVAL_THRESHOLD value, using default of 2500")
+		approval = 2500
+	}
+	cfg.PostRideApprovalThreshold = approval
+
+	// Driver ledger configuration
+	commissionStr := getEnvWithDefault("DRIVER_COMMISSION_PERCENT", "20")
+	commission, err := strconv.Atoi(commissionStr)
+	if err != nil || commission < 0 || commission > 100 {
+		log.Warn("Invalid DRIVER_COMMISSION_PERCENT value, using default of 20")
+		commission = 20
+	}
+	cfg.DriverCommissionPercent = commission
+
+	// Event stream configuration
+	retentionStr := getEnvWithDefault("EVENT_RETENTION_HOURS", "24")
+	retention, err := strconv.Atoi(retentionStr)
+	if err != nil || retention < 1 || retention > 720 {
+		log.Warn("Invalid EVENT_RETENTION_HOURS value, using default of 24")
+		retention = 24
+	}
+	cfg.EventRetentionHours = retention
+
+	locationRetentionStr := getEnvWithDefault("LOCATION_RETENTION_HOURS", "72")
+	locationRetention, err := strconv.Atoi(locationRetentionStr)
+	if err != nil || locationRetention < 1 || locationRetention > 2160 {
+		log.Warn("Invalid LOCATION_RETENTION_HOURS value, using default of 72")
+		locationRetention = 72
+	}
+	cfg.LocationRetentionHours = locationRetention
+
+	pingIntervalStr := getEnvWithDefault("LOCATION_PING_INTERVAL_SECONDS", "5")
+	pingInterval, err := strconv.Atoi(pingIntervalStr)
+	if err != nil || pingInterval < 0 || pingInterval > 300 {
+		log.Warn("Invalid LOCATION_PING_INTERVAL_SECONDS value, using default of 5")
+		pingInterval = 5
+	}
+	cfg.LocationPingIntervalSeconds = pingInterval
+
+	// Scheduled job configuration
+	offsetsStr := getEnvWithDefault("REMINDER_OFFSETS", "24h,2h")
+	for _, part := range strings.Split(offsetsStr, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		offset, err := time.ParseDuration(part)
+		if err != nil || offset < time.Minute || offset > 7*24*time.Hour {
+			log.Warn("Invalid REMINDER_OFFSETS value, using default of 24h,2h")
+			cfg.ReminderOffsets = []time.Duration{24 * time.Hour, 2 * time.Hour}
+			break
+		}
+		cfg.ReminderOffsets = append(cfg.ReminderOffsets, offset)
+	}
+
+	cfg.DriverManifestTime = getEnvWithDefault("DRIVER_MANIFEST_TIME", "20:00")
+	if _, err := time.Parse("15:04", cfg.DriverManifestTime); err != nil {
+		log.Warn("Invalid DRIVER_MANIFEST_TIME value, using default of 20:00")
+		cfg.DriverManifestTime = "20:00"
+	}
+
+	cfg.SchedulerTimezone = getEnvWithDefault("SCHEDULER_TIMEZONE", "UTC")
+	if _, err := time.LoadLocation(cfg.SchedulerTimezone); err != nil {
+		log.Warn("Invalid SCHEDULER_TIMEZONE value, using default of UTC")
+		cfg.SchedulerTimezone = "UTC"
+	}
+
+	unassignedStr := getEnvWithDefault("UNASSIGNED_ALERT_MINUTES", "120")
+	unassigned, err := strconv.Atoi(unassignedStr)
+	if err != nil || unassigned < 1 || unassigned > 2880 {
+		log.Warn("Invalid UNASSIGNED_ALERT_MINUTES value, using default of 120")
+		unassigned = 120
+	}
+	cfg.UnassignedAlertMinutes = unassigned
+	cfg.DispatchEmail = getEnvWithDefault("DISPATCH_EMAIL", "")
+
+	// Webhook configuration
+	disableAfterStr := getEnvWithDefault("WEBHOOK_DISABLE_AFTER_FAILURES", "20")
+	disableAfter, err := strconv.Atoi(disableAfterStr)
+	if err != nil || disableAfter < 1 || disableAfter > 1000 {
+		log.Warn("Invalid WEBHOOK_DISABLE_AFTER_FAILURES value, using default of 20")
+		disableAfter = 20
+	}
+	cfg.WebhookDisableAfterFailures = disableAfter
+	cfg.WebhookAllowPrivateTargets = getEnvWithDefault("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false") == "true"
+	if cfg.WebhookAllowPrivateTargets && cfg.Environment == "production" {
+		log.Warn("WEBHOOK_ALLOW_PRIVATE_TARGETS is ignored in production")
+		cfg.WebhookAllowPrivateTargets = false
+	}
+
+	// Idempotency key configuration
+	idempotencyTTLStr := getEnvWithDefault("IDEMPOTENCY_KEY_TTL_HOURS", "24")
+	idempotencyTTL, err := strconv.Atoi(idempotencyTTLStr)
+	if err != nil || idempotencyTTL < 1 || idempotencyTTL > 720 {
+		log.Warn("Invalid IDEMPOTENCY_KEY_TTL_HOURS value, using default of 24")
+		idempotencyTTL = 24
+	}
+	cfg.IdempotencyKeyTTLHours = idempotencyTTL
+
 	// Validate required fields
 	if cfg.DatabaseURL == "" {
 		log.Err("DATABASE_URL environment variable is required")
@@ -82,6 +320,12 @@ func LoadConfig(log *logger.Logger) (*Config, error) {
 		log.Warn("Email service not configured - MAILERSEND_API_KEY not set")
 	}
 
+	log.Info("Payment Provider: " + cfg.PaymentProvider)
+	log.Info("Geocoder Provider: " + cfg.GeocoderProvider)
+	if cfg.PaymentWebhookSecret == "" {
+		log.Warn("PAYMENT_WEBHOOK_SECRET not set - payment webhooks will be rejected")
+	}
+
 	return cfg, nil
 }
 
@@ -2,6 +2,7 @@ package email
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"github.com/diagnosis/luxsuv-v4/internal/logger"
 	"github.com/diagnosis/luxsuv-v4/internal/models"
@@ -485,6 +486,7 @@ func (s *Service) SendBookingUpdateEmail(to, updateToken string, booking *models
                     <span><strong>Pickup:</strong></span>
                     <span>%s</span>
                 </div>
+                %s
                 <div class="detail-row">
                     <span><strong>Dropoff:</strong></span>
                     <span>%s</span>
@@ -493,6 +495,7 @@ func (s *Service) SendBookingUpdateEmail(to, updateToken string, booking *models
                     <span><strong>Status:</strong></span>
                     <span>%s</span>
                 </div>
+                %s
             </div>
             
             <div style="text-align: center; margin: 30px 0;">
@@ -506,7 +509,7 @@ func (s *Service) SendBookingUpdateEmail(to, updateToken string, booking *models
             
             <div class="security-notice">
                 <p><strong>⏰ This link will expire in 24 hours</strong> for your security.</p>
-                <p><strong>⚠️ Important:</strong> Bookings can only be cancelled up to 24 hours before the scheduled time.</p>
+                <p><strong>⚠️ Important:</strong> Late cancellations may incur a fee under our cancellation policy. You will see any fee before confirming a cancellation.</p>
                 <p>If you didn't request this update link, please ignore this email.</p>
             </div>
         </div>
@@ -518,8 +521,8 @@ func (s *Service) SendBookingUpdateEmail(to, updateToken string, booking *models
     </div>
 </body>
 </html>
-	`, booking.YourName, booking.ID, booking.Date, booking.Time, booking.PickupLocation,
-		booking.DropoffLocation, booking.BookStatus, updateURL, updateURL, updateURL)
+	`, booking.YourName, booking.ID, booking.Date, booking.Time, booking.PickupLocation, stopRowsHTML(booking),
+		dropoffText(booking), booking.BookStatus, fareRowsHTML(booking), updateURL, updateURL, updateURL)
 
 	text := fmt.Sprintf(`
 Update Your LuxSUV Booking
@@ -532,29 +535,952 @@ Current Booking Details:
 - Booking ID: #%d
 - Date & Time: %s at %s
 - Pickup: %s
-- Dropoff: %s
+%s- Dropoff: %s
 - Status: %s
+%s
+Update your booking by visiting this link:
+%s
+
+This link will expire in 24 hours for your security.
+
+Important: Late cancellations may incur a fee under our cancellation policy. You will see any fee before confirming a cancellation.
+
+If you didn't request this update link, please ignore this email.
+
+---
+LuxSUV - Premium Ride Sharing
+This is an automated message, please do not reply.
+	`, booking.YourName, booking.ID, booking.Date, booking.Time, booking.PickupLocation, stopRowsText(booking),
+		dropoffText(booking), booking.BookStatus, fareRowsText(booking), updateURL)
+
+	return s.sendEmail(to, subject, html, text)
+}
+
+// SendRideCompletedEmail sends the rider a summary of the completed ride with the final fare,
+// attaching any given files such as the PDF receipt
+func (s *Service) SendRideCompletedEmail(to string, booking *models.BookRide, attachments ...Attachment) error {
+	subject := fmt.Sprintf("Your LuxSUV Ride #%d Is Complete", booking.ID)
+
+	html := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Ride Completed</title>
+    <style>
+        body { 
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif; 
+            line-height: 1.6; 
+            color: #333; 
+            margin: 0; 
+            padding: 0; 
+            background-color: #f8f9fa;
+        }
+        .container { 
+            max-width: 600px; 
+            margin: 40px auto; 
+            background: white; 
+            border-radius: 8px; 
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .header { 
+            background: linear-gradient(135deg, #48bb78 0%%, #38a169 100%%); 
+            color: white; 
+            padding: 40px 30px; 
+            text-align: center; 
+        }
+        .header h1 { 
+            margin: 0; 
+            font-size: 28px; 
+            font-weight: 600; 
+        }
+        .content { 
+            padding: 40px 30px; 
+        }
+        .booking-details {
+            background-color: #f7fafc;
+            border-radius: 8px;
+            padding: 20px;
+            margin: 20px 0;
+        }
+        .booking-details h3 {
+            color: #2d3748;
+            margin-top: 0;
+        }
+        .detail-row {
+            display: flex;
+            justify-content: space-between;
+            margin-bottom: 10px;
+            padding-bottom: 10px;
+            border-bottom: 1px solid #e2e8f0;
+        }
+        .detail-row:last-child {
+            border-bottom: none;
+            margin-bottom: 0;
+        }
+        .footer { 
+            background-color: #f8f9fa;
+            padding: 30px;
+            text-align: center;
+            font-size: 14px; 
+            color: #718096; 
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🚗 Thanks for Riding with LuxSUV</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p>Your ride has been completed. Here is your trip summary:</p>
+            
+            <div class="booking-details">
+                <h3>Trip Details</h3>
+                <div class="detail-row">
+                    <span><strong>Booking ID:</strong></span>
+                    <span>#%d</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>Date & Time:</strong></span>
+                    <span>%s at %s</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>Pickup:</strong></span>
+                    <span>%s</span>
+                </div>
+                %s
+                <div class="detail-row">
+                    <span><strong>Dropoff:</strong></span>
+                    <span>%s</span>
+                </div>
+                %s
+            </div>
+        </div>
+        <div class="footer">
+            <p><strong>LuxSUV - Premium Ride Sharing</strong></p>
+            <p>This is an automated message, please do not reply to this email.</p>
+            <p>If you need help, contact our support team.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, booking.YourName, booking.ID, booking.Date, booking.Time, booking.PickupLocation, stopRowsHTML(booking),
+		dropoffText(booking), fareRowsHTML(booking))
+
+	text := fmt.Sprintf(`
+Your LuxSUV Ride Is Complete
+
+Hello %s,
+
+Your ride has been completed. Here is your trip summary:
+
+- Booking ID: #%d
+- Date & Time: %s at %s
+- Pickup: %s
+%s- Dropoff: %s
+%s
+---
+LuxSUV - Premium Ride Sharing
+This is an automated message, please do not reply.
+	`, booking.YourName, booking.ID, booking.Date, booking.Time, booking.PickupLocation, stopRowsText(booking),
+		dropoffText(booking), fareRowsText(booking))
+
+	return s.sendEmail(to, subject, html, text, attachments...)
+}
+
+// SendAdjustmentReceiptEmail sends the rider a receipt for a tip or extra charged after a
+// completed ride, with the ride's updated breakdown
+func (s *Service) SendAdjustmentReceiptEmail(to string, booking *models.BookRide, item models.LineItem) error {
+	subject := fmt.Sprintf("Receipt for %s on LuxSUV Ride #%d", item.Description, booking.ID)
+
+	total := booking.QuotedAmount
+	if booking.FinalAmount != nil {
+		total = *booking.FinalAmount
+	}
+
+	html := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Receipt</title>
+    <style>
+        body { 
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif; 
+            line-height: 1.6; 
+            color: #333; 
+            margin: 0; 
+            padding: 0; 
+            background-color: #f8f9fa;
+        }
+        .container { 
+            max-width: 600px; 
+            margin: 40px auto; 
+            background: white; 
+            border-radius: 8px; 
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .header { 
+            background: linear-gradient(135deg, #48bb78 0%%, #38a169 100%%); 
+            color: white; 
+            padding: 40px 30px; 
+            text-align: center; 
+        }
+        .header h1 { 
+            margin: 0; 
+            font-size: 28px; 
+            font-weight: 600; 
+        }
+        .content { 
+            padding: 40px 30px; 
+        }
+        .booking-details {
+            background-color: #f7fafc;
+            border-radius: 8px;
+            padding: 20px;
+            margin: 20px 0;
+        }
+        .booking-details h3 {
+            color: #2d3748;
+            margin-top: 0;
+        }
+        .detail-row {
+            display: flex;
+            justify-content: space-between;
+            margin-bottom: 10px;
+            padding-bottom: 10px;
+            border-bottom: 1px solid #e2e8f0;
+        }
+        .detail-row:last-child {
+            border-bottom: none;
+            margin-bottom: 0;
+        }
+        .footer { 
+            background-color: #f8f9fa;
+            padding: 30px;
+            text-align: center;
+            font-size: 14px; 
+            color: #718096; 
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🧾 Your Receipt</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p>%s of %s was charged for your ride on %s at %s. Here is the updated breakdown:</p>
+            
+            <div class="booking-details">
+                <h3>Booking #%d</h3>
+                %s
+                <div class="detail-row">
+                    <span><strong>Total Charged:</strong></span>
+                    <span><strong>%s</strong></span>
+                </div>
+            </div>
+        </div>
+        <div class="footer">
+            <p><strong>LuxSUV - Premium Ride Sharing</strong></p>
+            <p>This is an automated message, please do not reply to this email.</p>
+            <p>If you need help, contact our support team.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, booking.YourName, item.Description, item.Amount.Format(booking.Currency), booking.Date, booking.Time, booking.ID,
+		lineItemRowsHTML(booking), total.Format(booking.Currency))
+
+	text := fmt.Sprintf(`
+Your LuxSUV Receipt
+
+Hello %s,
+
+%s of %s was charged for your ride on %s at %s. Here is the updated breakdown:
+
+Booking #%d
+%s- Total Charged: %s
+
+---
+LuxSUV - Premium Ride Sharing
+This is an automated message, please do not reply.
+	`, booking.YourName, item.Description, item.Amount.Format(booking.Currency), booking.Date, booking.Time, booking.ID,
+		lineItemRowsText(booking), total.Format(booking.Currency))
+
+	return s.sendEmail(to, subject, html, text)
+}
+
+// SendFlightDelayEmail tells a rider or driver that a delayed flight moved the pickup time
+func (s *Service) SendFlightDelayEmail(to, name string, booking *models.BookRide, previousDate, previousTime string) error {
+	subject := fmt.Sprintf("Pickup Time Updated for LuxSUV Booking #%d", booking.ID)
+
+	flight := booking.Flight
+	estimated := ""
+	if flight.EstimatedArrival != nil {
+		estimated = flight.EstimatedArrival.UTC().Format("2006-01-02 15:04 MST")
+	}
+
+	html := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Pickup Time Updated</title>
+    <style>
+        body { 
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif; 
+            line-height: 1.6; 
+            color: #333; 
+            margin: 0; 
+            padding: 0; 
+            background-color: #f8f9fa;
+        }
+        .container { 
+            max-width: 600px; 
+            margin: 40px auto; 
+            background: white; 
+            border-radius: 8px; 
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .header { 
+            background: linear-gradient(135deg, #ed8936 0%%, #dd6b20 100%%); 
+            color: white; 
+            padding: 40px 30px; 
+            text-align: center; 
+        }
+        .header h1 { 
+            margin: 0; 
+            font-size: 28px; 
+            font-weight: 600; 
+        }
+        .content { 
+            padding: 40px 30px; 
+        }
+        .booking-details {
+            background-color: #f7fafc;
+            border-radius: 8px;
+            padding: 20px;
+            margin: 20px 0;
+        }
+        .booking-details h3 {
+            color: #2d3748;
+            margin-top: 0;
+        }
+        .detail-row {
+            display: flex;
+            justify-content: space-between;
+            margin-bottom: 10px;
+            padding-bottom: 10px;
+            border-bottom: 1px solid #e2e8f0;
+        }
+        .detail-row:last-child {
+            border-bottom: none;
+            margin-bottom: 0;
+        }
+        .footer { 
+            background-color: #f8f9fa;
+            padding: 30px;
+            text-align: center;
+            font-size: 14px; 
+            color: #718096; 
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>✈️ Flight Delay</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p>Flight %s %s is running late, so we have moved the pickup to match its new arrival time.</p>
+            
+            <div class="booking-details">
+                <h3>Updated Pickup</h3>
+                <div class="detail-row">
+                    <span><strong>Booking ID:</strong></span>
+                    <span>#%d</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>Previous Pickup:</strong></span>
+                    <span>%s at %s</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>New Pickup:</strong></span>
+                    <span>%s at %s</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>Estimated Arrival:</strong></span>
+                    <span>%s</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>Pickup:</strong></span>
+                    <span>%s</span>
+                </div>
+            </div>
+        </div>
+        <div class="footer">
+            <p><strong>LuxSUV - Premium Ride Sharing</strong></p>
+            <p>This is an automated message, please do not reply to this email.</p>
+            <p>If you need help, contact our support team.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, name, flight.Airline, flight.FlightNumber, booking.ID, previousDate, previousTime,
+		booking.Date, booking.Time, estimated, booking.PickupLocation)
+
+	text := fmt.Sprintf(`
+Pickup Time Updated
+
+Hello %s,
+
+Flight %s %s is running late, so we have moved the pickup to match its new arrival time.
+
+- Booking ID: #%d
+- Previous Pickup: %s at %s
+- New Pickup: %s at %s
+- Estimated Arrival: %s
+- Pickup: %s
+
+---
+LuxSUV - Premium Ride Sharing
+This is an automated message, please do not reply.
+	`, name, flight.Airline, flight.FlightNumber, booking.ID, previousDate, previousTime,
+		booking.Date, booking.Time, estimated, booking.PickupLocation)
+
+	return s.sendEmail(to, subject, html, text)
+}
+
+// SendRideReminderEmail reminds a rider of an upcoming ride
+func (s *Service) SendRideReminderEmail(to, name string, booking *models.BookRide, driverName string) error {
+	subject := fmt.Sprintf("Reminder: Your LuxSUV Ride on %s at %s", booking.Date, booking.Time)
+
+	driver := driverName
+	if driver == "" {
+		driver = "To be assigned"
+	}
+
+	html := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Ride Reminder</title>
+    <style>
+        body { 
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif; 
+            line-height: 1.6; 
+            color: #333; 
+            margin: 0; 
+            padding: 0; 
+            background-color: #f8f9fa;
+        }
+        .container { 
+            max-width: 600px; 
+            margin: 40px auto; 
+            background: white; 
+            border-radius: 8px; 
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .header { 
+            background: linear-gradient(135deg, #667eea 0%%, #764ba2 100%%); 
+            color: white; 
+            padding: 40px 30px; 
+            text-align: center; 
+        }
+        .header h1 { 
+            margin: 0; 
+            font-size: 28px; 
+            font-weight: 600; 
+        }
+        .content { 
+            padding: 40px 30px; 
+        }
+        .booking-details {
+            background-color: #f7fafc;
+            border-radius: 8px;
+            padding: 20px;
+            margin: 20px 0;
+        }
+        .booking-details h3 {
+            color: #2d3748;
+            margin-top: 0;
+        }
+        .detail-row {
+            display: flex;
+            justify-content: space-between;
+            margin-bottom: 10px;
+            padding-bottom: 10px;
+            border-bottom: 1px solid #e2e8f0;
+        }
+        .detail-row:last-child {
+            border-bottom: none;
+            margin-bottom: 0;
+        }
+        .footer { 
+            background-color: #f8f9fa;
+            padding: 30px;
+            text-align: center;
+            font-size: 14px; 
+            color: #718096; 
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>🚙 Upcoming Ride</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p>This is a reminder of your upcoming ride with LuxSUV.</p>
+            
+            <div class="booking-details">
+                <h3>Ride Details</h3>
+                <div class="detail-row">
+                    <span><strong>Booking ID:</strong></span>
+                    <span>#%d</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>Pickup Time:</strong></span>
+                    <span>%s at %s</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>Pickup:</strong></span>
+                    <span>%s</span>
+                </div>
+                %s<div class="detail-row">
+                    <span><strong>Drop-off:</strong></span>
+                    <span>%s</span>
+                </div>
+                <div class="detail-row">
+                    <span><strong>Driver:</strong></span>
+                    <span>%s</span>
+                </div>
+            </div>
+        </div>
+        <div class="footer">
+            <p><strong>LuxSUV - Premium Ride Sharing</strong></p>
+            <p>This is an automated message, please do not reply to this email.</p>
+            <p>If you need help, contact our support team.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, name, booking.ID, booking.Date, booking.Time, booking.PickupLocation, stopRowsHTML(booking),
+		dropoffText(booking), driver)
+
+	text := fmt.Sprintf(`
+Upcoming Ride
+
+Hello %s,
+
+This is a reminder of your upcoming ride with LuxSUV.
+
+- Booking ID: #%d
+- Pickup Time: %s at %s
+- Pickup: %s
+%s- Drop-off: %s
+- Driver: %s
+
+---
+LuxSUV - Premium Ride Sharing
+This is an automated message, please do not reply.
+	`, name, booking.ID, booking.Date, booking.Time, booking.PickupLocation, stopRowsText(booking),
+		dropoffText(booking), driver)
+
+	return s.sendEmail(to, subject, html, text)
+}
+
+// SendDriverManifestEmail sends a driver the list of their assigned rides on a date
+func (s *Service) SendDriverManifestEmail(to, name, date string, bookings []*models.BookRide) error {
+	subject := fmt.Sprintf("Your LuxSUV Rides for %s (%d)", date, len(bookings))
+
+	html := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Ride Manifest</title>
+    <style>
+        body { 
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif; 
+            line-height: 1.6; 
+            color: #333; 
+            margin: 0; 
+            padding: 0; 
+            background-color: #f8f9fa;
+        }
+        .container { 
+            max-width: 600px; 
+            margin: 40px auto; 
+            background: white; 
+            border-radius: 8px; 
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .header { 
+            background: linear-gradient(135deg, #2d3748 0%%, #4a5568 100%%); 
+            color: white; 
+            padding: 40px 30px; 
+            text-align: center; 
+        }
+        .header h1 { 
+            margin: 0; 
+            font-size: 28px; 
+            font-weight: 600; 
+        }
+        .content { 
+            padding: 40px 30px; 
+        }
+        .booking-details {
+            background-color: #f7fafc;
+            border-radius: 8px;
+            padding: 20px;
+            margin: 20px 0;
+        }
+        .booking-details h3 {
+            color: #2d3748;
+            margin-top: 0;
+        }
+        .detail-row {
+            display: flex;
+            justify-content: space-between;
+            margin-bottom: 10px;
+            padding-bottom: 10px;
+            border-bottom: 1px solid #e2e8f0;
+        }
+        .detail-row:last-child {
+            border-bottom: none;
+            margin-bottom: 0;
+        }
+        table {
+            width: 100%%;
+            border-collapse: collapse;
+            font-size: 14px;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #e2e8f0;
+            vertical-align: top;
+        }
+        .footer { 
+            background-color: #f8f9fa;
+            padding: 30px;
+            text-align: center;
+            font-size: 14px; 
+            color: #718096; 
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>📋 Tomorrow's Rides</h1>
+        </div>
+        <div class="content">
+            <h2>Hello %s,</h2>
+            <p>You have %d ride(s) assigned on %s.</p>
+            <table>
+                <tr><th>Time</th><th>Ride</th><th>Passenger</th></tr>
+                %s
+            </table>
+        </div>
+        <div class="footer">
+            <p><strong>LuxSUV - Premium Ride Sharing</strong></p>
+            <p>This is an automated message, please do not reply to this email.</p>
+            <p>If you need help, contact our support team.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, name, len(bookings), date, manifestRowsHTML(bookings))
+
+	text := fmt.Sprintf(`
+Tomorrow's Rides
+
+Hello %s,
+
+You have %d ride(s) assigned on %s.
 
-Update your booking by visiting this link:
 %s
+---
+LuxSUV - Premium Ride Sharing
+This is an automated message, please do not reply.
+	`, name, len(bookings), date, manifestRowsText(bookings))
 
-This link will expire in 24 hours for your security.
+	return s.sendEmail(to, subject, html, text)
+}
 
-Important: Bookings can only be cancelled up to 24 hours before the scheduled time.
+// SendUnassignedAlertEmail alerts dispatch to bookings that still have no driver close to pickup
+func (s *Service) SendUnassignedAlertEmail(to string, bookings []*models.BookRide) error {
+	subject := fmt.Sprintf("Dispatch Alert: %d Unassigned Booking(s) Near Pickup", len(bookings))
 
-If you didn't request this update link, please ignore this email.
+	html := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Unassigned Bookings</title>
+    <style>
+        body { 
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif; 
+            line-height: 1.6; 
+            color: #333; 
+            margin: 0; 
+            padding: 0; 
+            background-color: #f8f9fa;
+        }
+        .container { 
+            max-width: 600px; 
+            margin: 40px auto; 
+            background: white; 
+            border-radius: 8px; 
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .header { 
+            background: linear-gradient(135deg, #e53e3e 0%%, #c53030 100%%); 
+            color: white; 
+            padding: 40px 30px; 
+            text-align: center; 
+        }
+        .header h1 { 
+            margin: 0; 
+            font-size: 28px; 
+            font-weight: 600; 
+        }
+        .content { 
+            padding: 40px 30px; 
+        }
+        .booking-details {
+            background-color: #f7fafc;
+            border-radius: 8px;
+            padding: 20px;
+            margin: 20px 0;
+        }
+        .booking-details h3 {
+            color: #2d3748;
+            margin-top: 0;
+        }
+        .detail-row {
+            display: flex;
+            justify-content: space-between;
+            margin-bottom: 10px;
+            padding-bottom: 10px;
+            border-bottom: 1px solid #e2e8f0;
+        }
+        .detail-row:last-child {
+            border-bottom: none;
+            margin-bottom: 0;
+        }
+        table {
+            width: 100%%;
+            border-collapse: collapse;
+            font-size: 14px;
+        }
+        th, td {
+            text-align: left;
+            padding: 8px;
+            border-bottom: 1px solid #e2e8f0;
+            vertical-align: top;
+        }
+        .footer { 
+            background-color: #f8f9fa;
+            padding: 30px;
+            text-align: center;
+            font-size: 14px; 
+            color: #718096; 
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>⚠️ Unassigned Bookings</h1>
+        </div>
+        <div class="content">
+            <p>The following booking(s) are close to pickup and no driver has accepted them yet.</p>
+            <table>
+                <tr><th>Time</th><th>Ride</th><th>Passenger</th></tr>
+                %s
+            </table>
+        </div>
+        <div class="footer">
+            <p><strong>LuxSUV - Premium Ride Sharing</strong></p>
+            <p>This is an automated message, please do not reply to this email.</p>
+            <p>If you need help, contact our support team.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`, manifestRowsHTML(bookings))
+
+	text := fmt.Sprintf(`
+Unassigned Bookings
 
+The following booking(s) are close to pickup and no driver has accepted them yet.
+
+%s
 ---
 LuxSUV - Premium Ride Sharing
 This is an automated message, please do not reply.
-	`, booking.YourName, booking.ID, booking.Date, booking.Time, booking.PickupLocation,
-		booking.DropoffLocation, booking.BookStatus, updateURL)
+	`, manifestRowsText(bookings))
 
 	return s.sendEmail(to, subject, html, text)
 }
 
+// manifestRowsHTML renders rides as table rows for drivers and dispatch; only the passenger's
+// name and phone are shown
+func manifestRowsHTML(bookings []*models.BookRide) string {
+	rows := ""
+	for _, booking := range bookings {
+		passenger := booking.Passenger()
+		notes := ""
+		if booking.AdditionalNotes != "" {
+			notes = "<br><em>" + booking.AdditionalNotes + "</em>"
+		}
+		rows += fmt.Sprintf(`<tr>
+                    <td>%s %s</td>
+                    <td>#%d %s<br>%s → %s%s</td>
+                    <td>%s<br>%s<br>%d pax, %d bags</td>
+                </tr>
+                `, booking.Date, booking.Time, booking.ID, booking.RideType, booking.PickupLocation, dropoffText(booking), notes,
+			passenger.Name, passenger.Phone, booking.NumberOfPassengers, booking.NumberOfLuggage)
+	}
+	return rows
+}
+
+// manifestRowsText renders rides for plain-text manifests and alerts
+func manifestRowsText(bookings []*models.BookRide) string {
+	rows := ""
+	for _, booking := range bookings {
+		passenger := booking.Passenger()
+		rows += fmt.Sprintf("- %s %s #%d %s: %s -> %s; %s %s; %d pax, %d bags\n", booking.Date, booking.Time, booking.ID,
+			booking.RideType, booking.PickupLocation, dropoffText(booking), passenger.Name, passenger.Phone,
+			booking.NumberOfPassengers, booking.NumberOfLuggage)
+		if booking.AdditionalNotes != "" {
+			rows += "  Notes: " + booking.AdditionalNotes + "\n"
+		}
+	}
+	return rows
+}
+
+// dropoffText describes the drop-off of a booking; hourly charters run as directed
+func dropoffText(booking *models.BookRide) string {
+	if !booking.IsHourly() || booking.DurationHours == nil {
+		return booking.DropoffLocation
+	}
+	text := fmt.Sprintf("As directed (%d hours)", *booking.DurationHours)
+	if booking.DropoffLocation != "" {
+		text += ", ending at " + booking.DropoffLocation
+	}
+	return text
+}
+
+// stopRowsHTML renders the intermediate stops of a booking as detail rows
+func stopRowsHTML(booking *models.BookRide) string {
+	rows := ""
+	for _, stop := range booking.Stops {
+		rows += fmt.Sprintf(`<div class="detail-row">
+                    <span><strong>Stop %d:</strong></span>
+                    <span>%s</span>
+                </div>
+                `, stop.Position, stop.Address)
+	}
+	return rows
+}
+
+// stopRowsText renders the intermediate stops of a booking for plain-text emails
+func stopRowsText(booking *models.BookRide) string {
+	rows := ""
+	for _, stop := range booking.Stops {
+		rows += fmt.Sprintf("- Stop %d: %s\n", stop.Position, stop.Address)
+	}
+	return rows
+}
+
+// fareRowsHTML renders the quoted and final fare as booking detail rows
+func fareRowsHTML(booking *models.BookRide) string {
+	rows := fmt.Sprintf(`<div class="detail-row">
+                    <span><strong>Quoted Fare:</strong></span>
+                    <span>%s</span>
+                </div>`, booking.QuotedAmount.Format(booking.Currency))
+	if booking.FinalAmount != nil {
+		rows += fmt.Sprintf(`
+                <div class="detail-row">
+                    <span><strong>Final Fare:</strong></span>
+                    <span>%s</span>
+                </div>`, booking.FinalAmount.Format(booking.Currency))
+	}
+	return rows
+}
+
+// fareRowsText renders the quoted and final fare for plain-text emails
+func fareRowsText(booking *models.BookRide) string {
+	rows := fmt.Sprintf("- Quoted Fare: %s\n", booking.QuotedAmount.Format(booking.Currency))
+	if booking.FinalAmount != nil {
+		rows += fmt.Sprintf("- Final Fare: %s\n", booking.FinalAmount.Format(booking.Currency))
+	}
+	return rows
+}
+
+// finalItems returns the final line items of a booking, or the quote when no final fare was recorded
+func finalItems(booking *models.BookRide) models.LineItems {
+	if len(booking.FinalLineItems) > 0 {
+		return booking.FinalLineItems
+	}
+	return booking.LineItems
+}
+
+// lineItemRowsHTML renders every line item of the final fare as booking detail rows
+func lineItemRowsHTML(booking *models.BookRide) string {
+	rows := ""
+	for _, item := range finalItems(booking) {
+		description := item.Description
+		if item.Quantity > 1 {
+			description = fmt.Sprintf("%s × %d", item.Description, item.Quantity)
+		}
+		rows += fmt.Sprintf(`<div class="detail-row">
+                    <span>%s</span>
+                    <span>%s</span>
+                </div>
+                `, description, item.Amount.Format(booking.Currency))
+	}
+	return rows
+}
+
+// lineItemRowsText renders every line item of the final fare for plain-text emails
+func lineItemRowsText(booking *models.BookRide) string {
+	rows := ""
+	for _, item := range finalItems(booking) {
+		description := item.Description
+		if item.Quantity > 1 {
+			description = fmt.Sprintf("%s x %d", item.Description, item.Quantity)
+		}
+		rows += fmt.Sprintf("- %s: %s\n", description, item.Amount.Format(booking.Currency))
+	}
+	return rows
+}
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	Filename string
+	Content  []byte
+}
+
 // sendEmail sends an email using MailerSend API
-func (s *Service) sendEmail(to, subject, html, text string) error {
+func (s *Service) sendEmail(to, subject, html, text string, attachments ...Attachment) error {
 	s.logger.Info(fmt.Sprintf("Attempting to send email to %s via MailerSend", to))
 
 	ctx := context.Background()
@@ -580,6 +1506,13 @@ func (s *Service) sendEmail(to, subject, html, text string) error {
 	message.SetHTML(html)
 	message.SetText(text)
 	message.SetTags([]string{"password-reset", "luxsuv"})
+	for _, attachment := range attachments {
+		message.AddAttachment(mailersend.Attachment{
+			Content:     base64.StdEncoding.EncodeToString(attachment.Content),
+			Filename:    attachment.Filename,
+			Disposition: mailersend.DispositionAttachment,
+		})
+	}
 
 	s.logger.Info(fmt.Sprintf("Sending email via MailerSend API to %s", to))
 
@@ -0,0 +1,24 @@
+package flights
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnknownFlight is returned when a provider has no record of a flight
+var ErrUnknownFlight = errors.New("unknown flight")
+
+// Status is the latest known state of a flight
+type Status struct {
+	Status           string
+	EstimatedArrival time.Time
+}
+
+// FlightStatusProvider looks up the live status of a flight
+type FlightStatusProvider interface {
+	// Name identifies the provider in logs
+	Name() string
+	// Status returns the status of the flight scheduled to arrive at scheduledArrival
+	Status(ctx context.Context, airline, flightNumber string, scheduledArrival time.Time) (*Status, error)
+}
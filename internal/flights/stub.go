@@ -0,0 +1,104 @@
+package flights
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// StubProviderName identifies the stub provider
+const StubProviderName = "stub"
+
+// StubFlight is a simulated flight status, keyed by airline code and flight number ("AA100") in the stub file
+type StubFlight struct {
+	Status       string `json:"status"`
+	DelayMinutes int    `json:"delay_minutes"`
+}
+
+// StubProvider reports simulated flight statuses for local development.
+// Flights it has no entry for are reported on time.
+type StubProvider struct {
+	mu      sync.RWMutex
+	flights map[string]StubFlight
+}
+
+// NewStubProvider loads simulated statuses from a JSON object of flight ("AA100") to status.
+// A missing file leaves every flight on time.
+func NewStubProvider(path string) (*StubProvider, error) {
+	p := &StubProvider{flights: make(map[string]StubFlight)}
+	if path == "" {
+		return p, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flight stub file: %w", err)
+	}
+
+	var flights map[string]StubFlight
+	if err := json.Unmarshal(data, &flights); err != nil {
+		return nil, fmt.Errorf("failed to parse flight stub file: %w", err)
+	}
+	for number, flight := range flights {
+		p.flights[normalizeFlightNumber(number)] = flight
+	}
+	return p, nil
+}
+
+// Set simulates the status of a flight, given as airline code and number ("AA100")
+func (p *StubProvider) Set(flightNumber string, flight StubFlight) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flights[normalizeFlightNumber(flightNumber)] = flight
+}
+
+func (p *StubProvider) Name() string {
+	return StubProviderName
+}
+
+func (p *StubProvider) Status(ctx context.Context, airline, flightNumber string, scheduledArrival time.Time) (*Status, error) {
+	p.mu.RLock()
+	flight, ok := p.flights[flightKey(airline, flightNumber)]
+	p.mu.RUnlock()
+
+	if !ok {
+		return &Status{Status: models.FlightStatusScheduled, EstimatedArrival: scheduledArrival}, nil
+	}
+
+	status := flight.Status
+	if status == "" {
+		status = models.FlightStatusScheduled
+		if flight.DelayMinutes > 0 {
+			status = models.FlightStatusDelayed
+		}
+	}
+	return &Status{
+		Status:           status,
+		EstimatedArrival: scheduledArrival.Add(time.Duration(flight.DelayMinutes) * time.Minute),
+	}, nil
+}
+
+func normalizeFlightNumber(flightNumber string) string {
+	return strings.ToUpper(strings.ReplaceAll(flightNumber, " ", ""))
+}
+
+// flightKey joins a booking's airline and flight number the way the stub file keys flights. Riders
+// may enter the number with or without the airline code, so "AA" and "100" or "AA100" both give "AA100".
+func flightKey(airline, flightNumber string) string {
+	airline = normalizeFlightNumber(airline)
+	flightNumber = normalizeFlightNumber(flightNumber)
+	if strings.HasPrefix(flightNumber, airline) {
+		return flightNumber
+	}
+	return airline + flightNumber
+}
@@ -0,0 +1,111 @@
+package flights
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// Tracker polls the status of inbound flights and moves pickups of delayed flights
+type Tracker struct {
+	provider FlightStatusProvider
+	bookings repository.BookRideRepository
+	zones    repository.ServiceZoneRepository
+	notifier *notify.Dispatcher
+	logger   *logger.Logger
+}
+
+func NewTracker(provider FlightStatusProvider, bookings repository.BookRideRepository, zones repository.ServiceZoneRepository, notifier *notify.Dispatcher, logger *logger.Logger) *Tracker {
+	return &Tracker{
+		provider: provider,
+		bookings: bookings,
+		zones:    zones,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// CheckAll refreshes the status of every tracked flight; run as a scheduled job
+func (t *Tracker) CheckAll(ctx context.Context) error {
+	bookings, err := t.bookings.GetTrackedFlights(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load tracked flights: %w", err)
+	}
+	zones, err := t.zones.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load service zones: %w", err)
+	}
+	// Pickup times are local to the pickup zone, or to the default zone for unzoned bookings
+	locations := make(map[int64]*time.Location, len(zones))
+	fallback := time.UTC
+	for _, zone := range zones {
+		locations[zone.ID] = zone.Location()
+		if zone.Code == models.DefaultZoneCode {
+			fallback = zone.Location()
+		}
+	}
+
+	for _, booking := range bookings {
+		loc := fallback
+		if booking.PickupZoneID != nil {
+			if zoneLoc, ok := locations[*booking.PickupZoneID]; ok {
+				loc = zoneLoc
+			}
+		}
+		if err := t.Check(ctx, booking, loc); err != nil {
+			t.logger.Warn(fmt.Sprintf("Flight check failed for booking %d (%s%s): %s", booking.ID,
+				booking.Flight.Airline, booking.Flight.FlightNumber, err.Error()))
+		}
+	}
+	return nil
+}
+
+// Check refreshes the status of one booking's flight; loc is the time zone of its pickup.
+// The pickup follows the original pickup time plus the current delay, so repeated checks
+// are harmless and a shrinking delay moves the pickup back, but never before the original time.
+func (t *Tracker) Check(ctx context.Context, booking *models.BookRide, loc *time.Location) error {
+	flight := *booking.Flight
+	status, err := t.provider.Status(ctx, flight.Airline, flight.FlightNumber, flight.ScheduledArrival)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	estimated := status.EstimatedArrival.UTC()
+	flight.Status = status.Status
+	flight.EstimatedArrival = &estimated
+	flight.CheckedAt = &now
+	if flight.OriginalPickup == "" {
+		flight.OriginalPickup = booking.Date + " " + booking.Time
+	}
+
+	original, err := time.ParseInLocation(models.PickupDateTimeLayout, flight.OriginalPickup, loc)
+	if err != nil {
+		return fmt.Errorf("invalid original pickup time: %w", err)
+	}
+	pickup := original.Add(flight.Delay()).In(loc)
+	date, clock := pickup.Format(models.PickupDateLayout), pickup.Format(models.PickupTimeLayout)
+
+	if err := t.bookings.UpdateFlight(ctx, booking.ID, &flight, date, clock); err != nil {
+		return err
+	}
+
+	if date == booking.Date && clock == booking.Time {
+		return nil
+	}
+
+	previousDate, previousTime := booking.Date, booking.Time
+	booking.Flight = &flight
+	booking.Date = date
+	booking.Time = clock
+	t.logger.Info(fmt.Sprintf("Flight %s%s is %s; pickup of booking %d moved from %s %s to %s %s",
+		flight.Airline, flight.FlightNumber, flight.Status, booking.ID, previousDate, previousTime, date, clock))
+
+	t.notifier.FlightDelay(ctx, booking, previousDate, previousTime)
+	return nil
+}
@@ -0,0 +1,99 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// GazetteerEntry is a known place in the offline gazetteer file
+type GazetteerEntry struct {
+	PlaceID          string   `json:"place_id"`
+	FormattedAddress string   `json:"formatted_address"`
+	Aliases          []string `json:"aliases"`
+	Lat              float64  `json:"lat"`
+	Lng              float64  `json:"lng"`
+}
+
+// Gazetteer is an offline Geocoder backed by a JSON file of known places.
+// It is meant for development and tests: a query matches an entry when it
+// equals or contains the entry's address or one of its aliases as whole words,
+// so "lga" matches "LGA Terminal B" but not "Volga St".
+type Gazetteer struct {
+	entries []GazetteerEntry
+}
+
+// NewGazetteer loads gazetteer entries from a JSON file
+func NewGazetteer(path string) (*Gazetteer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gazetteer file: %w", err)
+	}
+
+	var entries []GazetteerEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse gazetteer file: %w", err)
+	}
+	return NewGazetteerFromEntries(entries), nil
+}
+
+// NewGazetteerFromEntries builds a gazetteer from in-memory entries
+func NewGazetteerFromEntries(entries []GazetteerEntry) *Gazetteer {
+	return &Gazetteer{entries: entries}
+}
+
+func (g *Gazetteer) Geocode(ctx context.Context, query string) (*models.Location, error) {
+	normalized := normalizeAddress(query)
+	if normalized == "" {
+		return nil, ErrNotFound
+	}
+	// Padding both sides with spaces matches names on word boundaries
+	padded := " " + normalized + " "
+
+	// Prefer the longest matching name so "jfk terminal 4" beats "jfk"
+	var best *GazetteerEntry
+	bestLen := 0
+	for i := range g.entries {
+		entry := &g.entries[i]
+		names := append([]string{entry.FormattedAddress}, entry.Aliases...)
+		for _, name := range names {
+			name = normalizeAddress(name)
+			if name == "" || !strings.Contains(padded, " "+name+" ") {
+				continue
+			}
+			if len(name) > bestLen {
+				best = entry
+				bestLen = len(name)
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNotFound
+	}
+
+	lat, lng := best.Lat, best.Lng
+	return &models.Location{
+		FormattedAddress: best.FormattedAddress,
+		Lat:              &lat,
+		Lng:              &lng,
+		PlaceID:          best.PlaceID,
+	}, nil
+}
+
+// normalizeAddress lower-cases an address and turns punctuation into single spaces between words
+func normalizeAddress(s string) string {
+	s = strings.ToLower(s)
+	s = strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
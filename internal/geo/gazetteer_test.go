@@ -0,0 +1,51 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGazetteerGeocode(t *testing.T) {
+	gazetteer := NewGazetteerFromEntries([]GazetteerEntry{
+		{PlaceID: "lga", FormattedAddress: "LaGuardia Airport, Queens, NY", Aliases: []string{"LGA", "LaGuardia"}, Lat: 40.7769, Lng: -73.8740},
+		{PlaceID: "jfk", FormattedAddress: "JFK Airport, Queens, NY", Aliases: []string{"JFK"}, Lat: 40.6413, Lng: -73.7781},
+		{PlaceID: "jfk-t4", FormattedAddress: "JFK Terminal 4, Queens, NY", Aliases: []string{"JFK Terminal 4", "JFK T4"}, Lat: 40.6441, Lng: -73.7823},
+	})
+
+	tests := []struct {
+		query string
+		want  string // place ID, empty when nothing matches
+	}{
+		{"LGA", "lga"},
+		{"lga terminal b", "lga"},
+		{"Pickup at LGA.", "lga"},
+		{"laguardia airport, queens, ny", "lga"},
+		{"LaGuardia-Airport Queens NY", "lga"},
+		{"Volga St", ""},
+		{"lgaterminal", ""},
+		{"JFK", "jfk"},
+		{"jfk terminal 4 departures", "jfk-t4"},
+		{"JFK T4", "jfk-t4"},
+		{"jfk terminal 44", "jfk"},
+		{"", ""},
+		{"  ,.  ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			loc, err := gazetteer.Geocode(context.Background(), tt.query)
+			if tt.want == "" {
+				if !errors.Is(err, ErrNotFound) {
+					t.Errorf("Geocode(%q) = %v, %v; want ErrNotFound", tt.query, loc, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Geocode(%q): %v", tt.query, err)
+			}
+			if loc.PlaceID != tt.want {
+				t.Errorf("Geocode(%q) = %s, want %s", tt.query, loc.PlaceID, tt.want)
+			}
+		})
+	}
+}
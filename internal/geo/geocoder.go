@@ -0,0 +1,30 @@
+package geo
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// ErrNotFound is returned when an address cannot be resolved
+var ErrNotFound = errors.New("address not found")
+
+// Geocoder resolves free-text addresses to coordinates
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (*models.Location, error)
+}
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance between two resolved locations
+func DistanceKm(a, b *models.Location) float64 {
+	lat1 := *a.Lat * math.Pi / 180
+	lat2 := *b.Lat * math.Pi / 180
+	dLat := (*b.Lat - *a.Lat) * math.Pi / 180
+	dLng := (*b.Lng - *a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
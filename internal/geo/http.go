@@ -0,0 +1,89 @@
+package geo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// HTTPGeocoder queries a Nominatim-compatible search API
+// (GET {baseURL}/search?q=...&format=json&limit=1)
+type HTTPGeocoder struct {
+	baseURL   string
+	apiKey    string
+	userAgent string
+	client    *http.Client
+}
+
+func NewHTTPGeocoder(baseURL, apiKey string) *HTTPGeocoder {
+	return &HTTPGeocoder{
+		baseURL:   baseURL,
+		apiKey:    apiKey,
+		userAgent: "luxsuv-backend/1.0",
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type nominatimResult struct {
+	PlaceID     json.Number `json:"place_id"`
+	DisplayName string      `json:"display_name"`
+	Lat         string      `json:"lat"`
+	Lon         string      `json:"lon"`
+}
+
+func (g *HTTPGeocoder) Geocode(ctx context.Context, query string) (*models.Location, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	params.Set("limit", "1")
+	if g.apiKey != "" {
+		params.Set("key", g.apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoding request failed with status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude in geocoding response: %w", err)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude in geocoding response: %w", err)
+	}
+
+	return &models.Location{
+		FormattedAddress: results[0].DisplayName,
+		Lat:              &lat,
+		Lng:              &lng,
+		PlaceID:          results[0].PlaceID.String(),
+	}, nil
+}
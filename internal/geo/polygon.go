@@ -0,0 +1,92 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// Ring is a closed sequence of [lng, lat] positions, as in GeoJSON
+type Ring [][2]float64
+
+// Polygon is an outer ring followed by optional holes
+type Polygon []Ring
+
+// Area is the set of polygons making up a service area
+type Area []Polygon
+
+// ParseGeometry converts a GeoJSON Polygon or MultiPolygon into an Area
+func ParseGeometry(g *models.GeoJSONGeometry) (Area, error) {
+	if g == nil {
+		return nil, errors.New("geometry is required")
+	}
+
+	var area Area
+	switch g.Type {
+	case "Polygon":
+		var polygon Polygon
+		if err := json.Unmarshal(g.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		area = Area{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(g.Coordinates, &area); err != nil {
+			return nil, fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q; use Polygon or MultiPolygon", g.Type)
+	}
+
+	if len(area) == 0 {
+		return nil, errors.New("geometry has no polygons")
+	}
+	for _, polygon := range area {
+		if len(polygon) == 0 {
+			return nil, errors.New("polygon has no rings")
+		}
+		for _, ring := range polygon {
+			if len(ring) < 4 {
+				return nil, errors.New("polygon rings need at least 4 positions")
+			}
+			if ring[0] != ring[len(ring)-1] {
+				return nil, errors.New("polygon rings must be closed (first and last positions equal)")
+			}
+		}
+	}
+	return area, nil
+}
+
+// Contains reports whether the point lies inside the area (inside an outer ring and outside its holes)
+func (a Area) Contains(lat, lng float64) bool {
+	for _, polygon := range a {
+		if !polygon[0].contains(lat, lng) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if hole.contains(lat, lng) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// contains uses ray casting; points exactly on an edge may fall either way
+func (r Ring) contains(lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
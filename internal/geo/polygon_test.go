@@ -0,0 +1,77 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+func geometry(kind, coordinates string) *models.GeoJSONGeometry {
+	return &models.GeoJSONGeometry{Type: kind, Coordinates: json.RawMessage(coordinates)}
+}
+
+func TestAreaContains(t *testing.T) {
+	// A 10x10 degree square with a 2x2 hole in the middle, and a separate square to the east
+	square := geometry("Polygon", `[
+		[[0,0],[10,0],[10,10],[0,10],[0,0]],
+		[[4,4],[6,4],[6,6],[4,6],[4,4]]
+	]`)
+	multi := geometry("MultiPolygon", `[
+		[[[0,0],[10,0],[10,10],[0,10],[0,0]]],
+		[[[20,0],[30,0],[30,10],[20,10],[20,0]]]
+	]`)
+	// A concave L shape missing its upper right quarter
+	concave := geometry("Polygon", `[[[0,0],[10,0],[10,5],[5,5],[5,10],[0,10],[0,0]]]`)
+
+	tests := []struct {
+		name     string
+		geometry *models.GeoJSONGeometry
+		lat, lng float64
+		want     bool
+	}{
+		{"inside", square, 2, 2, true},
+		{"in the hole", square, 5, 5, false},
+		{"between hole and edge", square, 5, 8, true},
+		{"outside", square, 15, 5, false},
+		{"latitude and longitude are not swapped", square, 5, -1, false},
+		{"first polygon of many", multi, 5, 5, true},
+		{"second polygon of many", multi, 5, 25, true},
+		{"between polygons", multi, 5, 15, false},
+		{"concave inside", concave, 8, 2, true},
+		{"concave notch", concave, 8, 8, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			area, err := ParseGeometry(tt.geometry)
+			if err != nil {
+				t.Fatalf("ParseGeometry: %v", err)
+			}
+			if got := area.Contains(tt.lat, tt.lng); got != tt.want {
+				t.Errorf("Contains(%v, %v) = %v, want %v", tt.lat, tt.lng, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGeometryErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry *models.GeoJSONGeometry
+	}{
+		{"missing", nil},
+		{"unsupported type", geometry("Point", `[0,0]`)},
+		{"invalid coordinates", geometry("Polygon", `"nope"`)},
+		{"no polygons", geometry("MultiPolygon", `[]`)},
+		{"no rings", geometry("Polygon", `[]`)},
+		{"too few positions", geometry("Polygon", `[[[0,0],[1,0],[0,0]]]`)},
+		{"open ring", geometry("Polygon", `[[[0,0],[1,0],[1,1],[0,1]]]`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseGeometry(tt.geometry); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
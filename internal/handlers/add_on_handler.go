@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type AddOnHandler struct {
+	repo   repository.AddOnRepository
+	logger *logger.Logger
+}
+
+func NewAddOnHandler(repo repository.AddOnRepository, logger *logger.Logger) *AddOnHandler {
+	return &AddOnHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Catalog returns the active add-ons, optionally only those available for ?ride_type=
+func (h *AddOnHandler) Catalog(c echo.Context) error {
+	list, err := h.repo.ListActive(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list add-ons: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list add-ons"})
+	}
+
+	rideType := c.QueryParam("ride_type")
+	available := make([]*models.AddOn, 0, len(list))
+	for _, addOn := range list {
+		if rideType == "" || addOn.AvailableFor(rideType) {
+			available = append(available, addOn)
+		}
+	}
+	return c.JSON(http.StatusOK, available)
+}
+
+// List returns all add-ons, including inactive ones (admin only)
+func (h *AddOnHandler) List(c echo.Context) error {
+	list, err := h.repo.List(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list add-ons: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list add-ons"})
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// Upsert creates or replaces a catalog add-on (admin only). Bookings keep the price they were quoted.
+func (h *AddOnHandler) Upsert(c echo.Context) error {
+	var addOn models.AddOn
+	if err := c.Bind(&addOn); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	addOn.Code = strings.ToLower(strings.TrimSpace(c.Param("code")))
+
+	if err := validation.ValidateAddOn(&addOn); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.repo.Upsert(c.Request().Context(), &addOn); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to save add-on %s: %s", addOn.Code, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save add-on"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Add-on %s saved", addOn.Code))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "add-on saved successfully",
+		"add_on":  addOn,
+	})
+}
+
+// Delete removes an add-on from the catalog (admin only)
+func (h *AddOnHandler) Delete(c echo.Context) error {
+	code := strings.ToLower(strings.TrimSpace(c.Param("code")))
+
+	if err := h.repo.Delete(c.Request().Context(), code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "add-on not found"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to delete add-on %s: %s", code, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete add-on"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Add-on %s deleted", code))
+	return c.JSON(http.StatusOK, map[string]string{"message": "add-on deleted successfully"})
+}
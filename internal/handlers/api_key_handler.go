@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/apikeys"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+// APIKeyHandler lets admins issue, rotate and revoke partner API keys
+type APIKeyHandler struct {
+	apiKeys *apikeys.Service
+	logger  *logger.Logger
+}
+
+func NewAPIKeyHandler(apiKeys *apikeys.Service, logger *logger.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeys: apiKeys,
+		logger:  logger,
+	}
+}
+
+// List returns the API keys of every organization, or of one with ?organization_id=, and the scopes
+// keys can be granted (admin only)
+func (h *APIKeyHandler) List(c echo.Context) error {
+	var orgID int64
+	if param := c.QueryParam("organization_id"); param != "" {
+		id, err := strconv.ParseInt(param, 10, 64)
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid organization ID"})
+		}
+		orgID = id
+	}
+
+	keys, err := h.apiKeys.List(c.Request().Context(), orgID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list API keys: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list API keys"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"api_keys": keys,
+		"count":    len(keys),
+		"scopes":   models.APIKeyScopes,
+	})
+}
+
+// Create issues an API key acting as a member of an organization. The key is only returned here.
+// (admin only)
+func (h *APIKeyHandler) Create(c echo.Context) error {
+	adminID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	var req models.CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validation.ValidateCreateAPIKey(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	key, token, err := h.apiKeys.Issue(c.Request().Context(), &req, adminID)
+	if errors.Is(err, apikeys.ErrNotMember) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "the key's user must be a member of the organization"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to issue API key for organization %d: %s", req.OrganizationID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to issue API key"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Admin %d issued API key %s for organization %d acting as user %d", adminID, key.Prefix, key.OrganizationID, key.UserID))
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "API key issued successfully; store the key now, it will not be shown again",
+		"api_key": key,
+		"key":     token,
+	})
+}
+
+// Update replaces an API key's name, scopes, rate limit and expiry (admin only)
+func (h *APIKeyHandler) Update(c echo.Context) error {
+	key, err := h.loadKey(c)
+	if key == nil {
+		return err
+	}
+
+	var req models.UpdateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validation.ValidateUpdateAPIKey(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	key.Name = req.Name
+	key.Scopes = req.Scopes
+	key.RateLimitPerMinute = req.RateLimitPerMinute
+	key.ExpiresAt = req.ExpiresAt
+	if err := h.apiKeys.Update(c.Request().Context(), key); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to update API key %s: %s", key.Prefix, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update API key"})
+	}
+
+	h.logger.Info(fmt.Sprintf("API key %s updated", key.Prefix))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "API key updated successfully",
+		"api_key": key,
+	})
+}
+
+// Rotate issues a replacement for an API key. The old key keeps working for grace_hours (default
+// 24, at most 168; 0 ends it now). The new key is only returned here. (admin only)
+func (h *APIKeyHandler) Rotate(c echo.Context) error {
+	adminID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+	key, err := h.loadKey(c)
+	if key == nil {
+		return err
+	}
+
+	var req models.RotateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	grace := apikeys.DefaultGracePeriod
+	if req.GraceHours != nil {
+		grace = time.Duration(*req.GraceHours) * time.Hour
+		if grace < 0 || grace > apikeys.MaxGracePeriod {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "grace hours must be between 0 and 168"})
+		}
+	}
+	if key.ReplacedBy != nil {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "API key was already rotated; rotate its replacement instead"})
+	}
+
+	replacement, token, err := h.apiKeys.Rotate(c.Request().Context(), key.ID, grace, adminID)
+	if errors.Is(err, apikeys.ErrKeyRevoked) || errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "revoked or already rotated API keys cannot be rotated"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to rotate API key %s: %s", key.Prefix, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to rotate API key"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Admin %d rotated API key %s to %s", adminID, key.Prefix, replacement.Prefix))
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message":         "API key rotated successfully; store the new key now, it will not be shown again",
+		"api_key":         replacement,
+		"key":             token,
+		"old_key_expires": time.Now().Add(grace),
+	})
+}
+
+// Revoke stops an API key from working immediately (admin only)
+func (h *APIKeyHandler) Revoke(c echo.Context) error {
+	key, err := h.loadKey(c)
+	if key == nil {
+		return err
+	}
+
+	err = h.apiKeys.Revoke(c.Request().Context(), key.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "API key is already revoked"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to revoke API key %s: %s", key.Prefix, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to revoke API key"})
+	}
+
+	h.logger.Info(fmt.Sprintf("API key %s revoked", key.Prefix))
+	return c.JSON(http.StatusOK, map[string]string{"message": "API key revoked successfully"})
+}
+
+// loadKey returns the key named by the :id path parameter, or writes an error response
+func (h *APIKeyHandler) loadKey(c echo.Context) (*models.APIKey, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid API key ID"})
+	}
+	key, err := h.apiKeys.Get(c.Request().Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "API key not found"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get API key %d: %s", id, err.Error()))
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get API key"})
+	}
+	return key, nil
+}
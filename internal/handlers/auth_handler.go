@@ -6,23 +6,23 @@ import (
 	"strconv"
 
 	"github.com/diagnosis/luxsuv-v4/internal/auth"
-	"github.com/diagnosis/luxsuv-v4/internal/email"
 	"github.com/diagnosis/luxsuv-v4/internal/logger"
 	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
 	"github.com/labstack/echo/v4"
 )
 
 type AuthHandler struct {
-	authService  *auth.Service
-	emailService *email.Service
-	logger       *logger.Logger
+	authService *auth.Service
+	notifier    *notify.Dispatcher
+	logger      *logger.Logger
 }
 
-func NewAuthHandler(authService *auth.Service, emailService *email.Service, logger *logger.Logger) *AuthHandler {
+func NewAuthHandler(authService *auth.Service, notifier *notify.Dispatcher, logger *logger.Logger) *AuthHandler {
 	return &AuthHandler{
-		authService:  authService,
-		emailService: emailService,
-		logger:       logger,
+		authService: authService,
+		notifier:    notifier,
+		logger:      logger,
 	}
 }
 
@@ -46,13 +46,8 @@ func (h *AuthHandler) Register(c echo.Context) error {
 
 	h.logger.Info(fmt.Sprintf("User registered successfully: %s", user.Email))
 
-	// Send welcome email if email service is configured
-	if h.emailService != nil {
-		if err := h.emailService.SendWelcomeEmail(user.Email, user.Username); err != nil {
-			h.logger.Warn(fmt.Sprintf("Failed to send welcome email to %s: %s", user.Email, err.Error()))
-			// Don't fail registration if email fails
-		}
-	}
+	// Send welcome email unless turned off; registration never fails because of it
+	h.notifier.Welcome(c.Request().Context(), user)
 
 	return c.JSON(http.StatusCreated, map[string]interface{}{
 		"message": "user registered successfully",
@@ -1,16 +1,26 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 	"github.com/diagnosis/luxsuv-v4/internal/logger"
 	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
 	"github.com/diagnosis/luxsuv-v4/internal/repository"
 	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/diagnosis/luxsuv-v4/internal/zones"
 	"github.com/diagnosis/luxsuv-v4/internal/auth"
 	"github.com/diagnosis/luxsuv-v4/internal/email"
+	"github.com/diagnosis/luxsuv-v4/internal/geo"
+	"github.com/diagnosis/luxsuv-v4/internal/ledger"
+	"github.com/diagnosis/luxsuv-v4/internal/organizations"
 	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/payments"
+	"github.com/diagnosis/luxsuv-v4/internal/pricing"
+	"github.com/diagnosis/luxsuv-v4/internal/receipts"
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"net/url"
@@ -21,18 +31,105 @@ type BookRideHandler struct {
 	repo   repository.BookRideRepository
 	logger *logger.Logger
 	authService  *auth.Service
-	emailService *email.Service
+	notifier     *notify.Dispatcher
+	pricing      *pricing.Calculator
+	payments     *payments.Service
+	policies     repository.CancellationPolicyRepository
+	zones        *zones.Resolver
+	geocoder     geo.Geocoder
+	addOns       repository.AddOnRepository
+	receipts     *receipts.Service
+	ledger       *ledger.Service
+	orgs         *organizations.Service
+	// Calling code of phone numbers entered without one
+	countryCode  string
 }
 
-func NewBookRideHandler(repo repository.BookRideRepository, logger *logger.Logger, authService *auth.Service, emailService *email.Service) *BookRideHandler {
+func NewBookRideHandler(repo repository.BookRideRepository, logger *logger.Logger, authService *auth.Service, notifier *notify.Dispatcher, pricing *pricing.Calculator, payments *payments.Service, policies repository.CancellationPolicyRepository, zones *zones.Resolver, geocoder geo.Geocoder, addOns repository.AddOnRepository, receipts *receipts.Service, ledger *ledger.Service, orgs *organizations.Service, countryCode string) *BookRideHandler {
 	return &BookRideHandler{
 		repo:   repo,
 		logger: logger,
 		authService:  authService,
-		emailService: emailService,
+		notifier:     notifier,
+		pricing:      pricing,
+		payments:     payments,
+		policies:     policies,
+		zones:        zones,
+		geocoder:     geocoder,
+		addOns:       addOns,
+		receipts:     receipts,
+		ledger:       ledger,
+		orgs:         orgs,
+		countryCode:  countryCode,
 	}
 }
 
+// maxPinDistanceKm is how far coordinates supplied by the client may be from the geocoded
+// address and still be used, so riders can pin a precise spot such as a terminal door
+const maxPinDistanceKm = 1.0
+
+// resolveLocation returns the structured location of an address. The raw text is always
+// geocoded; coordinates supplied by the client are only kept when they lie near the geocoded
+// point. Addresses that cannot be geocoded are stored without coordinates.
+func (h *BookRideHandler) resolveLocation(c echo.Context, raw string, supplied *models.Location) *models.Location {
+	resolved, err := h.geocoder.Geocode(c.Request().Context(), raw)
+	if err != nil {
+		if !errors.Is(err, geo.ErrNotFound) {
+			h.logger.Warn(fmt.Sprintf("Geocoding failed for %q: %s", raw, err.Error()))
+		}
+		resolved = &models.Location{FormattedAddress: raw}
+	} else if supplied.HasCoordinates() {
+		if distance := geo.DistanceKm(supplied, resolved); distance <= maxPinDistanceKm {
+			resolved.Lat, resolved.Lng = supplied.Lat, supplied.Lng
+		} else {
+			h.logger.Warn(fmt.Sprintf("Ignoring coordinates supplied for %q that are %.1f km from the address", raw, distance))
+		}
+	}
+	if supplied != nil {
+		resolved.DriverNotes = supplied.DriverNotes
+	}
+	return resolved
+}
+
+// resolveTrip finds the service zones of a trip and writes the error response if the pickup is out of area
+func (h *BookRideHandler) resolveTrip(c echo.Context, pickupText string, pickup, dropoff *models.Location) (*zones.Trip, error) {
+	trip, err := h.zones.ResolveTrip(c.Request().Context(), pickupText, pickup, dropoff)
+	if err != nil {
+		if errors.Is(err, zones.ErrNoZone) || errors.Is(err, zones.ErrUnlocatedPickup) {
+			return nil, c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error(), "rule": "service_area"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to resolve service zone for %q: %s", pickupText, err.Error()))
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to resolve service zone"})
+	}
+	return trip, nil
+}
+
+// validateAddOns checks selected add-ons against the active catalog and writes the error
+// response if they are invalid. It returns false when the request cannot continue.
+func (h *BookRideHandler) validateAddOns(c echo.Context, addOns models.BookingAddOns, rideType string) (bool, error) {
+	if len(addOns) == 0 {
+		return true, nil
+	}
+	catalog, err := h.addOns.ListActive(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to load add-on catalog: %s", err.Error()))
+		return false, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load add-ons"})
+	}
+	if err := validation.ValidateAddOns(addOns, catalog, rideType); err != nil {
+		return false, c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	return true, nil
+}
+
+// scheduleErrorResponse renders a schedule validation error, naming the violated rule when known
+func scheduleErrorResponse(c echo.Context, err error) error {
+	var ruleErr *validation.RuleError
+	if errors.As(err, &ruleErr) {
+		return c.JSON(http.StatusBadRequest, ruleErr)
+	}
+	return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+}
+
 func (h *BookRideHandler) Create(c echo.Context) error {
 	br := &models.BookRide{}
 	if err := c.Bind(br); err != nil {
@@ -43,9 +140,29 @@ func (h *BookRideHandler) Create(c echo.Context) error {
 	h.logger.Info(fmt.Sprintf("Received booking data: %+v", br))
 
 	//validate input
-	if err := validation.ValidateBookRide(br); err != nil {
+	if err := validation.ValidateBookRide(br, h.countryCode); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
+	if ok, err := h.validateAddOns(c, br.AddOns, br.RideType); !ok {
+		return err
+	}
+
+	// Resolve structured locations, keeping the raw text as entered
+	br.Pickup = h.resolveLocation(c, br.PickupLocation, br.Pickup)
+	if br.DropoffLocation != "" {
+		br.Dropoff = h.resolveLocation(c, br.DropoffLocation, br.Dropoff)
+	}
+	h.resolveStops(c, br.Stops)
+
+	// Reject out-of-area pickups and validate the schedule against the pickup's service zone
+	trip, err := h.resolveTrip(c, br.PickupLocation, br.Pickup, br.Dropoff)
+	if trip == nil {
+		return err
+	}
+	zone := trip.PickupZone
+	if err := validation.ValidateBookingDateTime(zone, br.Date, br.Time, time.Now()); err != nil {
+		return scheduleErrorResponse(c, err)
+	}
 
 	// Get user ID from context if user is authenticated
 	userIDClaim := c.Get("user_id")
@@ -69,16 +186,200 @@ func (h *BookRideHandler) Create(c echo.Context) error {
 			return "nil"
 		}(), br.YourName, br.Email))
 
+	startFlightTracking(br.Flight, br.Date, br.Time)
+	h.prepareBooking(br, trip, zone)
+
+	// A requested return leg runs from the drop-off back to the pickup and is validated up front
+	var ret *models.BookRide
+	if br.ReturnTrip != nil {
+		ret = returnLeg(br)
+		h.resolveStops(c, ret.Stops)
+		retTrip, err := h.resolveTrip(c, ret.PickupLocation, ret.Pickup, ret.Dropoff)
+		if retTrip == nil {
+			return err
+		}
+		if err := validation.ValidateBookingDateTime(retTrip.PickupZone, ret.Date, ret.Time, time.Now()); err != nil {
+			return scheduleErrorResponse(c, err)
+		}
+		h.prepareBooking(ret, retTrip, retTrip.PickupZone)
+	}
+
+	// Corporate bookings are billed to the organization and may wait for an org admin's approval
+	if ok, err := h.attributeToOrganization(c, br, ret); !ok {
+		return err
+	}
+
+	if err := h.placeBooking(c, br); err != nil {
+		return placeBookingErrorResponse(c, err)
+	}
+
+	if ret != nil {
+		ret.OutboundBookingID = &br.ID
+		if err := h.placeBooking(c, ret); err != nil {
+			// Without its return leg the outbound booking is not what the rider asked for
+			if h.payments != nil {
+				if voidErr := h.payments.VoidBooking(c.Request().Context(), br.ID); voidErr != nil {
+					h.logger.Err(fmt.Sprintf("Failed to void hold of booking %d: %s", br.ID, voidErr.Error()))
+				}
+			}
+			if cancelErr := h.repo.Cancel(c.Request().Context(), br.ID, "return trip could not be booked"); cancelErr != nil {
+				h.logger.Err(fmt.Sprintf("Failed to cancel booking %d after return trip failure: %s", br.ID, cancelErr.Error()))
+			}
+			return placeBookingErrorResponse(c, err)
+		}
+		if err := h.repo.LinkReturn(c.Request().Context(), br.ID, ret.ID); err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to link return booking %d to booking %d: %s", ret.ID, br.ID, err.Error()))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "error linking return booking"})
+		}
+		br.ReturnBookingID = &ret.ID
+		br.ReturnBooking = ret
+		h.logger.Info(fmt.Sprintf("Return booking %d created for booking %d", ret.ID, br.ID))
+	}
+	br.ReturnTrip = nil
+
+	h.notifier.BookingConfirmed(c.Request().Context(), br)
+
+	h.logger.Info(fmt.Sprintf("Booking created successfully: ID %d", br.ID))
+	return c.JSON(http.StatusCreated, br)
+}
+
+// attributeToOrganization bills the booking and its return leg to the organization they name and writes
+// the error response if that is not allowed. Bookings made with an API key always go to the key's
+// organization. It returns false when the request cannot continue.
+func (h *BookRideHandler) attributeToOrganization(c echo.Context, bookings ...*models.BookRide) (bool, error) {
+	keyOrgID, viaAPIKey := middleware.ConvertToInt64(c.Get("organization_id"))
+	for _, br := range bookings {
+		if br == nil {
+			continue
+		}
+		if viaAPIKey {
+			if br.OrganizationID != nil && *br.OrganizationID != keyOrgID {
+				return false, c.JSON(http.StatusForbidden, map[string]string{"error": "API keys can only book for their own organization"})
+			}
+			br.OrganizationID = &keyOrgID
+		}
+		if h.orgs == nil {
+			br.OrganizationID = nil
+			continue
+		}
+		err := h.orgs.Attribute(c.Request().Context(), br, br.UserID)
+		if errors.Is(err, organizations.ErrNotMember) {
+			return false, c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		if errors.Is(err, organizations.ErrInvalidBooking) {
+			return false, c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to attribute booking for %s to organization: %s", br.Email, err.Error()))
+			return false, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to check organization account"})
+		}
+	}
+	return true, nil
+}
+
+// prepareBooking sets the initial status, resolved zones and server-side quote of a new booking
+func (h *BookRideHandler) prepareBooking(br *models.BookRide, trip *zones.Trip, zone *models.ServiceZone) {
 	br.BookStatus = "Pending"
 	br.RideStatus = "Pending"
 
+	// Record the resolved zones; cross-zone long trips wait for an admin before drivers can accept them
+	trip.Apply(br)
+	if trip.RequiresApproval {
+		h.logger.Info(fmt.Sprintf("Booking for %s flagged for manual approval: %s", br.Email, trip.ApprovalReason))
+	}
+
+	// Price the booking server-side; never trust amounts sent by the client
+	h.pricing.PriceBooking(br, zone)
+	br.ReturnBookingID = nil
+	br.ReturnBooking = nil
+	br.OutboundBookingID = nil
+	br.SeriesID = nil
+	br.OccurrenceDate = nil
+}
+
+// errCreateBooking is returned by placeBooking when the booking could not be saved
+var errCreateBooking = errors.New("error creating book ride")
+
+// placeBooking saves a booking and places an authorization hold for its quoted fare.
+// A booking whose hold fails is cancelled again.
+func (h *BookRideHandler) placeBooking(c echo.Context, br *models.BookRide) error {
 	if err := h.repo.Create(c.Request().Context(), br); err != nil {
 		h.logger.Err(fmt.Sprintf("Error creating book ride: %s", err.Error()))
+		return errCreateBooking
+	}
+
+	// Bookings billed to an organization are invoiced monthly instead of held on a card
+	if h.payments != nil && !br.IsInvoiced() {
+		if _, err := h.payments.AuthorizeBooking(c.Request().Context(), br, br.PaymentMethod); err != nil {
+			h.logger.Warn(fmt.Sprintf("Payment authorization failed for booking %d: %s", br.ID, err.Error()))
+			if cancelErr := h.repo.Cancel(c.Request().Context(), br.ID, "payment authorization failed"); cancelErr != nil {
+				h.logger.Err(fmt.Sprintf("Failed to cancel booking %d after payment failure: %s", br.ID, cancelErr.Error()))
+			}
+			return err
+		}
+	}
+	br.PaymentMethod = ""
+	return nil
+}
+
+// placeBookingErrorResponse renders a placeBooking failure
+func placeBookingErrorResponse(c echo.Context, err error) error {
+	if errors.Is(err, payments.ErrDeclined) {
+		return c.JSON(http.StatusPaymentRequired, map[string]string{"error": "payment authorization declined"})
+	}
+	if errors.Is(err, errCreateBooking) {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "error creating book ride"})
 	}
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to authorize payment"})
+}
 
-	h.logger.Info(fmt.Sprintf("Booking created successfully: ID %d", br.ID))
-	return c.JSON(http.StatusCreated, br)
+// returnLeg builds the return booking of a round trip, reversing the route of the outbound booking
+func returnLeg(br *models.BookRide) *models.BookRide {
+	return &models.BookRide{
+		UserID:             br.UserID,
+		YourName:           br.YourName,
+		Email:              br.Email,
+		PhoneNumber:        br.PhoneNumber,
+		PassengerName:      br.PassengerName,
+		PassengerEmail:     br.PassengerEmail,
+		PassengerPhone:     br.PassengerPhone,
+		Notifications:      br.Notifications,
+		RideType:           br.RideType,
+		BookingMode:        br.BookingMode,
+		PickupLocation:     br.DropoffLocation,
+		DropoffLocation:    br.PickupLocation,
+		Pickup:             br.Dropoff,
+		Dropoff:            br.Pickup,
+		Stops:              br.ReturnTrip.Stops,
+		AddOns:             br.AddOns,
+		Date:               br.ReturnTrip.Date,
+		Time:               br.ReturnTrip.Time,
+		NumberOfPassengers: br.NumberOfPassengers,
+		NumberOfLuggage:    br.NumberOfLuggage,
+		AdditionalNotes:    br.AdditionalNotes,
+		PaymentMethod:      br.PaymentMethod,
+		OrganizationID:     br.OrganizationID,
+		CostCenter:         br.CostCenter,
+	}
+}
+
+// startFlightTracking clears client-supplied tracking fields of a flight and records the
+// pickup time that delays are measured from
+func startFlightTracking(flight *models.FlightDetails, date, clock string) {
+	if flight == nil {
+		return
+	}
+	flight.Status = models.FlightStatusScheduled
+	flight.EstimatedArrival = nil
+	flight.CheckedAt = nil
+	flight.OriginalPickup = date + " " + clock
+}
+
+// resolveStops resolves the structured location of each intermediate stop
+func (h *BookRideHandler) resolveStops(c echo.Context, stops []models.BookingStop) {
+	for i := range stops {
+		stops[i].Place = h.resolveLocation(c, stops[i].Address, stops[i].Place)
+	}
 }
 
 func (h *BookRideHandler) GetByEmail(c echo.Context) error {
@@ -126,14 +427,92 @@ func (h *BookRideHandler) Accept(c echo.Context) error {
 		return c.JSON(http.StatusForbidden, map[string]string{"error": "access denied: driver role required"})
 	}
 
+	// Flagged trips cannot be accepted until an admin approves them
+	if booking, err := h.repo.GetByID(c.Request().Context(), id); err == nil && booking.RequiresApproval && booking.ApprovedAt == nil {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "booking is awaiting admin approval"})
+	}
+
 	if err := h.repo.Accept(c.Request().Context(), id, driverID); err != nil {
 		h.logger.Err(fmt.Sprintf("Failed to accept book ride: %s", err.Error()))
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "error accepting book ride"})
 	}
+	if booking, err := h.repo.GetByID(c.Request().Context(), id); err == nil {
+		h.notifier.DriverAssigned(c.Request().Context(), booking)
+	}
 	h.logger.Info(fmt.Sprintf("Booking accepted successfully: ID %d", id))
 	return c.JSON(http.StatusOK, map[string]string{"message": "booking accepted successfully"})
 }
 
+// StartRide marks an accepted ride as in progress once the passenger is on board (driver only)
+func (h *BookRideHandler) StartRide(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	driverID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	if err := h.repo.Start(c.Request().Context(), id, driverID); err != nil {
+		h.logger.Warn(fmt.Sprintf("Failed to start ride %d for driver %d: %s", id, driverID, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	h.logger.Info(fmt.Sprintf("Ride started: ID %d", id))
+	return c.JSON(http.StatusOK, map[string]string{"message": "ride started successfully"})
+}
+
+// GetDriverRides lists the rides assigned to the authenticated driver, add-ons first
+func (h *BookRideHandler) GetDriverRides(c echo.Context) error {
+	driverIDClaim := c.Get("user_id")
+	driverID, ok := middleware.ConvertToInt64(driverIDClaim)
+	if !ok {
+		h.logger.Warn(fmt.Sprintf("Invalid driver ID type: %T, value: %v", driverIDClaim, driverIDClaim))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	bookings, err := h.repo.GetByDriverID(c.Request().Context(), driverID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get bookings of driver %d: %s", driverID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "error getting driver rides"})
+	}
+
+	rides := make([]*models.DriverRide, len(bookings))
+	for i, booking := range bookings {
+		rides[i] = models.NewDriverRide(booking)
+	}
+	return c.JSON(http.StatusOK, rides)
+}
+
+// GetDriverRide returns the details of a ride assigned to the driver, or of a pending ride they may accept
+func (h *BookRideHandler) GetDriverRide(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	driverIDClaim := c.Get("user_id")
+	driverID, ok := middleware.ConvertToInt64(driverIDClaim)
+	if !ok {
+		h.logger.Warn(fmt.Sprintf("Invalid driver ID type: %T, value: %v", driverIDClaim, driverIDClaim))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	booking, err := h.repo.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+	}
+
+	assigned := booking.DriverID != nil && *booking.DriverID == driverID
+	available := booking.DriverID == nil && booking.BookStatus == models.BookStatusPending
+	if !assigned && !available {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "access denied"})
+	}
+
+	return c.JSON(http.StatusOK, models.NewDriverRide(booking))
+}
+
 func (h *BookRideHandler) GetByUserID(c echo.Context) error {
 	userIDClaim := c.Get("user_id")
 	userID, ok := middleware.ConvertToInt64(userIDClaim)
@@ -166,7 +545,7 @@ func (h *BookRideHandler) Update(c echo.Context) error {
 	}
 
 	// Validate the updates
-	if err := validation.ValidateUpdateBookRide(&updates); err != nil {
+	if err := validation.ValidateUpdateBookRide(&updates, h.countryCode); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
@@ -217,9 +596,51 @@ func (h *BookRideHandler) Update(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "cannot update cancelled or completed booking"})
 	}
 
-	// If date/time is being updated, validate 24-hour rule
+	if updates.DurationHours != nil && !booking.IsHourly() {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "duration is only allowed for hourly bookings"})
+	}
+	if updates.Stops != nil && len(*updates.Stops) > 0 && booking.IsHourly() {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "hourly bookings cannot have stops"})
+	}
+
+	// Add-ons must stay compatible with the ride type, including when only the ride type changes
+	if updates.AddOns != nil || updates.RideType != "" {
+		addOns := booking.AddOns
+		if updates.AddOns != nil {
+			addOns = *updates.AddOns
+		}
+		rideType := booking.RideType
+		if updates.RideType != "" {
+			rideType = updates.RideType
+		}
+		if ok, err := h.validateAddOns(c, addOns, rideType); !ok {
+			return err
+		}
+	}
+
+	// Re-resolve locations whose text or structure changed
+	if updates.Pickup != nil && updates.PickupLocation == "" {
+		updates.PickupLocation = updates.Pickup.FormattedAddress
+	}
+	if updates.Dropoff != nil && updates.DropoffLocation == "" {
+		updates.DropoffLocation = updates.Dropoff.FormattedAddress
+	}
+	if updates.PickupLocation != "" {
+		updates.Pickup = h.resolveLocation(c, updates.PickupLocation, updates.Pickup)
+	}
+	if updates.DropoffLocation != "" {
+		updates.Dropoff = h.resolveLocation(c, updates.DropoffLocation, updates.Dropoff)
+	}
+	if updates.Stops != nil {
+		h.resolveStops(c, *updates.Stops)
+	}
+
+	// Validate the resulting trip and schedule against the service zones
 	dateToCheck := booking.Date
 	timeToCheck := booking.Time
+	pickupToCheck := booking.PickupLocation
+	pickupPlace := booking.Pickup
+	dropoffPlace := booking.Dropoff
 
 	if updates.Date != "" {
 		dateToCheck = updates.Date
@@ -227,10 +648,29 @@ func (h *BookRideHandler) Update(c echo.Context) error {
 	if updates.Time != "" {
 		timeToCheck = updates.Time
 	}
+	if updates.PickupLocation != "" {
+		pickupToCheck = updates.PickupLocation
+		pickupPlace = updates.Pickup
+	}
+	if updates.DropoffLocation != "" {
+		dropoffPlace = updates.Dropoff
+	}
 
-	if err := validation.ValidateBookingDateTime(dateToCheck, timeToCheck); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	trip, err := h.resolveTrip(c, pickupToCheck, pickupPlace, dropoffPlace)
+	if trip == nil {
+		return err
 	}
+	zone := trip.PickupZone
+	if err := validation.ValidateBookingDateTime(zone, dateToCheck, timeToCheck, time.Now()); err != nil {
+		return scheduleErrorResponse(c, err)
+	}
+
+	// A new flight or pickup time restarts flight tracking from the new pickup
+	if updates.Flight == nil && booking.Flight != nil && (updates.Date != "" || updates.Time != "") {
+		flight := *booking.Flight
+		updates.Flight = &flight
+	}
+	startFlightTracking(updates.Flight, dateToCheck, timeToCheck)
 
 	// Perform the update
 	if err := h.repo.Update(c.Request().Context(), id, &updates); err != nil {
@@ -238,6 +678,14 @@ func (h *BookRideHandler) Update(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update booking"})
 	}
 
+	// A changed route is re-checked for manual approval
+	if updates.PickupLocation != "" || updates.DropoffLocation != "" {
+		if err := h.repo.UpdateZones(c.Request().Context(), id, trip.PickupZoneID(), trip.DropoffZoneID(), trip.RequiresApproval, trip.ApprovalReason); err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to update service zones of booking %d: %s", id, err.Error()))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "booking updated but failed to update service zones"})
+		}
+	}
+
 	// Get updated booking
 	updatedBooking, err := h.repo.GetByID(c.Request().Context(), id)
 	if err != nil {
@@ -245,6 +693,29 @@ func (h *BookRideHandler) Update(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "booking updated but failed to retrieve updated data"})
 	}
 
+	// Re-quote when a priced field changed
+	if updates.RideType != "" || updates.NumberOfLuggage != nil || updates.Date != "" ||
+		updates.PickupLocation != "" || updates.DropoffLocation != "" || updates.Stops != nil || updates.DurationHours != nil ||
+		updates.Flight != nil || updates.AddOns != nil {
+		items := h.pricing.Requote(updatedBooking, zone)
+		if err := h.repo.UpdateQuote(c.Request().Context(), id, items); err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to re-quote booking %d: %s", id, err.Error()))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "booking updated but failed to update fare"})
+		}
+		updatedBooking.LineItems = items
+		updatedBooking.QuotedAmount = items.Total()
+
+		// A higher fare needs a larger hold
+		h.reauthorizeHold(c, updatedBooking)
+
+		// A higher fare may need the organization's approval again
+		if h.orgs != nil {
+			if err := h.orgs.RecheckApproval(c.Request().Context(), updatedBooking); err != nil {
+				h.logger.Err(fmt.Sprintf("Failed to recheck organization approval of booking %d: %s", id, err.Error()))
+			}
+		}
+	}
+
 	h.logger.Info(fmt.Sprintf("Booking updated successfully: ID %d", id))
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "booking updated successfully",
@@ -252,7 +723,8 @@ func (h *BookRideHandler) Update(c echo.Context) error {
 	})
 }
 
-// Cancel handles cancelling a booking (authenticated users or via secure token)
+// Cancel handles cancelling a booking (authenticated users or via secure token).
+// With ?preview=true it only returns the fee the cancellation policy would charge.
 func (h *BookRideHandler) Cancel(c echo.Context) error {
 	idParam := c.Param("id")
 	id, err := strconv.ParseInt(idParam, 10, 64)
@@ -261,7 +733,8 @@ func (h *BookRideHandler) Cancel(c echo.Context) error {
 	}
 
 	var req struct {
-		Reason string `json:"reason,omitempty"`
+		Reason    string `json:"reason,omitempty"`
+		AcceptFee bool   `json:"accept_fee,omitempty"`
 	}
 	if err := c.Bind(&req); err != nil {
 		// Reason is optional, so binding errors are not critical
@@ -356,32 +829,65 @@ func (h *BookRideHandler) Cancel(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "cannot cancel completed booking"})
 	}
 
-	// Validate 24-hour cancellation rule
-	// Parse the booking date and time to check if it's within 24 hours
-	bookingDateTime, err := time.Parse("2006-01-02 15:04", booking.Date+" "+booking.Time)
+	// Work out the fee under the cancellation policy for this ride type
+	policy, err := h.policies.GetForRideType(c.Request().Context(), booking.RideType)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to load cancellation policy for booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to load cancellation policy"})
+	}
+
+	loc, err := h.zones.PickupLocation(c.Request().Context(), booking)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to load pickup time zone for booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to validate booking time"})
+	}
+
+	quote, err := pricing.CancellationFee(policy, booking, loc, time.Now())
 	if err != nil {
 		h.logger.Err(fmt.Sprintf("Failed to parse booking date/time for cancellation check: %s", err.Error()))
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to validate booking time"})
 	}
 
-	now := time.Now()
-	minCancelTime := now.Add(24 * time.Hour)
+	// Preview mode only reports the fee so the rider can confirm
+	if c.QueryParam("preview") == "true" {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message":      "cancellation preview",
+			"cancellation": quote,
+		})
+	}
 
-	if bookingDateTime.Before(minCancelTime) {
-		h.logger.Warn(fmt.Sprintf("Cancellation denied: booking %d is within 24 hours (booking: %s, now: %s)", 
-			id, bookingDateTime.Format("2006-01-02 15:04"), now.Format("2006-01-02 15:04")))
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "cannot cancel booking less than 24 hours before scheduled time"})
+	if quote.Fee > 0 && !req.AcceptFee {
+		h.logger.Info(fmt.Sprintf("Cancellation of booking %d requires accepting a fee of %s", id, quote.Fee.Format(quote.Currency)))
+		return c.JSON(http.StatusConflict, map[string]interface{}{
+			"error":        "a cancellation fee applies; resend with accept_fee set to true to confirm",
+			"cancellation": quote,
+		})
 	}
 
 	// Perform the cancellation
-	if err := h.repo.Cancel(c.Request().Context(), id, req.Reason); err != nil {
+	if err := h.repo.CancelWithFee(c.Request().Context(), id, req.Reason, quote.Fee); err != nil {
 		h.logger.Err(fmt.Sprintf("Failed to cancel booking %d: %s", id, err.Error()))
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to cancel booking"})
 	}
 
-	h.logger.Info(fmt.Sprintf("Booking cancelled successfully: ID %d, Reason: %s", id, req.Reason))
-	return c.JSON(http.StatusOK, map[string]string{
-		"message": "booking cancelled successfully",
+	// Charge the fee from the authorization hold, or release the hold entirely;
+	// fees on organization bookings are invoiced instead. Failures are flagged for admin follow-up.
+	if h.payments != nil && !booking.IsInvoiced() {
+		if quote.Fee > 0 {
+			if _, err := h.payments.CaptureBooking(c.Request().Context(), id, quote.Fee); err != nil {
+				h.flagPaymentIssue(c, booking, fmt.Sprintf("cancellation fee of %s could not be captured: %s", quote.Fee.Format(quote.Currency), err.Error()))
+			}
+		} else if err := h.payments.VoidBooking(c.Request().Context(), id); err != nil {
+			h.flagPaymentIssue(c, booking, fmt.Sprintf("hold could not be released: %s", err.Error()))
+		}
+	}
+
+	h.notifier.BookingCancelled(c.Request().Context(), booking)
+
+	h.logger.Info(fmt.Sprintf("Booking cancelled successfully: ID %d, Reason: %s, Fee: %s", id, req.Reason, quote.Fee.Format(quote.Currency)))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "booking cancelled successfully",
+		"cancellation": quote,
 	})
 }
 
@@ -431,8 +937,8 @@ func (h *BookRideHandler) GenerateUpdateLink(c echo.Context) error {
 	h.logger.Info(fmt.Sprintf("Update token generated for booking %d, email %s", id, email))
 
 	// Send email if email service is configured
-	if h.emailService != nil {
-		if err := h.emailService.SendBookingUpdateEmail(email, token, booking); err != nil {
+	if h.notifier.Emails() {
+		if err := h.notifier.BookingLink(c.Request().Context(), email, token, booking); err != nil {
 			h.logger.Err(fmt.Sprintf("Failed to send update email to %s: %s", email, err.Error()))
 			// Don't fail the request if email fails
 			return c.JSON(http.StatusOK, map[string]interface{}{
@@ -452,4 +958,281 @@ func (h *BookRideHandler) GenerateUpdateLink(c echo.Context) error {
 			"update_token": token,
 		})
 	}
-}
\ No newline at end of file
+}
+
+// Complete handles a driver marking an assigned ride as completed and recomputes the final fare
+func (h *BookRideHandler) Complete(c echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	driverIDClaim := c.Get("user_id")
+	driverID, ok := middleware.ConvertToInt64(driverIDClaim)
+	if !ok {
+		h.logger.Warn(fmt.Sprintf("Invalid driver ID type: %T, value: %v", driverIDClaim, driverIDClaim))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	var req models.CompleteRideRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	booking, err := h.repo.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+	}
+
+	if booking.DriverID == nil || *booking.DriverID != driverID {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "booking is not assigned to you"})
+	}
+
+	finalItems, err := h.pricing.Final(booking, &req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	// Time on duty is only recorded for hourly charters
+	var actualMinutes *int
+	if booking.IsHourly() {
+		actualMinutes = &req.ActualMinutes
+	}
+
+	if err := h.repo.Complete(c.Request().Context(), id, driverID, finalItems, actualMinutes); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to complete booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	finalAmount := finalItems.Total()
+
+	// Settle the hold for the final fare; the ride stays completed and a failed capture flags
+	// the booking for admin follow-up
+	if h.payments != nil && !booking.IsInvoiced() {
+		if _, err := h.payments.CaptureBooking(c.Request().Context(), id, finalAmount); err != nil {
+			h.flagPaymentIssue(c, booking, fmt.Sprintf("final fare of %s could not be captured: %s", finalAmount.Format(booking.Currency), err.Error()))
+		}
+	}
+
+	booking.BookStatus = models.BookStatusCompleted
+	booking.RideStatus = models.RideStatusCompleted
+	booking.FinalAmount = &finalAmount
+	booking.FinalLineItems = finalItems
+	booking.ActualMinutes = actualMinutes
+
+	// Credit the driver's share; a failed posting is logged for admin follow-up
+	if h.ledger != nil {
+		if err := h.ledger.RecordCompletion(c.Request().Context(), booking); err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to record earnings for booking %d: %s", id, err.Error()))
+		}
+	}
+
+	if h.notifier.Emails() {
+		h.notifier.RideCompleted(c.Request().Context(), booking, h.receiptAttachments(c, booking)...)
+	}
+
+	message := "booking completed successfully"
+	if booking.PaymentIssue != "" {
+		message = "booking completed, but the payment could not be captured and was flagged for follow-up"
+	}
+
+	h.logger.Info(fmt.Sprintf("Booking completed: ID %d, final fare %s", id, finalAmount.Format(booking.Currency)))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": message,
+		"booking": booking,
+	})
+}
+
+// receiptAttachments renders the PDF receipt of a completed booking for the completion email;
+// the email is still sent without it if the receipt cannot be generated
+func (h *BookRideHandler) receiptAttachments(c echo.Context, booking *models.BookRide) []email.Attachment {
+	if h.receipts == nil {
+		return nil
+	}
+	doc, err := h.receipts.ForBooking(c.Request().Context(), booking)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to issue receipt for booking %d: %s", booking.ID, err.Error()))
+		return nil
+	}
+	pdf, err := receipts.RenderPDF(doc)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to render receipt %s: %s", doc.Number, err.Error()))
+		return nil
+	}
+	return []email.Attachment{{Filename: doc.Filename(), Content: pdf}}
+}
+
+// AdjustFare handles an admin override of a booking fare (admin only, audited)
+func (h *BookRideHandler) AdjustFare(c echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	adminIDClaim := c.Get("user_id")
+	adminID, ok := middleware.ConvertToInt64(adminIDClaim)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid admin authentication"})
+	}
+
+	var req models.AdjustFareRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	if req.Reason = strings.TrimSpace(req.Reason); req.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "reason is required"})
+	}
+	if req.Amount < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "amount cannot be negative"})
+	}
+
+	adj := &models.FareAdjustment{
+		BookingID: id,
+		AdminID:   &adminID,
+		NewAmount: req.Amount,
+		Reason:    req.Reason,
+	}
+	if err := h.repo.AdjustFare(c.Request().Context(), adj); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to adjust fare for booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to adjust fare"})
+	}
+
+	booking, err := h.repo.GetByID(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get adjusted booking %d: %s", id, err.Error()))
+	} else {
+		// A raised quote needs a larger hold
+		if adj.Field == "quoted_amount" && adj.NewAmount > adj.PreviousAmount {
+			h.reauthorizeHold(c, booking)
+		}
+		// Changes to a completed ride's final fare also change the driver's earnings
+		if h.ledger != nil {
+			if err := h.ledger.RecordFareAdjustment(c.Request().Context(), booking, adj); err != nil {
+				h.logger.Err(fmt.Sprintf("Failed to record fare adjustment %d in ledger: %s", adj.ID, err.Error()))
+			}
+		}
+	}
+
+	h.logger.Info(fmt.Sprintf("Admin %d adjusted %s of booking %d from %s to %s: %s", adminID, adj.Field, id,
+		adj.PreviousAmount.Format(adj.Currency), adj.NewAmount.Format(adj.Currency), adj.Reason))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":    "fare adjusted successfully",
+		"adjustment": adj,
+	})
+}
+
+// GetFareAdjustments returns the fare audit trail of a booking (admin only)
+func (h *BookRideHandler) GetFareAdjustments(c echo.Context) error {
+	idParam := c.Param("id")
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	adjustments, err := h.repo.GetFareAdjustments(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get fare adjustments for booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get fare adjustments"})
+	}
+	return c.JSON(http.StatusOK, adjustments)
+}
+
+// Approve clears a booking flagged for manual approval so drivers can accept it (admin only)
+func (h *BookRideHandler) Approve(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	adminID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	if err := h.repo.Approve(c.Request().Context(), id, adminID); err != nil {
+		h.logger.Warn(fmt.Sprintf("Failed to approve booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	booking, err := h.repo.GetByID(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get approved booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "booking approved but failed to retrieve it"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Booking %d approved by admin %d", id, adminID))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "booking approved successfully",
+		"booking": booking,
+	})
+}
+
+// reauthorizeHold raises the hold of a booking whose quote rose; a declined payment method flags the booking
+func (h *BookRideHandler) reauthorizeHold(c echo.Context, booking *models.BookRide) {
+	if h.payments == nil || booking.IsInvoiced() {
+		return
+	}
+	if _, err := h.payments.ReauthorizeBooking(c.Request().Context(), booking); err != nil {
+		h.flagPaymentIssue(c, booking, fmt.Sprintf("hold could not be raised to the new quote of %s: %s", booking.QuotedAmount.Format(booking.Currency), err.Error()))
+	}
+}
+
+// flagPaymentIssue records a payment that needs admin follow-up on the booking
+func (h *BookRideHandler) flagPaymentIssue(c echo.Context, booking *models.BookRide, issue string) {
+	h.logger.Err(fmt.Sprintf("Payment issue on booking %d: %s", booking.ID, issue))
+	if err := h.repo.SetPaymentIssue(c.Request().Context(), booking.ID, issue); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to flag payment issue on booking %d: %s", booking.ID, err.Error()))
+		return
+	}
+	booking.PaymentIssue = issue
+}
+
+// GetPaymentIssues lists bookings whose payment could not be settled (admin only)
+func (h *BookRideHandler) GetPaymentIssues(c echo.Context) error {
+	bookings, err := h.repo.GetPaymentIssues(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get bookings with payment issues: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "error getting bookings with payment issues"})
+	}
+	return c.JSON(http.StatusOK, bookings)
+}
+
+// ResolvePaymentIssue clears the payment issue of a booking once an admin has settled it (admin only)
+func (h *BookRideHandler) ResolvePaymentIssue(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	adminID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	if err := h.repo.SetPaymentIssue(c.Request().Context(), id, ""); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to resolve payment issue of booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to resolve payment issue"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Payment issue of booking %d resolved by admin %d", id, adminID))
+	return c.JSON(http.StatusOK, map[string]string{"message": "payment issue resolved successfully"})
+}
+
+// GetPendingApproval lists open bookings waiting for manual approval (admin only)
+func (h *BookRideHandler) GetPendingApproval(c echo.Context) error {
+	bookings, err := h.repo.GetPendingApproval(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get bookings pending approval: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "error getting bookings pending approval"})
+	}
+	return c.JSON(http.StatusOK, bookings)
+}
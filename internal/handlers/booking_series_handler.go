@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/pricing"
+	"github.com/diagnosis/luxsuv-v4/internal/recurrence"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+// BookingSeriesHandler manages recurring bookings. Single occurrences are changed or
+// cancelled through the regular booking endpoints.
+type BookingSeriesHandler struct {
+	repo      repository.BookingSeriesRepository
+	bookings  *BookRideHandler
+	generator *recurrence.Generator
+	logger    *logger.Logger
+}
+
+func NewBookingSeriesHandler(repo repository.BookingSeriesRepository, bookings *BookRideHandler, generator *recurrence.Generator, logger *logger.Logger) *BookingSeriesHandler {
+	return &BookingSeriesHandler{
+		repo:      repo,
+		bookings:  bookings,
+		generator: generator,
+		logger:    logger,
+	}
+}
+
+// Create starts a booking series and books the occurrences within the generation horizon
+func (h *BookingSeriesHandler) Create(c echo.Context) error {
+	userID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	var req models.CreateBookingSeriesRequest
+	if err := c.Bind(&req); err != nil {
+		h.logger.Warn(fmt.Sprintf("Invalid request body: %s", err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	rule, err := recurrence.Parse(req.Recurrence)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	br := &req.BookRide
+	if br.ReturnTrip != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "recurring bookings cannot have a return trip; create a series for each direction"})
+	}
+	if br.Flight != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "recurring bookings cannot track a flight"})
+	}
+	if err := validation.ValidateBookRide(br, h.bookings.countryCode); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if ok, err := h.bookings.validateAddOns(c, br.AddOns, br.RideType); !ok {
+		return err
+	}
+
+	dates, _, err := rule.Dates(br.Date, br.Date, time.Now().UTC().AddDate(1, 0, 0).Format("2006-01-02"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if len(dates) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "recurrence rule has no occurrences"})
+	}
+
+	// Resolve the route once; every occurrence reuses it
+	br.Pickup = h.bookings.resolveLocation(c, br.PickupLocation, br.Pickup)
+	if br.DropoffLocation != "" {
+		br.Dropoff = h.bookings.resolveLocation(c, br.DropoffLocation, br.Dropoff)
+	}
+	h.bookings.resolveStops(c, br.Stops)
+
+	if trip, err := h.bookings.resolveTrip(c, br.PickupLocation, br.Pickup, br.Dropoff); trip == nil {
+		return err
+	}
+
+	series := &models.BookingSeries{
+		UserID:        userID,
+		Recurrence:    rule.String(),
+		StartDate:     br.Date,
+		Time:          br.Time,
+		Template:      models.NewSeriesTemplate(br),
+		PaymentMethod: br.PaymentMethod,
+		Status:        models.SeriesStatusActive,
+	}
+	if err := h.repo.Create(c.Request().Context(), series); err != nil {
+		h.logger.Err(fmt.Sprintf("Error creating booking series: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "error creating booking series"})
+	}
+
+	created, skipped, err := h.generator.Generate(c.Request().Context(), series)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to generate occurrences of series %d: %s", series.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "booking series created but failed to generate bookings"})
+	}
+	series.Bookings = created
+
+	h.logger.Info(fmt.Sprintf("Booking series %d created for user %d: %s", series.ID, userID, series.Recurrence))
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"series":  series,
+		"skipped": skipped,
+	})
+}
+
+// GetByUserID lists the booking series of the authenticated user
+func (h *BookingSeriesHandler) GetByUserID(c echo.Context) error {
+	userID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	list, err := h.repo.GetByUserID(c.Request().Context(), userID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get booking series of user %d: %s", userID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get booking series"})
+	}
+
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetByID returns a booking series with its generated bookings
+func (h *BookingSeriesHandler) GetByID(c echo.Context) error {
+	series, err := h.ownedSeries(c)
+	if series == nil {
+		return err
+	}
+
+	bookings, err := h.bookings.repo.GetBySeriesID(c.Request().Context(), series.ID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get bookings of series %d: %s", series.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get series bookings"})
+	}
+	series.Bookings = bookings
+
+	return c.JSON(http.StatusOK, series)
+}
+
+// Update changes the series template and every upcoming open occurrence.
+// Occurrences whose new pickup time breaks a schedule rule are left unchanged and reported.
+func (h *BookingSeriesHandler) Update(c echo.Context) error {
+	var req models.UpdateBookingSeriesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	check := &models.UpdateBookRideRequest{
+		YourName:           req.YourName,
+		PhoneNumber:        req.PhoneNumber,
+		Time:               req.Time,
+		NumberOfPassengers: req.NumberOfPassengers,
+		NumberOfLuggage:    req.NumberOfLuggage,
+	}
+	if req.AdditionalNotes != nil {
+		check.AdditionalNotes = *req.AdditionalNotes
+	}
+	if err := validation.ValidateUpdateBookRide(check, h.bookings.countryCode); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	series, err := h.ownedSeries(c)
+	if series == nil {
+		return err
+	}
+	if series.Status != models.SeriesStatusActive {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "cannot update a cancelled or completed series"})
+	}
+
+	if req.YourName != "" {
+		series.Template.YourName = req.YourName
+	}
+	if req.PhoneNumber != "" {
+		if err := validation.ValidatePhone("phone number", &req.PhoneNumber, h.bookings.countryCode); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		series.Template.PhoneNumber = req.PhoneNumber
+	}
+	if req.Time != "" {
+		series.Time = req.Time
+	}
+	if req.NumberOfPassengers != nil {
+		series.Template.NumberOfPassengers = *req.NumberOfPassengers
+	}
+	if req.NumberOfLuggage != nil {
+		series.Template.NumberOfLuggage = *req.NumberOfLuggage
+	}
+	if req.AdditionalNotes != nil {
+		series.Template.AdditionalNotes = *req.AdditionalNotes
+	}
+
+	if err := h.repo.Update(c.Request().Context(), series); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to update booking series %d: %s", series.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update booking series"})
+	}
+
+	upcoming, err := h.upcomingOccurrences(c, series.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "booking series updated but failed to get its bookings"})
+	}
+
+	var updated []int64
+	var skipped []recurrence.SkippedOccurrence
+	for _, booking := range upcoming {
+		if err := h.updateOccurrence(c, booking, check, req.AdditionalNotes); err != nil {
+			h.logger.Warn(fmt.Sprintf("Skipped update of booking %d in series %d: %s", booking.ID, series.ID, err.Error()))
+			skipped = append(skipped, recurrence.SkippedOccurrence{Date: booking.Date, Reason: err.Error()})
+			continue
+		}
+		updated = append(updated, booking.ID)
+	}
+
+	h.logger.Info(fmt.Sprintf("Booking series %d updated: %d bookings changed, %d skipped", series.ID, len(updated), len(skipped)))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":          "booking series updated successfully",
+		"series":           series,
+		"updated_bookings": updated,
+		"skipped":          skipped,
+	})
+}
+
+// updateOccurrence applies a series update to one of its bookings and re-quotes it when luggage changed
+func (h *BookingSeriesHandler) updateOccurrence(c echo.Context, booking *models.BookRide, change *models.UpdateBookRideRequest, notes *string) error {
+	ctx := c.Request().Context()
+
+	updates := *change
+	updates.AdditionalNotes = booking.AdditionalNotes
+	if notes != nil {
+		updates.AdditionalNotes = *notes
+	}
+
+	trip, err := h.bookings.zones.ResolveTrip(ctx, booking.PickupLocation, booking.Pickup, booking.Dropoff)
+	if err != nil {
+		return err
+	}
+	if updates.Time != "" {
+		if err := validation.ValidateBookingDateTime(trip.PickupZone, booking.Date, updates.Time, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := h.bookings.repo.Update(ctx, booking.ID, &updates); err != nil {
+		return err
+	}
+
+	if updates.NumberOfLuggage != nil && *updates.NumberOfLuggage != booking.NumberOfLuggage {
+		booking.NumberOfLuggage = *updates.NumberOfLuggage
+		items := h.bookings.pricing.Requote(booking, trip.PickupZone)
+		if err := h.bookings.repo.UpdateQuote(ctx, booking.ID, items); err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to re-quote booking %d: %s", booking.ID, err.Error()))
+		}
+	}
+	return nil
+}
+
+// Cancel stops a series and cancels its upcoming occurrences that can be cancelled free of charge.
+// Occurrences already inside their cancellation window are kept and must be cancelled individually.
+func (h *BookingSeriesHandler) Cancel(c echo.Context) error {
+	var req struct {
+		Reason string `json:"reason,omitempty"`
+	}
+	if err := c.Bind(&req); err != nil || req.Reason == "" {
+		req.Reason = "Series cancelled by user"
+	}
+
+	series, err := h.ownedSeries(c)
+	if series == nil {
+		return err
+	}
+	if series.Status == models.SeriesStatusCancelled {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "booking series is already cancelled"})
+	}
+
+	series.Status = models.SeriesStatusCancelled
+	if err := h.repo.Update(c.Request().Context(), series); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to cancel booking series %d: %s", series.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to cancel booking series"})
+	}
+
+	upcoming, err := h.upcomingOccurrences(c, series.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "booking series cancelled but failed to get its bookings"})
+	}
+
+	ctx := c.Request().Context()
+	var cancelled []int64
+	var kept []*models.CancellationQuote
+	for _, booking := range upcoming {
+		policy, err := h.bookings.policies.GetForRideType(ctx, booking.RideType)
+		if err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to load cancellation policy for booking %d: %s", booking.ID, err.Error()))
+			continue
+		}
+		loc, err := h.bookings.zones.PickupLocation(ctx, booking)
+		if err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to load pickup time zone for booking %d: %s", booking.ID, err.Error()))
+			continue
+		}
+		quote, err := pricing.CancellationFee(policy, booking, loc, time.Now())
+		if err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to quote cancellation of booking %d: %s", booking.ID, err.Error()))
+			continue
+		}
+		if quote.Fee > 0 {
+			kept = append(kept, quote)
+			continue
+		}
+
+		if err := h.bookings.repo.CancelWithFee(ctx, booking.ID, req.Reason, 0); err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to cancel booking %d of series %d: %s", booking.ID, series.ID, err.Error()))
+			continue
+		}
+		if h.bookings.payments != nil {
+			if err := h.bookings.payments.VoidBooking(ctx, booking.ID); err != nil {
+				h.logger.Err(fmt.Sprintf("Failed to void payment for cancelled booking %d: %s", booking.ID, err.Error()))
+			}
+		}
+		cancelled = append(cancelled, booking.ID)
+	}
+
+	h.logger.Info(fmt.Sprintf("Booking series %d cancelled: %d bookings cancelled, %d kept", series.ID, len(cancelled), len(kept)))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":            "booking series cancelled successfully",
+		"cancelled_bookings": cancelled,
+		"kept_bookings":      kept,
+	})
+}
+
+// ownedSeries loads the series in the id path parameter and checks the caller owns it.
+// It returns nil and the written response when the series cannot be used.
+func (h *BookingSeriesHandler) ownedSeries(c echo.Context) (*models.BookingSeries, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid series ID"})
+	}
+
+	userID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return nil, c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	series, err := h.repo.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "booking series not found"})
+	}
+	if series.UserID != userID {
+		return nil, c.JSON(http.StatusForbidden, map[string]string{"error": "access denied"})
+	}
+	return series, nil
+}
+
+// upcomingOccurrences returns the open bookings of a series whose pickup is still ahead
+func (h *BookingSeriesHandler) upcomingOccurrences(c echo.Context, seriesID int64) ([]*models.BookRide, error) {
+	bookings, err := h.bookings.repo.GetBySeriesID(c.Request().Context(), seriesID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get bookings of series %d: %s", seriesID, err.Error()))
+		return nil, err
+	}
+
+	now := time.Now()
+	var upcoming []*models.BookRide
+	for _, booking := range bookings {
+		if booking.BookStatus == models.BookStatusCancelled || booking.BookStatus == models.BookStatusCompleted {
+			continue
+		}
+		loc, err := h.bookings.zones.PickupLocation(c.Request().Context(), booking)
+		if err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to load pickup time zone for booking %d: %s", booking.ID, err.Error()))
+			return nil, err
+		}
+		if pickupAt, err := booking.PickupAt(loc); err != nil || !pickupAt.After(now) {
+			continue
+		}
+		upcoming = append(upcoming, booking)
+	}
+	return upcoming, nil
+}
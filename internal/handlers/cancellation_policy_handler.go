@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type CancellationPolicyHandler struct {
+	repo   repository.CancellationPolicyRepository
+	logger *logger.Logger
+}
+
+func NewCancellationPolicyHandler(repo repository.CancellationPolicyRepository, logger *logger.Logger) *CancellationPolicyHandler {
+	return &CancellationPolicyHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// List returns all cancellation policies (admin only)
+func (h *CancellationPolicyHandler) List(c echo.Context) error {
+	policies, err := h.repo.List(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list cancellation policies: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list cancellation policies"})
+	}
+	return c.JSON(http.StatusOK, policies)
+}
+
+// Upsert creates or replaces the cancellation policy of a ride type (admin only)
+func (h *CancellationPolicyHandler) Upsert(c echo.Context) error {
+	var policy models.CancellationPolicy
+	if err := c.Bind(&policy); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	policy.RideType = strings.ToLower(strings.TrimSpace(c.Param("ride_type")))
+
+	if err := validation.ValidateCancellationPolicy(&policy); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if adminID, ok := middleware.ConvertToInt64(c.Get("user_id")); ok {
+		policy.UpdatedBy = &adminID
+	}
+
+	if err := h.repo.Upsert(c.Request().Context(), &policy); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to save cancellation policy %s: %s", policy.RideType, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save cancellation policy"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Cancellation policy %s saved: free until %dh, %d%% until %dh, %d%% after",
+		policy.RideType, policy.FreeUntilHours, policy.LateFeePercent, policy.LateUntilHours, policy.NoShowFeePercent))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "cancellation policy saved successfully",
+		"policy":  policy,
+	})
+}
+
+// Delete removes the cancellation policy of a ride type so it falls back to the default (admin only)
+func (h *CancellationPolicyHandler) Delete(c echo.Context) error {
+	rideType := strings.ToLower(strings.TrimSpace(c.Param("ride_type")))
+	if rideType == models.DefaultPolicyRideType {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "the default cancellation policy cannot be deleted"})
+	}
+
+	if err := h.repo.Delete(c.Request().Context(), rideType); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "cancellation policy not found"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to delete cancellation policy %s: %s", rideType, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete cancellation policy"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Cancellation policy %s deleted", rideType))
+	return c.JSON(http.StatusOK, map[string]string{"message": "cancellation policy deleted successfully"})
+}
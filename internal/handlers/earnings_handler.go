@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/ledger"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+// EarningsHandler serves driver earnings from the ledger and manages weekly payout batches
+type EarningsHandler struct {
+	ledger *ledger.Service
+	repo   repository.LedgerRepository
+	logger *logger.Logger
+}
+
+func NewEarningsHandler(ledger *ledger.Service, repo repository.LedgerRepository, logger *logger.Logger) *EarningsHandler {
+	return &EarningsHandler{
+		ledger: ledger,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetEarnings summarizes the authenticated driver's earnings between ?from and ?to (YYYY-MM-DD, inclusive),
+// defaulting to the current week
+func (h *EarningsHandler) GetEarnings(c echo.Context) error {
+	driverID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	from := ledger.WeekStart(time.Now())
+	to := from.AddDate(0, 0, 7)
+	if v := c.QueryParam("from"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "from must be a date in YYYY-MM-DD format"})
+		}
+		from = date
+	}
+	if v := c.QueryParam("to"); v != "" {
+		date, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "to must be a date in YYYY-MM-DD format"})
+		}
+		to = date.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "to must not be before from"})
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "date range cannot exceed one year"})
+	}
+
+	summary, entries, err := h.ledger.Earnings(c.Request().Context(), driverID, from, to)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get earnings for driver %d: %s", driverID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get earnings"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"summary": summary,
+		"entries": entries,
+	})
+}
+
+// GetDriverPayouts returns the authenticated driver's payouts, newest first
+func (h *EarningsHandler) GetDriverPayouts(c echo.Context) error {
+	driverID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	payouts, err := h.repo.GetPayoutsByDriverID(c.Request().Context(), driverID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get payouts for driver %d: %s", driverID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get payouts"})
+	}
+	return c.JSON(http.StatusOK, payouts)
+}
+
+// GeneratePayoutBatch creates the payout batch of a finished week (admin only).
+// The week defaults to the last full week (Monday to Sunday, UTC).
+func (h *EarningsHandler) GeneratePayoutBatch(c echo.Context) error {
+	adminID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid admin authentication"})
+	}
+
+	var req models.GeneratePayoutBatchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	weekStart := ledger.WeekStart(time.Now()).AddDate(0, 0, -7)
+	if req.WeekStart != "" {
+		date, err := time.Parse("2006-01-02", req.WeekStart)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "week_start must be a date in YYYY-MM-DD format"})
+		}
+		if date.Weekday() != time.Monday {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "week_start must be a Monday"})
+		}
+		weekStart = date
+	}
+
+	batch, err := h.ledger.GeneratePayoutBatch(c.Request().Context(), weekStart, &adminID)
+	if errors.Is(err, ledger.ErrBatchExists) || errors.Is(err, ledger.ErrBatchSuperseded) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to generate payout batch for week of %s: %s", weekStart.Format("2006-01-02"), err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "payout batch generated successfully",
+		"batch":   batch,
+	})
+}
+
+// GetPayoutBatches lists payout batches, newest week first (admin only)
+func (h *EarningsHandler) GetPayoutBatches(c echo.Context) error {
+	batches, err := h.repo.GetPayoutBatches(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list payout batches: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list payout batches"})
+	}
+	return c.JSON(http.StatusOK, batches)
+}
+
+// GetPayoutBatch returns a payout batch with its payouts (admin only)
+func (h *EarningsHandler) GetPayoutBatch(c echo.Context) error {
+	batch, err := h.loadBatch(c)
+	if batch == nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, batch)
+}
+
+// ExportPayoutBatch downloads a payout batch as CSV for the bank or payout provider (admin only)
+func (h *EarningsHandler) ExportPayoutBatch(c echo.Context) error {
+	batch, err := h.loadBatch(c)
+	if batch == nil {
+		return err
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write([]string{"payout_id", "driver_id", "driver_username", "driver_email", "amount", "currency", "status", "reference", "failure_reason", "paid_at"})
+	for _, payout := range batch.Payouts {
+		paidAt := ""
+		if payout.PaidAt != nil {
+			paidAt = payout.PaidAt.Format(time.RFC3339)
+		}
+		w.Write([]string{
+			strconv.FormatInt(payout.ID, 10),
+			strconv.FormatInt(payout.DriverID, 10),
+			payout.DriverUsername,
+			payout.DriverEmail,
+			fmt.Sprintf("%d.%02d", payout.Amount/100, payout.Amount%100), // Payout amounts are always positive
+			payout.Currency,
+			payout.Status,
+			payout.Reference,
+			payout.FailureReason,
+			paidAt,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to export payout batch %d: %s", batch.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to export payout batch"})
+	}
+
+	filename := fmt.Sprintf("payouts-%s.csv", batch.PeriodStart.Format("2006-01-02"))
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, "text/csv", []byte(b.String()))
+}
+
+// MarkPayoutPaid confirms a pending payout with the bank or provider reference (admin only)
+func (h *EarningsHandler) MarkPayoutPaid(c echo.Context) error {
+	var req models.PayoutPaidRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Reference = strings.TrimSpace(req.Reference); req.Reference == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "reference is required"})
+	}
+
+	payout, err := h.loadPayout(c)
+	if payout == nil {
+		return err
+	}
+	if err := h.ledger.MarkPayoutPaid(c.Request().Context(), payout, req.Reference); err != nil {
+		h.logger.Warn(fmt.Sprintf("Failed to mark payout %d as paid: %s", payout.ID, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "payout marked as paid",
+		"payout":  payout,
+	})
+}
+
+// MarkPayoutFailed records a failed payout; its amount is owed again and paid with the next batch (admin only)
+func (h *EarningsHandler) MarkPayoutFailed(c echo.Context) error {
+	var req models.PayoutFailedRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Reason = strings.TrimSpace(req.Reason); req.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "reason is required"})
+	}
+
+	payout, err := h.loadPayout(c)
+	if payout == nil {
+		return err
+	}
+	if err := h.ledger.MarkPayoutFailed(c.Request().Context(), payout, req.Reason); err != nil {
+		h.logger.Warn(fmt.Sprintf("Failed to mark payout %d as failed: %s", payout.ID, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "payout marked as failed",
+		"payout":  payout,
+	})
+}
+
+// loadBatch returns the batch named by the :id path parameter with its payouts, or writes an error response
+func (h *EarningsHandler) loadBatch(c echo.Context) (*models.PayoutBatch, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid batch ID"})
+	}
+
+	batch, err := h.repo.GetPayoutBatchByID(c.Request().Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "payout batch not found"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get payout batch %d: %s", id, err.Error()))
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get payout batch"})
+	}
+
+	batch.Payouts, err = h.repo.GetPayoutsByBatchID(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get payouts of batch %d: %s", id, err.Error()))
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get payout batch"})
+	}
+	return batch, nil
+}
+
+// loadPayout returns the payout named by the :id path parameter, or writes an error response
+func (h *EarningsHandler) loadPayout(c echo.Context) (*models.Payout, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid payout ID"})
+	}
+
+	payout, err := h.repo.GetPayoutByID(c.Request().Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "payout not found"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get payout %d: %s", id, err.Error()))
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get payout"})
+	}
+	return payout, nil
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/observer"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+// heartbeatInterval keeps idle streams open through proxies
+const heartbeatInterval = 25 * time.Second
+
+// EventHandler streams booking lifecycle events over Server-Sent Events and WebSocket
+type EventHandler struct {
+	hub    *observer.Hub
+	logger *logger.Logger
+}
+
+func NewEventHandler(hub *observer.Hub, logger *logger.Logger) *EventHandler {
+	return &EventHandler{
+		hub:    hub,
+		logger: logger,
+	}
+}
+
+// Stream sends booking events as Server-Sent Events. Riders receive their own bookings, drivers their
+// assignments and admins every booking. Reconnecting clients send Last-Event-ID to replay missed events.
+func (h *EventHandler) Stream(c echo.Context) error {
+	scope, lastID, err := h.subscription(c)
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	fmt.Fprint(res, "retry: 3000\n\n")
+	res.Flush()
+
+	send := func(event *models.BookingEvent) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	}
+	heartbeat := func() error {
+		if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	}
+
+	h.stream(c.Request().Context(), scope, lastID, send, heartbeat)
+	return nil
+}
+
+// WebSocket sends booking events as JSON messages over a WebSocket, with the same scopes as Stream.
+// Browsers cannot set headers on WebSocket connections, so reconnecting clients pass ?last_event_id.
+func (h *EventHandler) WebSocket(c echo.Context) error {
+	scope, lastID, err := h.subscription(c)
+	if err != nil {
+		return err
+	}
+
+	server := websocket.Server{
+		// Connections are authenticated by token, so non-browser clients without an Origin are accepted
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			ctx, cancel := context.WithCancel(c.Request().Context())
+			defer cancel()
+
+			// Clients send nothing; a failed read means they went away
+			go func() {
+				var msg string
+				for websocket.Message.Receive(ws, &msg) == nil {
+				}
+				cancel()
+			}()
+
+			send := func(event *models.BookingEvent) error {
+				return websocket.JSON.Send(ws, event)
+			}
+			heartbeat := func() error {
+				return websocket.JSON.Send(ws, map[string]string{"type": "ping"})
+			}
+			h.stream(ctx, scope, lastID, send, heartbeat)
+		},
+	}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// subscription reads the caller's scope and the ID of the last event they received, or writes an error response
+func (h *EventHandler) subscription(c echo.Context) (observer.Scope, int64, error) {
+	userID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return observer.Scope{}, 0, c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+	role, _ := c.Get("role").(string)
+	isAdmin, _ := c.Get("is_admin").(bool)
+	scope := observer.Scope{
+		UserID:     userID,
+		Driver:     role == models.RoleDriver,
+		Dispatcher: role == models.RoleAdmin || isAdmin,
+	}
+
+	var lastID int64
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || id < 0 {
+			return observer.Scope{}, 0, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid last event ID"})
+		}
+		lastID = id
+	}
+	return scope, lastID, nil
+}
+
+// stream replays the events after lastID, then sends live events until the client disconnects,
+// falls behind, or a write fails
+func (h *EventHandler) stream(ctx context.Context, scope observer.Scope, lastID int64,
+	send func(*models.BookingEvent) error, heartbeat func() error) {
+	// Subscribe before replaying so no event is missed in between
+	sub := h.hub.Subscribe(scope)
+	defer h.hub.Unsubscribe(sub)
+
+	for lastID > 0 {
+		events, err := h.hub.Replay(ctx, scope, lastID)
+		if err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to replay booking events for user %d: %s", scope.UserID, err.Error()))
+			return
+		}
+		for _, event := range events {
+			if err := send(event); err != nil {
+				return
+			}
+			lastID = event.ID
+		}
+		if len(events) < observer.ReplayBatch {
+			break
+		}
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if event.ID <= lastID {
+				continue
+			}
+			if err := send(event); err != nil {
+				return
+			}
+			lastID = event.ID
+		case <-ticker.C:
+			if err := heartbeat(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/scheduler"
+	"github.com/labstack/echo/v4"
+)
+
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+	logger    *logger.Logger
+}
+
+func NewJobHandler(scheduler *scheduler.Scheduler, logger *logger.Logger) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+// ListJobs lists scheduled jobs with their next run, last run and current lease (admin only)
+func (h *JobHandler) ListJobs(c echo.Context) error {
+	leases, err := h.scheduler.List(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list jobs: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list jobs"})
+	}
+
+	now := time.Now()
+	jobs := make([]map[string]interface{}, 0, len(leases))
+	for _, lease := range leases {
+		jobs = append(jobs, map[string]interface{}{
+			"job":     lease,
+			"running": lease.Running(now),
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
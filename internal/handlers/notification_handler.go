@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	notifier *notify.Dispatcher
+	logger   *logger.Logger
+}
+
+func NewNotificationHandler(notifier *notify.Dispatcher, logger *logger.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// GetPreferences returns the caller's notification preferences with the events, channels and
+// languages they can choose from
+func (h *NotificationHandler) GetPreferences(c echo.Context) error {
+	userID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	prefs, err := h.notifier.Preferences(c.Request().Context(), userID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get notification preferences of user %d: %s", userID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get notification preferences"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"preferences": prefs,
+		"events":      models.NotificationEvents,
+		"channels":    models.NotificationChannelNames,
+		"languages":   models.NotificationLanguages,
+	})
+}
+
+// UpdatePreferences replaces the caller's notification preferences
+func (h *NotificationHandler) UpdatePreferences(c echo.Context) error {
+	userID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	var req models.UpdateNotificationPreferencesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validation.ValidateNotificationPreferences(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	prefs := models.DefaultNotificationPreferences(userID)
+	if req.Channels != nil {
+		prefs.Channels = req.Channels
+	}
+	prefs.QuietHoursStart = req.QuietHoursStart
+	prefs.QuietHoursEnd = req.QuietHoursEnd
+	if req.Timezone != "" {
+		prefs.Timezone = req.Timezone
+	}
+	if req.Language != "" {
+		prefs.Language = req.Language
+	}
+
+	if err := h.notifier.UpdatePreferences(c.Request().Context(), prefs); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to update notification preferences of user %d: %s", userID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update notification preferences"})
+	}
+
+	h.logger.Info(fmt.Sprintf("User %d updated notification preferences", userID))
+	return c.JSON(http.StatusOK, prefs)
+}
@@ -0,0 +1,584 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/diagnosis/luxsuv-v4/internal/organizations"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+// OrganizationHandler manages corporate accounts: members, cost centers, booking approvals and invoices
+type OrganizationHandler struct {
+	repo     repository.OrganizationRepository
+	bookings repository.BookRideRepository
+	users    repository.UserRepository
+	orgs     *organizations.Service
+	notifier *notify.Dispatcher
+	logger   *logger.Logger
+}
+
+func NewOrganizationHandler(repo repository.OrganizationRepository, bookings repository.BookRideRepository, users repository.UserRepository, orgs *organizations.Service, notifier *notify.Dispatcher, logger *logger.Logger) *OrganizationHandler {
+	return &OrganizationHandler{
+		repo:     repo,
+		bookings: bookings,
+		users:    users,
+		orgs:     orgs,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// Create creates a corporate account (admin only)
+func (h *OrganizationHandler) Create(c echo.Context) error {
+	var org models.Organization
+	if err := c.Bind(&org); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	org.Active = true
+	if err := validation.ValidateOrganization(&org); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.repo.Create(c.Request().Context(), &org); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to create organization %q: %s", org.Name, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create organization"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Organization %d (%s) created", org.ID, org.Name))
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message":      "organization created successfully",
+		"organization": org,
+	})
+}
+
+// List returns all corporate accounts (admin only)
+func (h *OrganizationHandler) List(c echo.Context) error {
+	orgs, err := h.repo.List(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list organizations: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list organizations"})
+	}
+	return c.JSON(http.StatusOK, orgs)
+}
+
+// Update replaces a corporate account's details, approval rules and status (admin only)
+func (h *OrganizationHandler) Update(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	var req models.Organization
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	req.ID = org.ID
+	req.CreatedAt = org.CreatedAt
+	if err := validation.ValidateOrganization(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.repo.Update(c.Request().Context(), &req); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to update organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update organization"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Organization %d updated", org.ID))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "organization updated successfully",
+		"organization": req,
+	})
+}
+
+// GetMine returns the caller's organization, membership and cost centers
+func (h *OrganizationHandler) GetMine(c echo.Context) error {
+	userID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	member, err := h.repo.GetMemberByUserID(c.Request().Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "you are not a member of any organization"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get membership of user %d: %s", userID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get organization"})
+	}
+	org, err := h.repo.GetByID(c.Request().Context(), member.OrganizationID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get organization %d: %s", member.OrganizationID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get organization"})
+	}
+	costCenters, err := h.repo.GetCostCenters(c.Request().Context(), org.ID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get cost centers of organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get organization"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"organization": org,
+		"membership":   member,
+		"cost_centers": costCenters,
+	})
+}
+
+// Get returns a corporate account (org admins and admins)
+func (h *OrganizationHandler) Get(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, org)
+}
+
+// UpdateApprovalRules sets the fare above which bookings need an org admin's approval,
+// and whether bookings must name a cost center (org admins and admins)
+func (h *OrganizationHandler) UpdateApprovalRules(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	var req models.ApprovalRulesRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.ApprovalThreshold != nil && *req.ApprovalThreshold < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "approval threshold cannot be negative"})
+	}
+
+	org.ApprovalThreshold = req.ApprovalThreshold
+	org.RequireCostCenter = req.RequireCostCenter
+	if err := h.repo.Update(c.Request().Context(), org); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to update approval rules of organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update approval rules"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Approval rules of organization %d updated", org.ID))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "approval rules updated successfully",
+		"organization": org,
+	})
+}
+
+// GetMembers lists the members of an organization (org admins and admins)
+func (h *OrganizationHandler) GetMembers(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	members, err := h.repo.GetMembers(c.Request().Context(), org.ID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get members of organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get members"})
+	}
+	return c.JSON(http.StatusOK, members)
+}
+
+// AddMember adds a registered user to an organization by email (org admins and admins)
+func (h *OrganizationHandler) AddMember(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	var req models.AddOrganizationMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Role == "" {
+		req.Role = models.OrgRoleEmployee
+	}
+	if err := validation.ValidateOrgRole(req.Role); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	user, err := h.users.GetByEmail(c.Request().Context(), strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "no user registered with this email"})
+	}
+
+	member := &models.OrganizationMember{
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+		Username:       user.Username,
+		Email:          user.Email,
+		Role:           req.Role,
+	}
+	if member.DefaultCostCenterID, err = h.costCenterID(c, org.ID, req.CostCenter); err != nil {
+		return err
+	}
+
+	if err := h.repo.AddMember(c.Request().Context(), member); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "user already belongs to an organization"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to add user %d to organization %d: %s", user.ID, org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to add member"})
+	}
+
+	h.logger.Info(fmt.Sprintf("User %d added to organization %d as %s", user.ID, org.ID, member.Role))
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "member added successfully",
+		"member":  member,
+	})
+}
+
+// UpdateMember changes a member's role or default cost center (org admins and admins)
+func (h *OrganizationHandler) UpdateMember(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+	member, err := h.loadMember(c, org.ID)
+	if member == nil {
+		return err
+	}
+
+	var req models.UpdateOrganizationMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Role != "" {
+		if err := validation.ValidateOrgRole(req.Role); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		member.Role = req.Role
+	}
+	if req.CostCenter != nil {
+		if member.DefaultCostCenterID, err = h.costCenterID(c, org.ID, *req.CostCenter); err != nil {
+			return err
+		}
+	}
+
+	if err := h.repo.UpdateMember(c.Request().Context(), member); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to update member %d of organization %d: %s", member.UserID, org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update member"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Member %d of organization %d updated", member.UserID, org.ID))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "member updated successfully",
+		"member":  member,
+	})
+}
+
+// RemoveMember removes a user from an organization; their past bookings stay billed to it (org admins and admins)
+func (h *OrganizationHandler) RemoveMember(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user ID"})
+	}
+
+	if err := h.repo.RemoveMember(c.Request().Context(), org.ID, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "member not found"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to remove member %d from organization %d: %s", userID, org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to remove member"})
+	}
+
+	h.logger.Info(fmt.Sprintf("User %d removed from organization %d", userID, org.ID))
+	return c.JSON(http.StatusOK, map[string]string{"message": "member removed successfully"})
+}
+
+// GetCostCenters lists the cost centers of an organization (org admins and admins)
+func (h *OrganizationHandler) GetCostCenters(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	costCenters, err := h.repo.GetCostCenters(c.Request().Context(), org.ID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get cost centers of organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get cost centers"})
+	}
+	return c.JSON(http.StatusOK, costCenters)
+}
+
+// UpsertCostCenter creates or updates a cost center; deactivated ones can no longer be booked against
+// (org admins and admins)
+func (h *OrganizationHandler) UpsertCostCenter(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	costCenter := models.CostCenter{Active: true}
+	if err := c.Bind(&costCenter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	costCenter.OrganizationID = org.ID
+	costCenter.Code = c.Param("code")
+	if err := validation.ValidateCostCenter(&costCenter); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.repo.UpsertCostCenter(c.Request().Context(), &costCenter); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to save cost center %s of organization %d: %s", costCenter.Code, org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save cost center"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Cost center %s of organization %d saved", costCenter.Code, org.ID))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":     "cost center saved successfully",
+		"cost_center": costCenter,
+	})
+}
+
+// GetBookings lists the bookings billed to an organization, optionally filtered with
+// ?approval=pending|approved|rejected|not_required (org admins and admins)
+func (h *OrganizationHandler) GetBookings(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	approval := c.QueryParam("approval")
+	switch approval {
+	case "", models.OrgApprovalNotRequired, models.OrgApprovalPending, models.OrgApprovalApproved, models.OrgApprovalRejected:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "approval must be one of: not_required, pending, approved, rejected"})
+	}
+
+	bookings, err := h.repo.GetBookings(c.Request().Context(), org.ID, approval)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get bookings of organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get bookings"})
+	}
+	return c.JSON(http.StatusOK, bookings)
+}
+
+// ApproveBooking lets drivers accept a booking that was waiting for approval (org admins and admins)
+func (h *OrganizationHandler) ApproveBooking(c echo.Context) error {
+	org, deciderID, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+	bookingID, err := strconv.ParseInt(c.Param("bookingId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	if err := h.repo.DecideBooking(c.Request().Context(), org.ID, bookingID, models.OrgApprovalApproved, deciderID); err != nil {
+		h.logger.Warn(fmt.Sprintf("Failed to approve booking %d of organization %d: %s", bookingID, org.ID, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	h.logger.Info(fmt.Sprintf("Booking %d approved for organization %d by user %d", bookingID, org.ID, deciderID))
+	return c.JSON(http.StatusOK, map[string]string{"message": "booking approved successfully"})
+}
+
+// RejectBooking rejects and cancels a booking that was waiting for approval (org admins and admins)
+func (h *OrganizationHandler) RejectBooking(c echo.Context) error {
+	org, deciderID, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+	bookingID, err := strconv.ParseInt(c.Param("bookingId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	var req models.RejectOrgBookingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Reason = strings.TrimSpace(req.Reason); req.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "reason is required"})
+	}
+
+	if err := h.repo.DecideBooking(c.Request().Context(), org.ID, bookingID, models.OrgApprovalRejected, deciderID); err != nil {
+		h.logger.Warn(fmt.Sprintf("Failed to reject booking %d of organization %d: %s", bookingID, org.ID, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err := h.bookings.Cancel(c.Request().Context(), bookingID, "rejected by organization: "+req.Reason); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to cancel rejected booking %d: %s", bookingID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "booking rejected but failed to cancel it"})
+	}
+	if booking, err := h.bookings.GetByID(c.Request().Context(), bookingID); err == nil {
+		h.notifier.BookingCancelled(c.Request().Context(), booking)
+	}
+
+	h.logger.Info(fmt.Sprintf("Booking %d rejected for organization %d by user %d: %s", bookingID, org.ID, deciderID, req.Reason))
+	return c.JSON(http.StatusOK, map[string]string{"message": "booking rejected and cancelled"})
+}
+
+// GenerateInvoice issues an organization's invoice for a month that has ended, defaulting to the
+// previous month (admin only)
+func (h *OrganizationHandler) GenerateInvoice(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	var req models.GenerateInvoiceRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	month := organizations.MonthStart(time.Now()).AddDate(0, -1, 0)
+	if req.Month != "" {
+		if month, err = time.Parse("2006-01", req.Month); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "month must be in YYYY-MM format"})
+		}
+	}
+
+	invoice, err := h.orgs.Invoice(c.Request().Context(), org, month)
+	if errors.Is(err, organizations.ErrInvoiceExists) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		h.logger.Warn(fmt.Sprintf("Failed to invoice organization %d for %s: %s", org.ID, month.Format("2006-01"), err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "invoice issued successfully",
+		"invoice": invoice,
+	})
+}
+
+// GetInvoices lists the monthly invoices of an organization (org admins and admins)
+func (h *OrganizationHandler) GetInvoices(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+
+	invoices, err := h.repo.GetInvoices(c.Request().Context(), org.ID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get invoices of organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get invoices"})
+	}
+	return c.JSON(http.StatusOK, invoices)
+}
+
+// GetInvoice returns an invoice with one line per booking, as JSON or as CSV with ?format=csv
+// (org admins and admins)
+func (h *OrganizationHandler) GetInvoice(c echo.Context) error {
+	org, _, err := h.orgAdminAccess(c)
+	if org == nil {
+		return err
+	}
+	invoiceID, err := strconv.ParseInt(c.Param("invoiceId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid invoice ID"})
+	}
+
+	invoice, err := h.repo.GetInvoiceByID(c.Request().Context(), org.ID, invoiceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "invoice not found"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get invoice %d: %s", invoiceID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get invoice"})
+	}
+
+	if c.QueryParam("format") != "csv" {
+		return c.JSON(http.StatusOK, invoice)
+	}
+
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write([]string{"booking_id", "ride_date", "rider_name", "cost_center", "description", "amount", "currency"})
+	for _, line := range invoice.Lines {
+		w.Write([]string{
+			strconv.FormatInt(line.BookingID, 10),
+			line.RideDate,
+			line.RiderName,
+			line.CostCenterCode,
+			line.Description,
+			fmt.Sprintf("%d.%02d", line.Amount/100, line.Amount%100), // Billed amounts are never negative
+			invoice.Currency,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to export invoice %s: %s", invoice.Number, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to export invoice"})
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", invoice.Number+".csv"))
+	return c.Blob(http.StatusOK, "text/csv", []byte(b.String()))
+}
+
+// orgAdminAccess loads the organization named by the :id path parameter and checks that the caller
+// administers it or is a platform admin. It returns the caller's user ID, or writes an error response.
+func (h *OrganizationHandler) orgAdminAccess(c echo.Context) (*models.Organization, int64, error) {
+	return orgAdminAccess(c, h.repo, h.logger)
+}
+
+// orgAdminAccess is shared with the handlers of other organization resources
+func orgAdminAccess(c echo.Context, repo repository.OrganizationRepository, logger *logger.Logger) (*models.Organization, int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, 0, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid organization ID"})
+	}
+	userID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return nil, 0, c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+	}
+
+	if role, _ := c.Get("role").(string); role != models.RoleAdmin {
+		member, err := repo.GetMemberByUserID(c.Request().Context(), userID)
+		if err != nil || member.OrganizationID != id || !member.IsOrgAdmin() {
+			return nil, 0, c.JSON(http.StatusForbidden, map[string]string{"error": "organization admin access required"})
+		}
+	}
+
+	org, err := repo.GetByID(c.Request().Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, c.JSON(http.StatusNotFound, map[string]string{"error": "organization not found"})
+	}
+	if err != nil {
+		logger.Err(fmt.Sprintf("Failed to get organization %d: %s", id, err.Error()))
+		return nil, 0, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get organization"})
+	}
+	return org, userID, nil
+}
+
+// loadMember returns the member named by the :userId path parameter, or writes an error response
+func (h *OrganizationHandler) loadMember(c echo.Context, orgID int64) (*models.OrganizationMember, error) {
+	userID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user ID"})
+	}
+	member, err := h.repo.GetMemberByUserID(c.Request().Context(), userID)
+	if err != nil || member.OrganizationID != orgID {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "member not found"})
+	}
+	return member, nil
+}
+
+// costCenterID resolves a cost center code of an organization; an empty code means none.
+// It writes the error response if the code is unknown.
+func (h *OrganizationHandler) costCenterID(c echo.Context, orgID int64, code string) (*int64, error) {
+	if code = strings.TrimSpace(code); code == "" {
+		return nil, nil
+	}
+	costCenter, err := h.repo.GetCostCenterByCode(c.Request().Context(), orgID, code)
+	if err != nil || !costCenter.Active {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"error": "unknown cost center " + code})
+	}
+	return &costCenter.ID, nil
+}
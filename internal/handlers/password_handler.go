@@ -6,8 +6,8 @@ import (
 	"strings"
 
 	"github.com/diagnosis/luxsuv-v4/internal/auth"
-	"github.com/diagnosis/luxsuv-v4/internal/email"
 	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
 	"github.com/diagnosis/luxsuv-v4/internal/repository"
 	"github.com/diagnosis/luxsuv-v4/internal/validation"
 	"github.com/labstack/echo/v4"
@@ -15,18 +15,18 @@ import (
 )
 
 type PasswordHandler struct {
-	authService  *auth.Service
-	userRepo     repository.UserRepository
-	emailService *email.Service
-	logger       *logger.Logger
+	authService *auth.Service
+	userRepo    repository.UserRepository
+	notifier    *notify.Dispatcher
+	logger      *logger.Logger
 }
 
-func NewPasswordHandler(authService *auth.Service, userRepo repository.UserRepository, emailService *email.Service, logger *logger.Logger) *PasswordHandler {
+func NewPasswordHandler(authService *auth.Service, userRepo repository.UserRepository, notifier *notify.Dispatcher, logger *logger.Logger) *PasswordHandler {
 	return &PasswordHandler{
-		authService:  authService,
-		userRepo:     userRepo,
-		emailService: emailService,
-		logger:       logger,
+		authService: authService,
+		userRepo:    userRepo,
+		notifier:    notifier,
+		logger:      logger,
 	}
 }
 
@@ -168,9 +168,9 @@ func (h *PasswordHandler) ResetPasswordRequest(c echo.Context) error {
 	h.logger.Info(fmt.Sprintf("Password reset token generated successfully for user %s (ID: %d)", email, user.ID))
 
 	// Send email if email service is configured
-	if h.emailService != nil {
+	if h.notifier.Emails() {
 		h.logger.Info(fmt.Sprintf("Attempting to send password reset email to %s", email))
-		if err := h.emailService.SendPasswordResetEmail(email, resetToken); err != nil {
+		if err := h.notifier.PasswordReset(c.Request().Context(), email, resetToken); err != nil {
 			h.logger.Err(fmt.Sprintf("Failed to send password reset email to %s: %s", email, err.Error()))
 			// Don't fail the request if email fails, but log it
 			h.logger.Warn("Email service failed, falling back to token response")
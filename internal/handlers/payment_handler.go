@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/diagnosis/luxsuv-v4/internal/ledger"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/payments"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+type PaymentHandler struct {
+	paymentService *payments.Service
+	bookings       repository.BookRideRepository
+	ledger         *ledger.Service
+	logger         *logger.Logger
+}
+
+func NewPaymentHandler(paymentService *payments.Service, bookings repository.BookRideRepository, ledger *ledger.Service, logger *logger.Logger) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		bookings:       bookings,
+		ledger:         ledger,
+		logger:         logger,
+	}
+}
+
+// Webhook receives signed provider callbacks and applies them to stored payments
+func (h *PaymentHandler) Webhook(c echo.Context) error {
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, 64*1024))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	signature := c.Request().Header.Get("X-Payment-Signature")
+	if !h.paymentService.VerifySignature(payload, signature) {
+		h.logger.Warn("Rejected payment webhook with invalid signature")
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid signature"})
+	}
+
+	var event payments.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid event payload"})
+	}
+	// Handled event IDs are stored to skip redeliveries, so every event needs one
+	if event.ID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "event id is required"})
+	}
+
+	if err := h.paymentService.HandleEvent(c.Request().Context(), event); err != nil {
+		if errors.Is(err, payments.ErrUnknownPayment) {
+			h.logger.Warn(fmt.Sprintf("Payment webhook %s references unknown payment %s", event.ID, event.ProviderRef))
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "payment not found"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to apply payment webhook %s: %s", event.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to process event"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"message": "event processed"})
+}
+
+// GetBookingPayments lists the payment attempts of a booking (admin only)
+func (h *PaymentHandler) GetBookingPayments(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	list, err := h.paymentService.GetBookingPayments(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get payments for booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get payments"})
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// Refund refunds part or all of the captured payment of a booking (admin only)
+func (h *PaymentHandler) Refund(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	var req models.RefundRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if req.Reason = strings.TrimSpace(req.Reason); req.Reason == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "reason is required"})
+	}
+
+	adminID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid admin authentication"})
+	}
+
+	payment, refund, err := h.paymentService.RefundBooking(c.Request().Context(), id, req.Amount, req.Reason, &adminID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "no captured payment for this booking"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to refund booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	h.logger.Info(fmt.Sprintf("Admin %d refunded %s on booking %d: %s", adminID, req.Amount.Format(payment.Currency), id, req.Reason))
+	h.recordRefund(c.Request().Context(), id, refund)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "refund processed successfully",
+		"payment": payment,
+		"refund":  refund,
+	})
+}
+
+// recordRefund takes a refund back out of the driver's earnings; failures are logged for admin follow-up
+func (h *PaymentHandler) recordRefund(ctx context.Context, bookingID int64, refund *models.PaymentRefund) {
+	if h.ledger == nil {
+		return
+	}
+	booking, err := h.bookings.GetByID(ctx, bookingID)
+	if err == nil {
+		err = h.ledger.RecordRefund(ctx, booking, refund)
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to record refund %d in ledger: %s", refund.ID, err.Error()))
+	}
+}
+
+// GetBookingRefunds returns the refund audit trail of a booking (admin only)
+func (h *PaymentHandler) GetBookingRefunds(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	refunds, err := h.paymentService.GetBookingRefunds(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get refunds for booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get refunds"})
+	}
+	return c.JSON(http.StatusOK, refunds)
+}
@@ -0,0 +1,431 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/diagnosis/luxsuv-v4/internal/auth"
+	"github.com/diagnosis/luxsuv-v4/internal/ledger"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/diagnosis/luxsuv-v4/internal/payments"
+	"github.com/diagnosis/luxsuv-v4/internal/pricing"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+// PostRideHandler handles tips and driver extras added to completed bookings
+type PostRideHandler struct {
+	repo              repository.PostRideAdjustmentRepository
+	bookings          repository.BookRideRepository
+	payments          *payments.Service
+	pricing           *pricing.Calculator
+	notifier          *notify.Dispatcher
+	authService       *auth.Service
+	logger            *logger.Logger
+	approvalThreshold models.Money
+	ledger            *ledger.Service
+}
+
+func NewPostRideHandler(repo repository.PostRideAdjustmentRepository, bookings repository.BookRideRepository, payments *payments.Service, pricing *pricing.Calculator, notifier *notify.Dispatcher, authService *auth.Service, logger *logger.Logger, approvalThreshold models.Money, ledger *ledger.Service) *PostRideHandler {
+	return &PostRideHandler{
+		repo:              repo,
+		bookings:          bookings,
+		payments:          payments,
+		pricing:           pricing,
+		notifier:          notifier,
+		authService:       authService,
+		logger:            logger,
+		approvalThreshold: approvalThreshold,
+		ledger:            ledger,
+	}
+}
+
+// AddTip charges a tip on a completed booking (authenticated users or via secure token)
+func (h *PostRideHandler) AddTip(c echo.Context) error {
+	booking, riderID, err := h.riderBooking(c)
+	if booking == nil {
+		return err
+	}
+
+	var req models.TipRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validation.ValidateTip(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if booking.BookStatus != models.BookStatusCompleted {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "tips can only be added to completed bookings"})
+	}
+
+	existing, err := h.repo.GetByBookingID(c.Request().Context(), booking.ID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get adjustments of booking %d: %s", booking.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to add tip"})
+	}
+	for _, adj := range existing {
+		if adj.Kind == models.AdjustmentTip && adj.Status != models.AdjustmentStatusRejected && adj.Status != models.AdjustmentStatusFailed {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "booking has already been tipped"})
+		}
+	}
+
+	// The tip is inserted as charging, so a second tip sent at the same time is refused before
+	// either is charged
+	adj := &models.PostRideAdjustment{
+		BookingID:   booking.ID,
+		Kind:        models.AdjustmentTip,
+		Amount:      req.Amount,
+		Currency:    booking.Currency,
+		SubmittedBy: riderID,
+		Status:      models.AdjustmentStatusCharging,
+	}
+	err = h.repo.Create(c.Request().Context(), adj)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "booking has already been tipped"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to save tip for booking %d: %s", booking.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to add tip"})
+	}
+
+	if err := h.charge(c.Request().Context(), adj, riderID); err != nil {
+		return h.chargeErrorResponse(c, adj, err)
+	}
+
+	h.logger.Info(fmt.Sprintf("Tip of %s added to booking %d", adj.Amount.Format(adj.Currency), booking.ID))
+	h.sendReceipt(c.Request().Context(), adj)
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message":    "tip added successfully",
+		"adjustment": adj,
+	})
+}
+
+// AddExtra records tolls, parking or waiting time on a completed booking (driver only).
+// Extras are charged right away unless they bring the driver extras of the booking above
+// the approval threshold, in which case they wait for the rider or an admin.
+func (h *PostRideHandler) AddExtra(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	driverIDClaim := c.Get("user_id")
+	driverID, ok := middleware.ConvertToInt64(driverIDClaim)
+	if !ok {
+		h.logger.Warn(fmt.Sprintf("Invalid driver ID type: %T, value: %v", driverIDClaim, driverIDClaim))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	var req models.RideExtraRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validation.ValidateRideExtra(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	booking, err := h.bookings.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+	}
+	if booking.DriverID == nil || *booking.DriverID != driverID {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "booking is not assigned to you"})
+	}
+	if booking.BookStatus != models.BookStatusCompleted {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "extras can only be added to completed bookings"})
+	}
+
+	adj := &models.PostRideAdjustment{
+		BookingID:   booking.ID,
+		Kind:        req.Kind,
+		Amount:      req.Amount,
+		Currency:    booking.Currency,
+		Description: req.Description,
+		SubmittedBy: &driverID,
+	}
+	if req.Kind == models.AdjustmentWaitingTime {
+		minutes := req.Minutes
+		adj.Minutes = &minutes
+		adj.Amount = h.pricing.WaitingCharge(booking.RideType, minutes)
+	}
+
+	existing, err := h.repo.GetByBookingID(c.Request().Context(), booking.ID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get adjustments of booking %d: %s", booking.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to add extra"})
+	}
+	total := adj.Amount
+	for _, other := range existing {
+		if other.Kind != models.AdjustmentTip && other.Status != models.AdjustmentStatusRejected && other.Status != models.AdjustmentStatusFailed {
+			total = total.Add(other.Amount)
+		}
+	}
+	adj.RequiresApproval = total > h.approvalThreshold
+	// Extras charged right away are inserted as charging, so nothing else can claim them
+	adj.Status = models.AdjustmentStatusCharging
+	if adj.RequiresApproval {
+		adj.Status = models.AdjustmentStatusPending
+	}
+
+	if err := h.repo.Create(c.Request().Context(), adj); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to save %s extra for booking %d: %s", adj.Kind, booking.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to add extra"})
+	}
+
+	if adj.RequiresApproval {
+		h.logger.Info(fmt.Sprintf("Extra %d (%s, %s) on booking %d is waiting for approval", adj.ID, adj.Kind,
+			adj.Amount.Format(adj.Currency), booking.ID))
+		return c.JSON(http.StatusAccepted, map[string]interface{}{
+			"message":    "extra submitted for rider approval",
+			"adjustment": adj,
+		})
+	}
+
+	if err := h.charge(c.Request().Context(), adj, nil); err != nil {
+		return h.chargeErrorResponse(c, adj, err)
+	}
+
+	h.logger.Info(fmt.Sprintf("Extra %d (%s, %s) charged on booking %d", adj.ID, adj.Kind, adj.Amount.Format(adj.Currency), booking.ID))
+	h.sendReceipt(c.Request().Context(), adj)
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message":    "extra added successfully",
+		"adjustment": adj,
+	})
+}
+
+// GetAdjustments lists the tips and extras of a booking (authenticated users or via secure token)
+func (h *PostRideHandler) GetAdjustments(c echo.Context) error {
+	booking, _, err := h.riderBooking(c)
+	if booking == nil {
+		return err
+	}
+	return h.listAdjustments(c, booking.ID)
+}
+
+// Approve charges a pending driver extra (authenticated users or via secure token)
+func (h *PostRideHandler) Approve(c echo.Context) error {
+	booking, riderID, err := h.riderBooking(c)
+	if booking == nil {
+		return err
+	}
+	return h.decide(c, booking.ID, riderID, true)
+}
+
+// Reject declines a pending driver extra (authenticated users or via secure token)
+func (h *PostRideHandler) Reject(c echo.Context) error {
+	booking, riderID, err := h.riderBooking(c)
+	if booking == nil {
+		return err
+	}
+	return h.decide(c, booking.ID, riderID, false)
+}
+
+// AdminGetAdjustments lists the tips and extras of any booking (admin only)
+func (h *PostRideHandler) AdminGetAdjustments(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+	return h.listAdjustments(c, id)
+}
+
+// AdminApprove charges a pending driver extra on behalf of the rider (admin only)
+func (h *PostRideHandler) AdminApprove(c echo.Context) error {
+	return h.adminDecide(c, true)
+}
+
+// AdminReject declines a pending driver extra (admin only)
+func (h *PostRideHandler) AdminReject(c echo.Context) error {
+	return h.adminDecide(c, false)
+}
+
+// adminDecide approves or rejects an extra as the authenticated admin
+func (h *PostRideHandler) adminDecide(c echo.Context, approve bool) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+	adminID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid admin authentication"})
+	}
+	return h.decide(c, id, &adminID, approve)
+}
+
+// listAdjustments renders the adjustments of a booking
+func (h *PostRideHandler) listAdjustments(c echo.Context, bookingID int64) error {
+	adjustments, err := h.repo.GetByBookingID(c.Request().Context(), bookingID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get adjustments of booking %d: %s", bookingID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get adjustments"})
+	}
+	return c.JSON(http.StatusOK, adjustments)
+}
+
+// decide approves (and charges) or rejects a pending adjustment of a booking
+func (h *PostRideHandler) decide(c echo.Context, bookingID int64, decidedBy *int64, approve bool) error {
+	adjustmentID, err := strconv.ParseInt(c.Param("adjustmentId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid adjustment ID"})
+	}
+
+	adj, err := h.repo.GetByID(c.Request().Context(), adjustmentID)
+	if err != nil || adj.BookingID != bookingID {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "adjustment not found"})
+	}
+	if adj.Status != models.AdjustmentStatusPending {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "adjustment is not pending"})
+	}
+
+	if !approve {
+		if err := h.repo.Reject(c.Request().Context(), adj.ID, decidedBy); err != nil {
+			h.logger.Warn(fmt.Sprintf("Failed to reject adjustment %d: %s", adj.ID, err.Error()))
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		h.logger.Info(fmt.Sprintf("Adjustment %d on booking %d rejected", adj.ID, bookingID))
+		return c.JSON(http.StatusOK, map[string]string{"message": "adjustment rejected successfully"})
+	}
+
+	// Claim the adjustment before charging, so a rider and an admin approving it at the same
+	// time cannot both charge the card
+	err = h.repo.Claim(c.Request().Context(), adj.ID, decidedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "adjustment was already decided"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to claim adjustment %d: %s", adj.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to approve adjustment"})
+	}
+	adj.Status = models.AdjustmentStatusCharging
+
+	if err := h.charge(c.Request().Context(), adj, decidedBy); err != nil {
+		return h.chargeErrorResponse(c, adj, err)
+	}
+
+	h.logger.Info(fmt.Sprintf("Adjustment %d on booking %d approved and charged", adj.ID, bookingID))
+	h.sendReceipt(c.Request().Context(), adj)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":    "adjustment approved successfully",
+		"adjustment": adj,
+	})
+}
+
+// riderBooking resolves the booking of the request for its rider, either the authenticated
+// owner or a guest holding a secure token. A nil booking means a response was already written.
+func (h *PostRideHandler) riderBooking(c echo.Context) (*models.BookRide, *int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return nil, nil, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	if userIDClaim := c.Get("user_id"); userIDClaim != nil {
+		uid, ok := middleware.ConvertToInt64(userIDClaim)
+		if !ok {
+			return nil, nil, c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+		}
+		booking, err := h.bookings.GetByID(c.Request().Context(), id)
+		if err != nil {
+			return nil, nil, c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+		if booking.UserID == nil || *booking.UserID != uid {
+			return nil, nil, c.JSON(http.StatusForbidden, map[string]string{"error": "access denied"})
+		}
+		return booking, &uid, nil
+	}
+
+	token := c.QueryParam("token")
+	if token == "" {
+		return nil, nil, c.JSON(http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+	}
+	// Guest rider with secure token
+	bookingID, email, err := h.authService.ValidateBookingUpdateToken(token)
+	if err != nil {
+		return nil, nil, c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
+	}
+	if bookingID != id {
+		return nil, nil, c.JSON(http.StatusForbidden, map[string]string{"error": "token not valid for this booking"})
+	}
+	booking, err := h.bookings.GetByIDAndEmail(c.Request().Context(), id, email)
+	if err != nil {
+		return nil, nil, c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+	}
+	return booking, nil, nil
+}
+
+// charge collects a charging adjustment from the rider's card and adds it to the final fare.
+// Callers claim the adjustment first, so each is charged once. A failed charge marks the
+// adjustment as failed. Adjustments on organization bookings are only added to the final fare,
+// which is invoiced.
+func (h *PostRideHandler) charge(ctx context.Context, adj *models.PostRideAdjustment, decidedBy *int64) error {
+	booking, err := h.bookings.GetByID(ctx, adj.BookingID)
+	if err != nil {
+		return err
+	}
+
+	var paymentID *int64
+	if h.payments != nil && !booking.IsInvoiced() {
+		payment, err := h.payments.ChargeBooking(ctx, adj.BookingID, adj.Amount, adj.Currency)
+		if payment != nil {
+			paymentID = &payment.ID
+		}
+		if err != nil {
+			if markErr := h.repo.MarkFailed(ctx, adj.ID, decidedBy, paymentID, err.Error()); markErr != nil {
+				h.logger.Err(fmt.Sprintf("Failed to mark adjustment %d as failed: %s", adj.ID, markErr.Error()))
+			}
+			adj.Status = models.AdjustmentStatusFailed
+			adj.PaymentID = paymentID
+			adj.FailureReason = err.Error()
+			return err
+		}
+	}
+	if err := h.repo.Apply(ctx, adj, decidedBy, paymentID, pricing.AdjustmentItem(adj)); err != nil {
+		// The card was charged; the adjustment stays charging for an admin to reconcile
+		return fmt.Errorf("failed to apply charged adjustment: %w", err)
+	}
+	h.recordEarnings(ctx, booking, adj)
+	return nil
+}
+
+// recordEarnings credits a charged adjustment to the driver; failures are logged for admin follow-up
+func (h *PostRideHandler) recordEarnings(ctx context.Context, booking *models.BookRide, adj *models.PostRideAdjustment) {
+	if h.ledger == nil {
+		return
+	}
+	if err := h.ledger.RecordAdjustment(ctx, booking, adj); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to record adjustment %d in ledger: %s", adj.ID, err.Error()))
+	}
+}
+
+// chargeErrorResponse renders a failed adjustment charge
+func (h *PostRideHandler) chargeErrorResponse(c echo.Context, adj *models.PostRideAdjustment, err error) error {
+	if adj.Status == models.AdjustmentStatusFailed {
+		h.logger.Warn(fmt.Sprintf("Charge for adjustment %d on booking %d failed: %s", adj.ID, adj.BookingID, err.Error()))
+		if errors.Is(err, payments.ErrDeclined) {
+			return c.JSON(http.StatusPaymentRequired, map[string]string{"error": "payment declined"})
+		}
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": "failed to charge payment"})
+	}
+	h.logger.Err(fmt.Sprintf("Failed to apply adjustment %d to booking %d: %s", adj.ID, adj.BookingID, err.Error()))
+	return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to apply adjustment"})
+}
+
+// sendReceipt emails the receipt recipients a receipt for a charged tip or extra
+func (h *PostRideHandler) sendReceipt(ctx context.Context, adj *models.PostRideAdjustment) {
+	if !h.notifier.Emails() {
+		return
+	}
+	booking, err := h.bookings.GetByID(ctx, adj.BookingID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to reload booking %d for adjustment receipt: %s", adj.BookingID, err.Error()))
+		return
+	}
+	h.notifier.AdjustmentReceipt(ctx, booking, pricing.AdjustmentItem(adj))
+}
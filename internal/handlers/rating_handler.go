@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/diagnosis/luxsuv-v4/internal/auth"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type RatingHandler struct {
+	repo         repository.RatingRepository
+	bookings     repository.BookRideRepository
+	users        repository.UserRepository
+	authService  *auth.Service
+	logger       *logger.Logger
+	lowThreshold int
+}
+
+func NewRatingHandler(repo repository.RatingRepository, bookings repository.BookRideRepository, users repository.UserRepository, authService *auth.Service, logger *logger.Logger, lowThreshold int) *RatingHandler {
+	return &RatingHandler{
+		repo:         repo,
+		bookings:     bookings,
+		users:        users,
+		authService:  authService,
+		logger:       logger,
+		lowThreshold: lowThreshold,
+	}
+}
+
+// RateDriver lets the rider of a completed booking rate its driver (authenticated users or via secure token)
+func (h *RatingHandler) RateDriver(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	var raterID *int64
+	var booking *models.BookRide
+
+	if userIDClaim := c.Get("user_id"); userIDClaim != nil {
+		uid, ok := middleware.ConvertToInt64(userIDClaim)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+		}
+		booking, err = h.bookings.GetByID(c.Request().Context(), id)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+		if booking.UserID == nil || *booking.UserID != uid {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "access denied"})
+		}
+		raterID = &uid
+	} else if token := c.QueryParam("token"); token != "" {
+		// Guest rider with secure token
+		bookingID, email, err := h.authService.ValidateBookingUpdateToken(token)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
+		}
+		if bookingID != id {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "token not valid for this booking"})
+		}
+		booking, err = h.bookings.GetByIDAndEmail(c.Request().Context(), id, email)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+	} else {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+	}
+
+	return h.rate(c, booking, models.RatingRiderToDriver, raterID, booking.DriverID)
+}
+
+// RateRider lets the driver of a completed booking rate its rider (driver only)
+func (h *RatingHandler) RateRider(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	driverIDClaim := c.Get("user_id")
+	driverID, ok := middleware.ConvertToInt64(driverIDClaim)
+	if !ok {
+		h.logger.Warn(fmt.Sprintf("Invalid driver ID type: %T, value: %v", driverIDClaim, driverIDClaim))
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	booking, err := h.bookings.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+	}
+	if booking.DriverID == nil || *booking.DriverID != driverID {
+		return c.JSON(http.StatusForbidden, map[string]string{"error": "access denied"})
+	}
+
+	return h.rate(c, booking, models.RatingDriverToRider, &driverID, booking.UserID)
+}
+
+// rate stores the rating of one party of a completed booking, flagging low scores for review
+func (h *RatingHandler) rate(c echo.Context, booking *models.BookRide, direction string, raterID, rateeID *int64) error {
+	var req models.CreateRatingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validation.ValidateRating(&req, direction); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if booking.BookStatus != models.BookStatusCompleted {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "only completed bookings can be rated"})
+	}
+	if direction == models.RatingRiderToDriver && booking.DriverID == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "booking has no driver to rate"})
+	}
+
+	rating := &models.Rating{
+		BookingID: booking.ID,
+		Direction: direction,
+		RaterID:   raterID,
+		RateeID:   rateeID,
+		Score:     req.Score,
+		Tags:      req.Tags,
+		Comment:   req.Comment,
+		Flagged:   req.Score <= h.lowThreshold,
+	}
+	if err := h.repo.Create(c.Request().Context(), rating); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "booking has already been rated"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to save %s rating of booking %d: %s", direction, booking.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save rating"})
+	}
+
+	if rating.Flagged {
+		h.logger.Warn(fmt.Sprintf("Low %s rating %d (score %d) on booking %d flagged for review", direction, rating.ID, rating.Score, booking.ID))
+	} else {
+		h.logger.Info(fmt.Sprintf("Booking %d rated %s: %d", booking.ID, direction, rating.Score))
+	}
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "rating saved successfully",
+		"rating":  rating,
+	})
+}
+
+// GetDriverProfile returns a driver's public profile with their rating summary
+func (h *RatingHandler) GetDriverProfile(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid driver ID"})
+	}
+
+	user, err := h.users.GetByID(c.Request().Context(), id)
+	if err != nil || user.Role != models.RoleDriver {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "driver not found"})
+	}
+
+	summary, err := h.repo.GetSummary(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get rating summary of driver %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get driver rating"})
+	}
+
+	return c.JSON(http.StatusOK, &models.DriverProfile{
+		ID:       user.ID,
+		Username: user.Username,
+		Rating:   summary,
+	})
+}
+
+// GetUserRatings returns the rating summary and all ratings a user received (admin only)
+func (h *RatingHandler) GetUserRatings(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid user ID"})
+	}
+
+	summary, err := h.repo.GetSummary(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get rating summary of user %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get ratings"})
+	}
+	ratings, err := h.repo.GetByRateeID(c.Request().Context(), id)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get ratings of user %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get ratings"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"summary": summary,
+		"ratings": ratings,
+	})
+}
+
+// GetFlagged lists low ratings waiting for review (admin only)
+func (h *RatingHandler) GetFlagged(c echo.Context) error {
+	ratings, err := h.repo.GetFlagged(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get flagged ratings: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get flagged ratings"})
+	}
+	return c.JSON(http.StatusOK, ratings)
+}
+
+// Review marks a flagged rating as reviewed (admin only)
+func (h *RatingHandler) Review(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid rating ID"})
+	}
+
+	adminID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid admin authentication"})
+	}
+
+	var req models.ReviewRatingRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if len(req.Note) > 1000 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "note must be no more than 1000 characters"})
+	}
+
+	if err := h.repo.Review(c.Request().Context(), id, adminID, req.Note); err != nil {
+		h.logger.Warn(fmt.Sprintf("Failed to review rating %d: %s", id, err.Error()))
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	h.logger.Info(fmt.Sprintf("Rating %d reviewed by admin %d", id, adminID))
+	return c.JSON(http.StatusOK, map[string]string{"message": "rating reviewed successfully"})
+}
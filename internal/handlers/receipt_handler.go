@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/diagnosis/luxsuv-v4/internal/auth"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/receipts"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type ReceiptHandler struct {
+	receipts    *receipts.Service
+	entities    repository.LegalEntityRepository
+	bookings    repository.BookRideRepository
+	authService *auth.Service
+	logger      *logger.Logger
+}
+
+func NewReceiptHandler(receipts *receipts.Service, entities repository.LegalEntityRepository, bookings repository.BookRideRepository, authService *auth.Service, logger *logger.Logger) *ReceiptHandler {
+	return &ReceiptHandler{
+		receipts:    receipts,
+		entities:    entities,
+		bookings:    bookings,
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+// GetReceipt returns the receipt of a completed booking as HTML, or as PDF with ?format=pdf
+// (booking owner, admins, or guests via secure token)
+func (h *ReceiptHandler) GetReceipt(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	format := strings.ToLower(c.QueryParam("format"))
+	if format == "" {
+		format = "html"
+	}
+	if format != "html" && format != "pdf" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "format must be html or pdf"})
+	}
+
+	var booking *models.BookRide
+	if userIDClaim := c.Get("user_id"); userIDClaim != nil {
+		uid, ok := middleware.ConvertToInt64(userIDClaim)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+		}
+		booking, err = h.bookings.GetByID(c.Request().Context(), id)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+		role, _ := c.Get("role").(string)
+		if role != models.RoleAdmin && (booking.UserID == nil || *booking.UserID != uid) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "access denied"})
+		}
+	} else if token := c.QueryParam("token"); token != "" {
+		// Guest rider with secure token
+		bookingID, email, err := h.authService.ValidateBookingUpdateToken(token)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
+		}
+		if bookingID != id {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "token not valid for this booking"})
+		}
+		booking, err = h.bookings.GetByIDAndEmail(c.Request().Context(), id, email)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+	} else {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+	}
+
+	doc, err := h.receipts.ForBooking(c.Request().Context(), booking)
+	if errors.Is(err, receipts.ErrNotCompleted) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to build receipt for booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate receipt"})
+	}
+
+	if format == "pdf" {
+		pdf, err := receipts.RenderPDF(doc)
+		if err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to render PDF receipt %s: %s", doc.Number, err.Error()))
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate receipt"})
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", doc.Filename()))
+		return c.Blob(http.StatusOK, "application/pdf", pdf)
+	}
+
+	html, err := receipts.RenderHTML(doc)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to render HTML receipt %s: %s", doc.Number, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to generate receipt"})
+	}
+	return c.HTMLBlob(http.StatusOK, html)
+}
+
+// ListLegalEntities returns the companies that issue receipts (admin only)
+func (h *ReceiptHandler) ListLegalEntities(c echo.Context) error {
+	entities, err := h.entities.List(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list legal entities: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list legal entities"})
+	}
+	return c.JSON(http.StatusOK, entities)
+}
+
+// UpsertLegalEntity creates or updates a company that issues receipts (admin only).
+// Its receipt numbering continues where it left off.
+func (h *ReceiptHandler) UpsertLegalEntity(c echo.Context) error {
+	var entity models.LegalEntity
+	if err := c.Bind(&entity); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	entity.Code = c.Param("code")
+
+	if err := validation.ValidateLegalEntity(&entity); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.entities.Upsert(c.Request().Context(), &entity); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to save legal entity %s: %s", entity.Code, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save legal entity"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Legal entity %s saved", entity.Code))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "legal entity saved successfully",
+		"legal_entity": entity,
+	})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type ServiceZoneHandler struct {
+	repo   repository.ServiceZoneRepository
+	logger *logger.Logger
+}
+
+func NewServiceZoneHandler(repo repository.ServiceZoneRepository, logger *logger.Logger) *ServiceZoneHandler {
+	return &ServiceZoneHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// List returns all service zones (admin only)
+func (h *ServiceZoneHandler) List(c echo.Context) error {
+	list, err := h.repo.List(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list service zones: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list service zones"})
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// Get returns a single service zone (admin only)
+func (h *ServiceZoneHandler) Get(c echo.Context) error {
+	code := strings.ToLower(strings.TrimSpace(c.Param("code")))
+	zone, err := h.repo.GetByCode(c.Request().Context(), code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "service zone not found"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to get service zone %s: %s", code, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get service zone"})
+	}
+	return c.JSON(http.StatusOK, zone)
+}
+
+// Upsert creates or replaces a service zone (admin only)
+func (h *ServiceZoneHandler) Upsert(c echo.Context) error {
+	var zone models.ServiceZone
+	if err := c.Bind(&zone); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	zone.Code = strings.ToLower(strings.TrimSpace(c.Param("code")))
+
+	if err := validation.ValidateServiceZone(&zone); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if err := h.repo.Upsert(c.Request().Context(), &zone); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to save service zone %s: %s", zone.Code, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to save service zone"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Service zone %s saved", zone.Code))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "service zone saved successfully",
+		"zone":    zone,
+	})
+}
+
+// Delete removes a service zone (admin only)
+func (h *ServiceZoneHandler) Delete(c echo.Context) error {
+	code := strings.ToLower(strings.TrimSpace(c.Param("code")))
+	if code == models.DefaultZoneCode {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "the default service zone cannot be deleted"})
+	}
+
+	if err := h.repo.Delete(c.Request().Context(), code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "service zone not found"})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to delete service zone %s: %s", code, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete service zone"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Service zone %s deleted", code))
+	return c.JSON(http.StatusOK, map[string]string{"message": "service zone deleted successfully"})
+}
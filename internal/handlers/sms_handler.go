@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/labstack/echo/v4"
+)
+
+type SMSHandler struct {
+	sms    *notify.Service
+	logger *logger.Logger
+}
+
+func NewSMSHandler(sms *notify.Service, logger *logger.Logger) *SMSHandler {
+	return &SMSHandler{
+		sms:    sms,
+		logger: logger,
+	}
+}
+
+// Inbound receives signed replies forwarded by the SMS provider and applies STOP, START and HELP
+func (h *SMSHandler) Inbound(c echo.Context) error {
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, 16*1024))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	signature := c.Request().Header.Get("X-SMS-Signature")
+	if !h.sms.VerifySignature(payload, signature) {
+		h.logger.Warn("Rejected inbound SMS with invalid signature")
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid signature"})
+	}
+
+	var reply models.InboundSMS
+	if err := json.Unmarshal(payload, &reply); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid message payload"})
+	}
+
+	if err := h.sms.HandleReply(c.Request().Context(), &reply); err != nil {
+		if errors.Is(err, notify.ErrInvalidPhone) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		h.logger.Err(fmt.Sprintf("Failed to handle inbound SMS: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to process message"})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"message": "message processed"})
+}
+
+// ListOptOuts lists phone numbers that no longer receive text messages (admin only)
+func (h *SMSHandler) ListOptOuts(c echo.Context) error {
+	optOuts, err := h.sms.ListOptOuts(c.Request().Context())
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list SMS opt-outs: %s", err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list opt-outs"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"opt_outs": optOuts,
+		"count":    len(optOuts),
+	})
+}
+
+// OptOut stops text messages to a phone number, e.g. at a rider's request by phone (admin only)
+func (h *SMSHandler) OptOut(c echo.Context) error {
+	var req models.SMSOptOutRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	phone, err := h.sms.OptOut(c.Request().Context(), req.Phone, models.SMSOptOutSourceAdmin)
+	if errors.Is(err, notify.ErrInvalidPhone) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to opt out %s: %s", req.Phone, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to opt out phone number"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Admin opted out %s from SMS", phone))
+	return c.JSON(http.StatusOK, map[string]string{"message": "phone number opted out", "phone": phone})
+}
+
+// OptIn resumes text messages to a phone number (admin only)
+func (h *SMSHandler) OptIn(c echo.Context) error {
+	phone, err := h.sms.OptIn(c.Request().Context(), c.Param("phone"))
+	if errors.Is(err, notify.ErrInvalidPhone) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to opt in %s: %s", c.Param("phone"), err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to opt in phone number"})
+	}
+
+	h.logger.Info(fmt.Sprintf("Admin opted %s back in to SMS", phone))
+	return c.JSON(http.StatusOK, map[string]string{"message": "phone number opted in", "phone": phone})
+}
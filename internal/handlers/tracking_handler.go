@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/diagnosis/luxsuv-v4/internal/auth"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/tracking"
+	"github.com/labstack/echo/v4"
+)
+
+type TrackingHandler struct {
+	tracker     *tracking.Service
+	bookings    repository.BookRideRepository
+	authService *auth.Service
+	notifier    *notify.Dispatcher
+	logger      *logger.Logger
+}
+
+func NewTrackingHandler(tracker *tracking.Service, bookings repository.BookRideRepository, authService *auth.Service, notifier *notify.Dispatcher, logger *logger.Logger) *TrackingHandler {
+	return &TrackingHandler{
+		tracker:     tracker,
+		bookings:    bookings,
+		authService: authService,
+		notifier:    notifier,
+		logger:      logger,
+	}
+}
+
+// PostLocations records a batch of GPS pings for a ride assigned to the driver (driver only)
+func (h *TrackingHandler) PostLocations(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	driverID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	var req models.LocationPingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+
+	booking, err := h.bookings.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+	}
+
+	err = h.tracker.RecordPings(c.Request().Context(), driverID, booking, req.Pings)
+	switch {
+	case errors.Is(err, tracking.ErrNotSharing):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, tracking.ErrRateLimited):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	case errors.Is(err, tracking.ErrInvalidPing):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case err != nil:
+		h.logger.Err(fmt.Sprintf("Failed to record locations of booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to record locations"})
+	}
+	return c.JSON(http.StatusAccepted, map[string]interface{}{"message": "locations recorded", "count": len(req.Pings)})
+}
+
+// Arriving texts the rider that the driver is about to reach the pickup, with the current ETA (driver only)
+func (h *TrackingHandler) Arriving(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	driverID, ok := middleware.ConvertToInt64(c.Get("user_id"))
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid driver authentication"})
+	}
+
+	booking, err := h.bookings.GetByID(c.Request().Context(), id)
+	if err != nil || booking.DriverID == nil || *booking.DriverID != driverID {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+	}
+	if booking.BookStatus != models.BookStatusAccepted || booking.RideStatus != models.RideStatusAssigned {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "the ride has already started or ended"})
+	}
+
+	// The ETA is best effort; the rider is told the driver is arriving either way
+	var etaMinutes *int
+	if rideTracking, err := h.tracker.Track(c.Request().Context(), booking); err == nil {
+		etaMinutes = rideTracking.ETAMinutes
+	}
+	h.notifier.DriverArriving(c.Request().Context(), booking, etaMinutes)
+
+	h.logger.Info(fmt.Sprintf("Driver %d arriving for booking %d", driverID, id))
+	return c.JSON(http.StatusOK, map[string]string{"message": "rider notified"})
+}
+
+// GetTracking returns the driver's last known location and ETA for a ride
+// (booking owner, admins, or guests via secure token)
+func (h *TrackingHandler) GetTracking(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid booking ID"})
+	}
+
+	var booking *models.BookRide
+	if userIDClaim := c.Get("user_id"); userIDClaim != nil {
+		uid, ok := middleware.ConvertToInt64(userIDClaim)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid user authentication"})
+		}
+		booking, err = h.bookings.GetByID(c.Request().Context(), id)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+		role, _ := c.Get("role").(string)
+		if role != models.RoleAdmin && (booking.UserID == nil || *booking.UserID != uid) {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "access denied"})
+		}
+	} else if token := c.QueryParam("token"); token != "" {
+		// Guest rider with secure token
+		bookingID, email, err := h.authService.ValidateBookingUpdateToken(token)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid or expired token"})
+		}
+		if bookingID != id {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "token not valid for this booking"})
+		}
+		booking, err = h.bookings.GetByIDAndEmail(c.Request().Context(), id, email)
+		if err != nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "booking not found"})
+		}
+	} else {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "authentication required"})
+	}
+
+	rideTracking, err := h.tracker.Track(c.Request().Context(), booking)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get tracking of booking %d: %s", id, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get ride tracking"})
+	}
+	return c.JSON(http.StatusOK, rideTracking)
+}
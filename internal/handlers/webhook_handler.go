@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/diagnosis/luxsuv-v4/internal/webhooks"
+	"github.com/labstack/echo/v4"
+)
+
+// WebhookHandler lets organization admins register webhook endpoints and inspect their deliveries
+type WebhookHandler struct {
+	webhooks *webhooks.Service
+	orgs     repository.OrganizationRepository
+	logger   *logger.Logger
+}
+
+func NewWebhookHandler(webhooks *webhooks.Service, orgs repository.OrganizationRepository, logger *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhooks: webhooks,
+		orgs:     orgs,
+		logger:   logger,
+	}
+}
+
+// List returns an organization's webhook endpoints and the event types they can subscribe to
+// (org admins and admins)
+func (h *WebhookHandler) List(c echo.Context) error {
+	org, _, err := orgAdminAccess(c, h.orgs, h.logger)
+	if org == nil {
+		return err
+	}
+
+	endpoints, err := h.webhooks.ListEndpoints(c.Request().Context(), org.ID)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to list webhook endpoints of organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to list webhook endpoints"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"webhooks":    endpoints,
+		"event_types": models.BookingEventTypes,
+	})
+}
+
+// Create registers a webhook endpoint. Its signing secret is only returned here. (org admins and admins)
+func (h *WebhookHandler) Create(c echo.Context) error {
+	org, userID, err := orgAdminAccess(c, h.orgs, h.logger)
+	if org == nil {
+		return err
+	}
+
+	var req models.WebhookEndpointRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validation.ValidateWebhookEndpoint(&req, h.webhooks.AllowsPrivateTargets()); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		OrganizationID: org.ID,
+		URL:            req.URL,
+		Description:    req.Description,
+		EventTypes:     req.EventTypes,
+	}
+	if err := h.webhooks.CreateEndpoint(c.Request().Context(), endpoint); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to create webhook endpoint for organization %d: %s", org.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create webhook endpoint"})
+	}
+
+	h.logger.Info(fmt.Sprintf("User %d registered webhook endpoint %d for organization %d", userID, endpoint.ID, org.ID))
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "webhook endpoint created successfully; store the secret now, it will not be shown again",
+		"webhook": endpoint,
+		"secret":  endpoint.Secret,
+	})
+}
+
+// Update replaces a webhook endpoint's URL and subscriptions. Setting active to true re-enables an
+// endpoint disabled after repeated failures. (org admins and admins)
+func (h *WebhookHandler) Update(c echo.Context) error {
+	org, userID, err := orgAdminAccess(c, h.orgs, h.logger)
+	if org == nil {
+		return err
+	}
+	endpoint, err := h.loadEndpoint(c, org.ID)
+	if endpoint == nil {
+		return err
+	}
+
+	var req models.WebhookEndpointRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	if err := validation.ValidateWebhookEndpoint(&req, h.webhooks.AllowsPrivateTargets()); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	endpoint.URL = req.URL
+	endpoint.Description = req.Description
+	endpoint.EventTypes = req.EventTypes
+	if req.Active != nil {
+		endpoint.Active = *req.Active
+	}
+	if err := h.webhooks.UpdateEndpoint(c.Request().Context(), endpoint); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to update webhook endpoint %d: %s", endpoint.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to update webhook endpoint"})
+	}
+
+	h.logger.Info(fmt.Sprintf("User %d updated webhook endpoint %d of organization %d", userID, endpoint.ID, org.ID))
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "webhook endpoint updated successfully",
+		"webhook": endpoint,
+	})
+}
+
+// Delete removes a webhook endpoint and its delivery log (org admins and admins)
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	org, userID, err := orgAdminAccess(c, h.orgs, h.logger)
+	if org == nil {
+		return err
+	}
+	endpoint, err := h.loadEndpoint(c, org.ID)
+	if endpoint == nil {
+		return err
+	}
+
+	if err := h.webhooks.DeleteEndpoint(c.Request().Context(), org.ID, endpoint.ID); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to delete webhook endpoint %d: %s", endpoint.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to delete webhook endpoint"})
+	}
+
+	h.logger.Info(fmt.Sprintf("User %d deleted webhook endpoint %d of organization %d", userID, endpoint.ID, org.ID))
+	return c.JSON(http.StatusOK, map[string]string{"message": "webhook endpoint deleted successfully"})
+}
+
+// GetDeliveries returns a webhook endpoint's delivery log, newest first, optionally filtered with
+// ?status=pending|delivered|failed and limited with ?limit= (org admins and admins)
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	org, _, err := orgAdminAccess(c, h.orgs, h.logger)
+	if org == nil {
+		return err
+	}
+	endpoint, err := h.loadEndpoint(c, org.ID)
+	if endpoint == nil {
+		return err
+	}
+
+	status := c.QueryParam("status")
+	switch status {
+	case "", models.WebhookDeliveryPending, models.WebhookDeliveryDelivered, models.WebhookDeliveryFailed:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "status must be one of: pending, delivered, failed"})
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit < 1 || limit > 500 {
+		limit = 50 // Default limit
+	}
+
+	deliveries, err := h.webhooks.Deliveries(c.Request().Context(), endpoint.ID, status, limit)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get deliveries of webhook endpoint %d: %s", endpoint.ID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get webhook deliveries"})
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+// Replay queues a delivery again as a new delivery, e.g. after the partner fixed their endpoint
+// (org admins and admins)
+func (h *WebhookHandler) Replay(c echo.Context) error {
+	org, userID, err := orgAdminAccess(c, h.orgs, h.logger)
+	if org == nil {
+		return err
+	}
+	endpoint, err := h.loadEndpoint(c, org.ID)
+	if endpoint == nil {
+		return err
+	}
+	deliveryID, err := strconv.ParseInt(c.Param("deliveryId"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid delivery ID"})
+	}
+
+	replay, err := h.webhooks.Replay(c.Request().Context(), endpoint.ID, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "delivery not found"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to replay webhook delivery %d: %s", deliveryID, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to replay webhook delivery"})
+	}
+
+	h.logger.Info(fmt.Sprintf("User %d replayed webhook delivery %d as %d", userID, deliveryID, replay.ID))
+	message := "delivery queued for replay"
+	if !endpoint.Active {
+		message = "delivery queued for replay; it will be sent once the endpoint is re-enabled"
+	}
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"message":  message,
+		"delivery": replay,
+	})
+}
+
+// loadEndpoint returns the organization's endpoint named by the :webhookId path parameter, or writes an error response
+func (h *WebhookHandler) loadEndpoint(c echo.Context, orgID int64) (*models.WebhookEndpoint, error) {
+	id, err := strconv.ParseInt(c.Param("webhookId"), 10, 64)
+	if err != nil {
+		return nil, c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid webhook ID"})
+	}
+	endpoint, err := h.webhooks.GetEndpoint(c.Request().Context(), orgID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, c.JSON(http.StatusNotFound, map[string]string{"error": "webhook endpoint not found"})
+	}
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to get webhook endpoint %d: %s", id, err.Error()))
+		return nil, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get webhook endpoint"})
+	}
+	return endpoint, nil
+}
@@ -0,0 +1,185 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// maxKeyLength is the longest Idempotency-Key accepted
+	maxKeyLength = 255
+	// maxBodySize is the largest request body hashed for an idempotent request
+	maxBodySize = 1 << 20
+	// staleAfter is how long a first request may stay in progress before a retry takes its key over
+	staleAfter = 5 * time.Minute
+)
+
+// Service makes mutating requests safe to retry. The first response to a request sent with an
+// Idempotency-Key header is stored against the key, the caller and a hash of the request, and
+// replayed to identical retries until the key expires.
+type Service struct {
+	repo   repository.IdempotencyKeyRepository
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+func NewService(repo repository.IdempotencyKeyRepository, ttl time.Duration, logger *logger.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// principal names the caller a key belongs to. Guests acting on a booking are scoped by a hash of
+// its secure token; guests creating a booking share one namespace, so their keys must be random.
+func principal(c echo.Context) string {
+	if keyID, ok := middleware.ConvertToInt64(c.Get("api_key_id")); ok {
+		return fmt.Sprintf("api_key:%d", keyID)
+	}
+	if userID, ok := middleware.ConvertToInt64(c.Get("user_id")); ok {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	if token := c.QueryParam("token"); token != "" {
+		sum := sha256.Sum256([]byte(token))
+		return "guest:" + hex.EncodeToString(sum[:8])
+	}
+	return "guest"
+}
+
+// requestHash covers the method, URL and body of a request
+func requestHash(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + uri + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recorder copies a response as it is written
+type recorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Middleware handles the Idempotency-Key header. Add it to a route after its authentication
+// middleware, so keys belong to the authenticated caller. Requests without the header are not affected.
+//
+// A retry with the same key and request gets the stored response with the Idempotent-Replayed
+// header; the same key with a different request gets 422, and a retry while the first request is
+// still being handled gets 409. Responses with a 5xx status are not stored, so such requests can be retried.
+func (s *Service) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(models.IdempotencyKeyHeader)
+			if key == "" {
+				return next(c)
+			}
+			if len(key) > maxKeyLength {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("%s must be no more than %d characters long", models.IdempotencyKeyHeader, maxKeyLength)})
+			}
+
+			body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxBodySize+1))
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "failed to read request body"})
+			}
+			if len(body) > maxBodySize {
+				return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "request body too large"})
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			record := &models.IdempotencyKey{
+				Principal:   principal(c),
+				Key:         key,
+				RequestHash: requestHash(c.Request().Method, c.Request().URL.RequestURI(), body),
+				ExpiresAt:   time.Now().Add(s.ttl),
+			}
+			claimed, err := s.repo.Claim(ctx, record, staleAfter)
+			if err != nil {
+				s.logger.Err(fmt.Sprintf("Failed to claim idempotency key of %s: %s", record.Principal, err.Error()))
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to process idempotency key"})
+			}
+			if !claimed {
+				return s.replay(c, record)
+			}
+
+			rec := &recorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = rec
+			err = next(c)
+			c.Response().Writer = rec.ResponseWriter
+
+			// Use a context that outlives a client that disconnected mid-request
+			ctx = context.WithoutCancel(ctx)
+			status := c.Response().Status
+			if err != nil || !c.Response().Committed || status >= http.StatusInternalServerError {
+				if releaseErr := s.repo.Release(ctx, record.ID); releaseErr != nil {
+					s.logger.Err(fmt.Sprintf("Failed to release idempotency key of %s: %s", record.Principal, releaseErr.Error()))
+				}
+				return err
+			}
+
+			record.ResponseStatus = &status
+			record.ResponseContentType = c.Response().Header().Get(echo.HeaderContentType)
+			record.ResponseBody = rec.body.Bytes()
+			if err := s.repo.SaveResponse(ctx, record); err != nil {
+				s.logger.Err(fmt.Sprintf("Failed to store response for idempotency key of %s: %s", record.Principal, err.Error()))
+			}
+			return nil
+		}
+	}
+}
+
+// replay answers a request whose key is already held
+func (s *Service) replay(c echo.Context, request *models.IdempotencyKey) error {
+	stored, err := s.repo.Get(c.Request().Context(), request.Principal, request.Key)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Released by a failed first request in the meantime
+		return c.JSON(http.StatusConflict, map[string]string{"error": "a request with this idempotency key failed; retry it"})
+	}
+	if err != nil {
+		s.logger.Err(fmt.Sprintf("Failed to get idempotency key of %s: %s", request.Principal, err.Error()))
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to process idempotency key"})
+	}
+
+	if stored.RequestHash != request.RequestHash {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "idempotency key was already used for a different request"})
+	}
+	if stored.ResponseStatus == nil {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "a request with this idempotency key is still in progress"})
+	}
+
+	s.logger.Info(fmt.Sprintf("Replayed response to %s %s for %s", c.Request().Method, c.Request().URL.Path, request.Principal))
+	c.Response().Header().Set(models.IdempotentReplayedHeader, "true")
+	return c.Blob(*stored.ResponseStatus, stored.ResponseContentType, stored.ResponseBody)
+}
+
+// DeleteExpired removes idempotency keys past their expiry; run as a scheduled job
+func (s *Service) DeleteExpired(ctx context.Context) error {
+	deleted, err := s.repo.DeleteExpired(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	if deleted > 0 {
+		s.logger.Info(fmt.Sprintf("Deleted %d expired idempotency keys", deleted))
+	}
+	return nil
+}
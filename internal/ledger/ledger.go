@@ -0,0 +1,358 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/pricing"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// passThroughItems are reimbursed to the driver in full, without commission
+var passThroughItems = map[string]bool{
+	pricing.ItemTolls:   true,
+	pricing.ItemParking: true,
+	pricing.ItemTip:     true,
+}
+
+// Service records driver earnings in the double-entry ledger and pays them out in weekly batches
+type Service struct {
+	repo              repository.LedgerRepository
+	commissionPercent int64
+	logger            *logger.Logger
+}
+
+func NewService(repo repository.LedgerRepository, commissionPercent int, logger *logger.Logger) *Service {
+	return &Service{
+		repo:              repo,
+		commissionPercent: int64(commissionPercent),
+		logger:            logger,
+	}
+}
+
+// RecordCompletion posts the final fare of a completed booking: the driver's share and the
+// platform commission, with tolls reimbursed in full
+func (s *Service) RecordCompletion(ctx context.Context, booking *models.BookRide) error {
+	if booking.DriverID == nil {
+		return nil
+	}
+	items := booking.FinalLineItems
+	if len(items) == 0 {
+		items = booking.LineItems
+	}
+
+	p := s.newPosting(booking, fmt.Sprintf("booking:%d:completed", booking.ID), models.LedgerKindBookingCompleted,
+		fmt.Sprintf("Booking #%d completed", booking.ID))
+	var fare, extras models.Money
+	for _, item := range items {
+		if passThroughItems[item.Code] {
+			extras += item.Amount
+		} else {
+			fare += item.Amount
+		}
+	}
+	p.earn(models.LedgerCategoryFare, fare, true)
+	p.earn(models.LedgerCategoryExtra, extras, false)
+	return s.post(ctx, p)
+}
+
+// RecordAdjustment posts a charged tip or post-ride extra of a booking
+func (s *Service) RecordAdjustment(ctx context.Context, booking *models.BookRide, adj *models.PostRideAdjustment) error {
+	if booking.DriverID == nil {
+		return nil
+	}
+	p := s.newPosting(booking, fmt.Sprintf("adjustment:%d", adj.ID), models.LedgerKindPostRide,
+		fmt.Sprintf("%s on booking #%d", adj.Kind, booking.ID))
+	category := models.LedgerCategoryExtra
+	if adj.Kind == models.AdjustmentTip {
+		category = models.LedgerCategoryTip
+	}
+	p.earn(category, adj.Amount, !passThroughItems[pricing.AdjustmentItem(adj).Code])
+	return s.post(ctx, p)
+}
+
+// RecordFareAdjustment posts an admin change of a completed booking's final fare, split like the fare itself
+func (s *Service) RecordFareAdjustment(ctx context.Context, booking *models.BookRide, adj *models.FareAdjustment) error {
+	if booking.DriverID == nil || adj.Field != "final_amount" || booking.BookStatus != models.BookStatusCompleted {
+		return nil
+	}
+	p := s.newPosting(booking, fmt.Sprintf("fare_adjustment:%d", adj.ID), models.LedgerKindFareAdjustment,
+		fmt.Sprintf("Fare adjusted on booking #%d: %s", booking.ID, adj.Reason))
+	p.earn(models.LedgerCategoryAdjustment, adj.NewAmount-adj.PreviousAmount, true)
+	return s.post(ctx, p)
+}
+
+// RecordRefund posts an admin refund of a completed booking's fare, taken back from the driver's
+// share and the platform commission like a fare reduction
+func (s *Service) RecordRefund(ctx context.Context, booking *models.BookRide, refund *models.PaymentRefund) error {
+	if booking.DriverID == nil || booking.BookStatus != models.BookStatusCompleted {
+		return nil
+	}
+	p := s.newPosting(booking, fmt.Sprintf("refund:%d", refund.ID), models.LedgerKindRefund,
+		fmt.Sprintf("Refund on booking #%d: %s", booking.ID, refund.Reason))
+	p.earn(models.LedgerCategoryAdjustment, -refund.Amount, true)
+	return s.post(ctx, p)
+}
+
+// posting builds the ledger transaction of money collected from the rider of a booking
+type posting struct {
+	txn               *models.LedgerTransaction
+	booking           *models.BookRide
+	commissionPercent int64
+	collected         models.Money
+}
+
+func (s *Service) newPosting(booking *models.BookRide, reference, kind, description string) *posting {
+	return &posting{
+		txn: &models.LedgerTransaction{
+			Reference:   reference,
+			Kind:        kind,
+			BookingID:   &booking.ID,
+			Description: description,
+		},
+		booking:           booking,
+		commissionPercent: s.commissionPercent,
+	}
+}
+
+// earn credits an amount collected from the rider to the driver, less the platform commission if commissioned
+func (p *posting) earn(category string, amount models.Money, commissioned bool) {
+	if amount == 0 {
+		return
+	}
+	var commission models.Money
+	if commissioned {
+		commission = amount.Percent(p.commissionPercent)
+	}
+	p.collected += amount
+	p.txn.Entries = append(p.txn.Entries, models.LedgerEntry{
+		Account:  models.LedgerAccountDriverPayable,
+		DriverID: p.booking.DriverID,
+		Category: category,
+		Amount:   -(amount - commission),
+		Currency: p.booking.Currency,
+	})
+	if commission != 0 {
+		p.txn.Entries = append(p.txn.Entries, models.LedgerEntry{
+			Account:  models.LedgerAccountCommission,
+			DriverID: p.booking.DriverID,
+			Category: models.LedgerCategoryCommission,
+			Amount:   -commission,
+			Currency: p.booking.Currency,
+		})
+	}
+}
+
+// post records the rider side of a posting and the posting itself; a posting already recorded
+// under the same reference is left alone
+func (s *Service) post(ctx context.Context, p *posting) error {
+	if len(p.txn.Entries) == 0 {
+		return nil
+	}
+	p.txn.Entries = append(p.txn.Entries, models.LedgerEntry{
+		Account:  models.LedgerAccountRiderPayments,
+		Category: p.txn.Kind,
+		Amount:   p.collected,
+		Currency: p.booking.Currency,
+	})
+	return s.record(ctx, p.txn)
+}
+
+// record posts a balanced transaction, treating one already posted under the same reference as done
+func (s *Service) record(ctx context.Context, txn *models.LedgerTransaction) error {
+	if err := s.repo.Post(ctx, txn); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	s.logger.Info(fmt.Sprintf("Ledger transaction %s posted", txn.Reference))
+	return nil
+}
+
+// Earnings summarizes a driver's ledger entries in [from, to)
+func (s *Service) Earnings(ctx context.Context, driverID int64, from, to time.Time) (*models.EarningsSummary, []*models.LedgerEntry, error) {
+	entries, err := s.repo.GetDriverEntries(ctx, driverID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	balance, err := s.repo.GetDriverBalance(ctx, driverID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary := &models.EarningsSummary{
+		DriverID: driverID,
+		From:     from,
+		To:       to,
+		Currency: models.DefaultCurrency,
+		Balance:  balance,
+	}
+	bookings := make(map[int64]bool)
+	for _, entry := range entries {
+		summary.Currency = entry.Currency
+		if entry.Account == models.LedgerAccountCommission {
+			summary.Commission -= entry.Amount
+			continue
+		}
+		if entry.Account != models.LedgerAccountDriverPayable {
+			continue
+		}
+		// Credits to the payable account are earnings; payout debits reduce it
+		switch entry.Category {
+		case models.LedgerCategoryFare:
+			summary.FareShare -= entry.Amount
+			if entry.BookingID != nil {
+				bookings[*entry.BookingID] = true
+			}
+		case models.LedgerCategoryTip:
+			summary.Tips -= entry.Amount
+		case models.LedgerCategoryExtra:
+			summary.Extras -= entry.Amount
+		case models.LedgerCategoryAdjustment:
+			summary.Adjustments -= entry.Amount
+		case models.LedgerCategoryPayout:
+			summary.PaidOut += entry.Amount
+		}
+	}
+	summary.Bookings = len(bookings)
+	summary.Earned = summary.FareShare + summary.Tips + summary.Extras + summary.Adjustments
+	summary.Gross = summary.Earned + summary.Commission
+	return summary, entries, nil
+}
+
+// ErrBatchExists is returned when the payout batch of a week has already been generated
+var ErrBatchExists = errors.New("payout batch already generated for this week")
+
+// ErrBatchSuperseded is returned for a week before the latest batch, whose earnings that batch already paid
+var ErrBatchSuperseded = errors.New("a payout batch for a later week has already been generated")
+
+// WeekStart returns the Monday 00:00 UTC that starts the week containing t
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// GenerateDueBatch generates the previous week's payout batch unless it already exists; run as a scheduled job
+func (s *Service) GenerateDueBatch(ctx context.Context) error {
+	lastWeek := WeekStart(time.Now()).AddDate(0, 0, -7)
+	_, err := s.repo.GetPayoutBatchByPeriod(ctx, lastWeek)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check payout batch for week of %s: %w", lastWeek.Format("2006-01-02"), err)
+	}
+	if _, err := s.GeneratePayoutBatch(ctx, lastWeek, nil); err != nil && !errors.Is(err, ErrBatchExists) {
+		return fmt.Errorf("failed to generate payout batch for week of %s: %w", lastWeek.Format("2006-01-02"), err)
+	}
+	return nil
+}
+
+// GeneratePayoutBatch pays every driver the balance they were owed at the end of the week starting at
+// weekStart. Earnings from earlier weeks that were not paid out, including failed payouts, are included.
+func (s *Service) GeneratePayoutBatch(ctx context.Context, weekStart time.Time, createdBy *int64) (*models.PayoutBatch, error) {
+	periodEnd := weekStart.AddDate(0, 0, 7)
+	if periodEnd.After(time.Now()) {
+		return nil, errors.New("payout batches can only be generated for weeks that have ended")
+	}
+	latest, err := s.repo.GetLatestPayoutBatch(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to load latest payout batch: %w", err)
+	}
+	if err == nil && weekStart.Before(latest.PeriodStart) {
+		return nil, ErrBatchSuperseded
+	}
+
+	balances, err := s.repo.GetPayableBalances(ctx, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load driver balances: %w", err)
+	}
+
+	batch := &models.PayoutBatch{
+		PeriodStart: weekStart,
+		PeriodEnd:   periodEnd,
+		Currency:    models.DefaultCurrency,
+		CreatedBy:   createdBy,
+		Payouts:     []*models.Payout{},
+	}
+	var postings []*models.LedgerTransaction
+	for driverID, amount := range balances {
+		batch.Payouts = append(batch.Payouts, &models.Payout{
+			DriverID: driverID,
+			Amount:   amount,
+			Currency: batch.Currency,
+			Status:   models.PayoutStatusPending,
+		})
+		batch.TotalAmount += amount
+	}
+	sort.Slice(batch.Payouts, func(i, j int) bool { return batch.Payouts[i].DriverID < batch.Payouts[j].DriverID })
+	for _, payout := range batch.Payouts {
+		driverID := payout.DriverID
+		postings = append(postings, &models.LedgerTransaction{
+			Reference:   fmt.Sprintf("payout:%s:%d", weekStart.Format("2006-01-02"), driverID),
+			Kind:        models.LedgerKindPayout,
+			Description: fmt.Sprintf("Payout for week of %s", weekStart.Format("2006-01-02")),
+			Entries: []models.LedgerEntry{
+				{Account: models.LedgerAccountDriverPayable, DriverID: &driverID, Category: models.LedgerCategoryPayout, Amount: payout.Amount, Currency: payout.Currency},
+				{Account: models.LedgerAccountPayoutClearing, DriverID: &driverID, Category: models.LedgerCategoryPayout, Amount: -payout.Amount, Currency: payout.Currency},
+			},
+		})
+	}
+	batch.PayoutCount = len(batch.Payouts)
+
+	if err := s.repo.CreatePayoutBatch(ctx, batch, postings); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrBatchExists
+		}
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Payout batch %d generated for week of %s: %d payouts totalling %s",
+		batch.ID, weekStart.Format("2006-01-02"), batch.PayoutCount, batch.TotalAmount.Format(batch.Currency)))
+	return batch, nil
+}
+
+// MarkPayoutPaid confirms a pending payout, moving its amount from clearing to cash
+func (s *Service) MarkPayoutPaid(ctx context.Context, payout *models.Payout, reference string) error {
+	payout.Status = models.PayoutStatusPaid
+	payout.Reference = reference
+	return s.settle(ctx, payout, models.LedgerKindPayoutPaid, fmt.Sprintf("Payout #%d paid (%s)", payout.ID, reference), []models.LedgerEntry{
+		{Account: models.LedgerAccountPayoutClearing, DriverID: &payout.DriverID, Category: models.LedgerCategoryPayout, Amount: payout.Amount, Currency: payout.Currency},
+		{Account: models.LedgerAccountCash, Category: models.LedgerCategoryPayout, Amount: -payout.Amount, Currency: payout.Currency},
+	})
+}
+
+// MarkPayoutFailed records a failed payout and returns its amount to the driver's balance,
+// so it is paid with the next batch
+func (s *Service) MarkPayoutFailed(ctx context.Context, payout *models.Payout, reason string) error {
+	payout.Status = models.PayoutStatusFailed
+	payout.FailureReason = reason
+	return s.settle(ctx, payout, models.LedgerKindPayoutFailed, fmt.Sprintf("Payout #%d failed: %s", payout.ID, reason), []models.LedgerEntry{
+		{Account: models.LedgerAccountPayoutClearing, DriverID: &payout.DriverID, Category: models.LedgerCategoryPayout, Amount: payout.Amount, Currency: payout.Currency},
+		{Account: models.LedgerAccountDriverPayable, DriverID: &payout.DriverID, Category: models.LedgerCategoryPayout, Amount: -payout.Amount, Currency: payout.Currency},
+	})
+}
+
+func (s *Service) settle(ctx context.Context, payout *models.Payout, kind, description string, entries []models.LedgerEntry) error {
+	posting := &models.LedgerTransaction{
+		Reference:   fmt.Sprintf("payout:%d:%s", payout.ID, payout.Status),
+		Kind:        kind,
+		PayoutID:    &payout.ID,
+		Description: description,
+		Entries:     entries,
+	}
+	if err := s.repo.SettlePayout(ctx, payout, posting); err != nil {
+		return err
+	}
+	s.logger.Info(fmt.Sprintf("Payout %d to driver %d marked %s", payout.ID, payout.DriverID, payout.Status))
+	return nil
+}
@@ -1,23 +1,28 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/diagnosis/luxsuv-v4/internal/apikeys"
 	"github.com/diagnosis/luxsuv-v4/internal/auth"
 	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
 	"github.com/labstack/echo/v4"
 )
 
 type AuthMiddleware struct {
 	authService *auth.Service
+	apiKeys     *apikeys.Service
 	logger      *logger.Logger
 }
 
-func NewAuthMiddleware(authService *auth.Service, logger *logger.Logger) *AuthMiddleware {
+func NewAuthMiddleware(authService *auth.Service, apiKeys *apikeys.Service, logger *logger.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
 		authService: authService,
+		apiKeys:     apiKeys,
 		logger:      logger,
 	}
 }
@@ -38,7 +43,7 @@ func ConvertToInt64(value interface{}) (int64, bool) {
 	}
 }
 
-// RequireAuth middleware validates JWT tokens
+// RequireAuth middleware validates JWT tokens and partner API keys
 func (m *AuthMiddleware) RequireAuth() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -68,6 +73,14 @@ func (m *AuthMiddleware) RequireAuth() echo.MiddlewareFunc {
 				})
 			}
 
+			// API keys act as their user, limited to the routes their scopes open
+			if apikeys.IsKey(tokenString) {
+				if ok, err := m.authenticateAPIKey(c, tokenString); !ok {
+					return err
+				}
+				return next(c)
+			}
+
 			// Validate token
 			claims, err := m.authService.ValidateJWT(tokenString)
 			if err != nil {
@@ -201,6 +214,14 @@ func (m *AuthMiddleware) OptionalAuth() echo.MiddlewareFunc {
 				return next(c)
 			}
 
+			// A presented API key must be valid: partner requests are never handled as a guest's
+			if apikeys.IsKey(tokenString) {
+				if ok, err := m.authenticateAPIKey(c, tokenString); !ok {
+					return err
+				}
+				return next(c)
+			}
+
 			claims, err := m.authService.ValidateJWT(tokenString)
 			if err != nil {
 				return next(c) // Invalid token, proceed as guest
@@ -237,4 +258,57 @@ func (m *AuthMiddleware) OptionalAuth() echo.MiddlewareFunc {
 			return next(c)
 		}
 	}
-}
\ No newline at end of file
+}
+
+// authenticateAPIKey checks a partner API key against the route's scope and the key's rate limit and
+// sets the key's user in the context with rider permissions. It returns false after writing an error response.
+func (m *AuthMiddleware) authenticateAPIKey(c echo.Context, token string) (bool, error) {
+	principal, err := m.apiKeys.Authenticate(c.Request().Context(), token)
+	switch {
+	case errors.Is(err, apikeys.ErrInvalidKey), errors.Is(err, apikeys.ErrKeyExpired), errors.Is(err, apikeys.ErrKeyRevoked), errors.Is(err, apikeys.ErrNotMember):
+		m.logger.Warn(fmt.Sprintf("API key authentication failed: %s", err.Error()))
+		return false, c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+	case err != nil:
+		m.logger.Err(fmt.Sprintf("Failed to authenticate API key: %s", err.Error()))
+		return false, c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to authenticate API key"})
+	}
+
+	scope, ok := models.ScopeForRoute(c.Request().Method, c.Path())
+	if !ok {
+		m.logger.Warn(fmt.Sprintf("API key %s used on %s %s", principal.Prefix, c.Request().Method, c.Path()))
+		return false, c.JSON(http.StatusForbidden, map[string]string{"error": "API keys cannot access this endpoint"})
+	}
+	if !principal.HasScope(scope) {
+		m.logger.Warn(fmt.Sprintf("API key %s lacks the %s scope", principal.Prefix, scope))
+		return false, c.JSON(http.StatusForbidden, map[string]string{"error": fmt.Sprintf("API key lacks the %s scope", scope)})
+	}
+	if !m.apiKeys.Allow(&principal.APIKey) {
+		m.logger.Warn(fmt.Sprintf("API key %s exceeded its rate limit", principal.Prefix))
+		return false, c.JSON(http.StatusTooManyRequests, map[string]string{"error": "API key rate limit exceeded"})
+	}
+
+	c.Set("user_id", principal.UserID)
+	c.Set("role", models.RoleRider)
+	c.Set("is_admin", false)
+	c.Set("username", principal.Username)
+	c.Set("email", principal.Email)
+	c.Set("api_key_id", principal.ID)
+	c.Set("organization_id", principal.OrganizationID)
+
+	m.logger.Info(fmt.Sprintf("Authenticated API key %s of organization %d as user %d", principal.Prefix, principal.OrganizationID, principal.UserID))
+	return true, nil
+}
+
+// QueryToken copies an access_token query parameter into the Authorization header, for clients that
+// cannot set headers such as browser EventSource and WebSocket connections. Use it only on streaming routes,
+// since URLs end up in access logs.
+func (m *AuthMiddleware) QueryToken() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if token := c.QueryParam("access_token"); token != "" && c.Request().Header.Get("Authorization") == "" {
+				c.Request().Header.Set("Authorization", "Bearer "+token)
+			}
+			return next(c)
+		}
+	}
+}
@@ -35,11 +35,13 @@ func CORSConfig() middleware.CORSConfig {
 			"X-Requested-With",
 			"X-CSRF-Token",
 			"Cache-Control",
+			"Idempotency-Key",
 		},
 		ExposeHeaders: []string{
 			"Content-Length",
 			"Content-Type",
 			"X-Total-Count",
+			"Idempotent-Replayed",
 		},
 		AllowCredentials: true,
 		MaxAge:           86400, // 24 hours
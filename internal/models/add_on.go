@@ -0,0 +1,103 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AddOn is an extra from the admin-managed catalog that riders can add to a booking
+type AddOn struct {
+	ID          int64          `json:"id" db:"id"`
+	Code        string         `json:"code" db:"code"`
+	Name        string         `json:"name" db:"name"`
+	Description string         `json:"description,omitempty" db:"description"`
+	Price       Money          `json:"price" db:"price"`
+	MaxQuantity int            `json:"max_quantity" db:"max_quantity"`
+	RideTypes   pq.StringArray `json:"ride_types" db:"ride_types"` // Compatible ride types; empty for all
+	Active      bool           `json:"active" db:"active"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// AvailableFor reports whether the add-on can be booked with the given ride type
+func (a *AddOn) AvailableFor(rideType string) bool {
+	if len(a.RideTypes) == 0 {
+		return true
+	}
+	rideType = strings.ToLower(strings.TrimSpace(rideType))
+	for _, t := range a.RideTypes {
+		if t == rideType {
+			return true
+		}
+	}
+	return false
+}
+
+// BookingAddOn is an add-on selected on a booking, with the catalog name and price at booking time
+type BookingAddOn struct {
+	Code      string `json:"code"`
+	Name      string `json:"name,omitempty"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice Money  `json:"unit_price"`
+}
+
+// BookingAddOns is stored as a JSONB array on book_rides
+type BookingAddOns []BookingAddOn
+
+// Summary lists the add-ons with their quantities, e.g. "Child seat x2, Champagne"
+func (l BookingAddOns) Summary() string {
+	parts := make([]string, len(l))
+	for i, a := range l {
+		parts[i] = a.Name
+		if a.Quantity > 1 {
+			parts[i] = fmt.Sprintf("%s x%d", a.Name, a.Quantity)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Value implements driver.Valuer
+func (l BookingAddOns) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements sql.Scanner
+func (l *BookingAddOns) Scan(src interface{}) error {
+	if src == nil {
+		*l = nil
+		return nil
+	}
+	return scanJSON(src, l)
+}
+
+// DriverRide is a booking as shown to drivers, with the passenger to pick up and the requested
+// add-ons listed up front
+type DriverRide struct {
+	Passenger       Contact       `json:"passenger"`
+	AddOns          BookingAddOns `json:"add_ons"`
+	SpecialRequests string        `json:"special_requests,omitempty"`
+	Booking         *BookRide     `json:"booking"`
+}
+
+// NewDriverRide builds the driver view of a booking
+func NewDriverRide(br *BookRide) *DriverRide {
+	addOns := br.AddOns
+	if addOns == nil {
+		addOns = BookingAddOns{}
+	}
+	passenger := br.Passenger()
+	return &DriverRide{
+		Passenger:       Contact{Name: passenger.Name, Phone: passenger.Phone}, // Drivers call, they don't email
+		AddOns:          addOns,
+		SpecialRequests: addOns.Summary(),
+		Booking:         br,
+	}
+}
@@ -1,40 +1,163 @@
 package models
 
+import "time"
+
+// Layouts of a booking's date and time fields, and of the two combined
+const (
+	PickupDateLayout     = "2006-01-02"
+	PickupTimeLayout     = "15:04"
+	PickupDateTimeLayout = PickupDateLayout + " " + PickupTimeLayout
+)
+
 type BookRide struct {
-	ID                 int64  `json:"id" db:"id"`
-	UserID             *int64 `json:"user_id,omitempty" db:"user_id"`
-	DriverID           *int64 `json:"driver_id,omitempty" db:"driver_id"`
-	YourName           string `json:"your_name" db:"your_name"`
-	Email              string `json:"email" db:"email"`
-	PhoneNumber        string `json:"phone_number" db:"phone_number"`
-	RideType           string `json:"ride_type" db:"ride_type"`
-	PickupLocation     string `json:"pickup_location" db:"pickup_location"`
-	DropoffLocation    string `json:"dropoff_location" db:"dropoff_location"`
-	Date               string `json:"date" db:"date"`
-	Time               string `json:"time" db:"time"`
-	NumberOfPassengers int    `json:"number_of_passengers" db:"number_of_passengers"`
-	NumberOfLuggage    int    `json:"number_of_luggage" db:"number_of_luggage"`
-	AdditionalNotes    string `json:"additional_notes,omitempty" db:"additional_notes"`
-	BookStatus         string `json:"book_status" db:"book_status"`
-	RideStatus         string `json:"ride_status" db:"ride_status"`
-	CreatedAt          string `json:"created_at" db:"created_at"`
-	UpdatedAt          string `json:"updated_at" db:"updated_at"`
+	ID                 int64                 `json:"id" db:"id"`
+	UserID             *int64                `json:"user_id,omitempty" db:"user_id"`
+	DriverID           *int64                `json:"driver_id,omitempty" db:"driver_id"`
+	YourName           string                `json:"your_name" db:"your_name"`
+	Email              string                `json:"email" db:"email"`
+	PhoneNumber        string                `json:"phone_number" db:"phone_number"`
+	PassengerName      string                `json:"passenger_name,omitempty" db:"passenger_name"` // Set when booking for someone else
+	PassengerEmail     string                `json:"passenger_email,omitempty" db:"passenger_email"`
+	PassengerPhone     string                `json:"passenger_phone,omitempty" db:"passenger_phone"`
+	Notifications      *BookingNotifications `json:"notifications,omitempty" db:"notifications"`
+	RideType           string                `json:"ride_type" db:"ride_type"`
+	BookingMode        string                `json:"booking_mode" db:"booking_mode"`
+	DurationHours      *int                  `json:"duration_hours,omitempty" db:"duration_hours"`
+	ActualMinutes      *int                  `json:"actual_minutes,omitempty" db:"actual_minutes"`
+	PickupLocation     string                `json:"pickup_location" db:"pickup_location"`
+	DropoffLocation    string                `json:"dropoff_location" db:"dropoff_location"`
+	Pickup             *Location             `json:"pickup,omitempty" db:"pickup_place"`
+	Dropoff            *Location             `json:"dropoff,omitempty" db:"dropoff_place"`
+	Stops              []BookingStop         `json:"stops,omitempty" db:"-"`
+	Flight             *FlightDetails        `json:"flight,omitempty" db:"flight"`
+	AddOns             BookingAddOns         `json:"add_ons,omitempty" db:"add_ons"`
+	PickupZoneID       *int64                `json:"pickup_zone_id,omitempty" db:"pickup_zone_id"`
+	DropoffZoneID      *int64                `json:"dropoff_zone_id,omitempty" db:"dropoff_zone_id"`
+	RequiresApproval   bool                  `json:"requires_approval" db:"requires_approval"`
+	ApprovalReason     string                `json:"approval_reason,omitempty" db:"approval_reason"`
+	ApprovedBy         *int64                `json:"approved_by,omitempty" db:"approved_by"`
+	ApprovedAt         *time.Time            `json:"approved_at,omitempty" db:"approved_at"`
+	OrganizationID     *int64                `json:"organization_id,omitempty" db:"organization_id"` // Billed to the organization's monthly invoice
+	CostCenterID       *int64                `json:"cost_center_id,omitempty" db:"cost_center_id"`
+	CostCenter         string                `json:"cost_center,omitempty" db:"-"` // Cost center code, only used when booking
+	OrgApprovalStatus  string                `json:"org_approval_status,omitempty" db:"org_approval_status"`
+	OrgApprovedBy      *int64                `json:"org_approved_by,omitempty" db:"org_approved_by"`
+	OrgApprovedAt      *time.Time            `json:"org_approved_at,omitempty" db:"org_approved_at"`
+	Date               string                `json:"date" db:"date"`
+	Time               string                `json:"time" db:"time"`
+	NumberOfPassengers int                   `json:"number_of_passengers" db:"number_of_passengers"`
+	NumberOfLuggage    int                   `json:"number_of_luggage" db:"number_of_luggage"`
+	AdditionalNotes    string                `json:"additional_notes,omitempty" db:"additional_notes"`
+	BookStatus         string                `json:"book_status" db:"book_status"`
+	RideStatus         string                `json:"ride_status" db:"ride_status"`
+	QuotedAmount       Money                 `json:"quoted_amount" db:"quoted_amount"`
+	FinalAmount        *Money                `json:"final_amount,omitempty" db:"final_amount"`
+	Currency           string                `json:"currency" db:"currency"`
+	LineItems          LineItems             `json:"line_items" db:"line_items"`
+	FinalLineItems     LineItems             `json:"final_line_items,omitempty" db:"final_line_items"`
+	CancellationFee    *Money                `json:"cancellation_fee,omitempty" db:"cancellation_fee"`
+	PaymentIssue       string                `json:"payment_issue,omitempty" db:"payment_issue"` // Why the hold could not be settled, until an admin resolves it
+	ReturnBookingID    *int64                `json:"return_booking_id,omitempty" db:"return_booking_id"`
+	OutboundBookingID  *int64                `json:"outbound_booking_id,omitempty" db:"outbound_booking_id"`
+	SeriesID           *int64                `json:"series_id,omitempty" db:"series_id"`
+	OccurrenceDate     *string               `json:"occurrence_date,omitempty" db:"occurrence_date"`
+	ReturnTrip         *ReturnTripRequest    `json:"return_trip,omitempty" db:"-"`    // Only used to request a return leg
+	ReturnBooking      *BookRide             `json:"return_booking,omitempty" db:"-"` // Return leg created with the booking
+	PaymentMethod      string                `json:"payment_method,omitempty" db:"-"` // Provider token, only used to place the hold
+	CreatedAt          string                `json:"created_at" db:"created_at"`
+	UpdatedAt          string                `json:"updated_at" db:"updated_at"`
+}
+
+// IsHourly reports whether the booking is an hourly charter
+func (br *BookRide) IsHourly() bool {
+	return br.BookingMode == BookingModeHourly
+}
+
+// IsInvoiced reports whether the booking is billed to an organization instead of the rider's card
+func (br *BookRide) IsInvoiced() bool {
+	return br.OrganizationID != nil
+}
+
+// PickupAt returns the scheduled pickup time of the booking; its date and time are local to
+// the pickup zone, whose time zone is given as loc
+func (br *BookRide) PickupAt(loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(PickupDateTimeLayout, br.Date+" "+br.Time, loc)
+}
+
+// BookingStop is an intermediate stop between pickup and drop-off
+type BookingStop struct {
+	ID        int64     `json:"id,omitempty" db:"id"`
+	BookingID int64     `json:"booking_id,omitempty" db:"booking_id"`
+	Position  int       `json:"position" db:"position"`
+	Address   string    `json:"address" db:"address"`
+	Place     *Location `json:"place,omitempty" db:"place"`
+	CreatedAt time.Time `json:"created_at,omitempty" db:"created_at"`
+}
+
+// ReturnTripRequest asks for a linked return booking from the drop-off back to the pickup
+type ReturnTripRequest struct {
+	Date  string        `json:"date"`
+	Time  string        `json:"time"`
+	Stops []BookingStop `json:"stops,omitempty"`
 }
 
 // UpdateBookRideRequest represents the request payload for updating a booking
 type UpdateBookRideRequest struct {
-	YourName           string `json:"your_name,omitempty"`
-	PhoneNumber        string `json:"phone_number,omitempty"`
-	RideType           string `json:"ride_type,omitempty"`
-	PickupLocation     string `json:"pickup_location,omitempty"`
-	DropoffLocation    string `json:"dropoff_location,omitempty"`
-	Date               string `json:"date,omitempty"`
-	Time               string `json:"time,omitempty"`
-	NumberOfPassengers *int   `json:"number_of_passengers,omitempty"`
-	NumberOfLuggage    *int   `json:"number_of_luggage,omitempty"`
-	AdditionalNotes    string `json:"additional_notes,omitempty"`
+	YourName           string                `json:"your_name,omitempty"`
+	PhoneNumber        string                `json:"phone_number,omitempty"`
+	PassengerName      *string               `json:"passenger_name,omitempty"` // An empty name makes the booker the passenger again
+	PassengerEmail     *string               `json:"passenger_email,omitempty"`
+	PassengerPhone     *string               `json:"passenger_phone,omitempty"`
+	Notifications      *BookingNotifications `json:"notifications,omitempty"`
+	RideType           string                `json:"ride_type,omitempty"`
+	PickupLocation     string                `json:"pickup_location,omitempty"`
+	DropoffLocation    string                `json:"dropoff_location,omitempty"`
+	Pickup             *Location             `json:"pickup,omitempty"`
+	Dropoff            *Location             `json:"dropoff,omitempty"`
+	Stops              *[]BookingStop        `json:"stops,omitempty"` // Replaces all stops; an empty list removes them
+	DurationHours      *int                  `json:"duration_hours,omitempty"`
+	Flight             *FlightDetails        `json:"flight,omitempty"`
+	AddOns             *BookingAddOns        `json:"add_ons,omitempty"` // Replaces all add-ons; an empty list removes them
+	Date               string                `json:"date,omitempty"`
+	Time               string                `json:"time,omitempty"`
+	NumberOfPassengers *int                  `json:"number_of_passengers,omitempty"`
+	NumberOfLuggage    *int                  `json:"number_of_luggage,omitempty"`
+	AdditionalNotes    string                `json:"additional_notes,omitempty"`
+}
+
+// CompleteRideRequest represents the extras a driver records when completing a ride
+type CompleteRideRequest struct {
+	ActualMinutes  int   `json:"actual_minutes,omitempty"` // Time on duty for hourly charters
+	WaitingMinutes int   `json:"waiting_minutes"`
+	ExtraStops     int   `json:"extra_stops"`
+	Tolls          Money `json:"tolls"`
+}
+
+// AdjustFareRequest represents an admin override of a booking fare
+type AdjustFareRequest struct {
+	Amount Money  `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+// FareAdjustment is an audit record of an admin fare change
+type FareAdjustment struct {
+	ID             int64     `json:"id" db:"id"`
+	BookingID      int64     `json:"booking_id" db:"booking_id"`
+	AdminID        *int64    `json:"admin_id,omitempty" db:"admin_id"`
+	Field          string    `json:"field" db:"field"`
+	PreviousAmount Money     `json:"previous_amount" db:"previous_amount"`
+	NewAmount      Money     `json:"new_amount" db:"new_amount"`
+	Currency       string    `json:"currency" db:"currency"`
+	Reason         string    `json:"reason" db:"reason"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 }
 
+// Booking modes
+const (
+	BookingModePointToPoint = "point_to_point"
+	BookingModeHourly       = "hourly"
+)
+
 // BookRide status constants
 const (
 	BookStatusPending   = "Pending"
@@ -42,9 +165,9 @@ const (
 	BookStatusCancelled = "Cancelled"
 	BookStatusCompleted = "Completed"
 
-	RideStatusPending   = "Pending"
-	RideStatusAssigned  = "Assigned"
+	RideStatusPending    = "Pending"
+	RideStatusAssigned   = "Assigned"
 	RideStatusInProgress = "In Progress"
-	RideStatusCompleted = "Completed"
-	RideStatusCancelled = "Cancelled"
-)
\ No newline at end of file
+	RideStatusCompleted  = "Completed"
+	RideStatusCancelled  = "Cancelled"
+)
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BookingEventChannel is the Postgres NOTIFY channel that announces new booking events to every server instance
+const BookingEventChannel = "booking_events"
+
+// Booking event types
+const (
+	BookingEventCreated   = "booking.created"
+	BookingEventUpdated   = "booking.updated"
+	BookingEventApproved  = "booking.approved"
+	BookingEventAccepted  = "booking.accepted"
+	BookingEventStarted   = "booking.started"
+	BookingEventCompleted = "booking.completed"
+	BookingEventCancelled = "booking.cancelled"
+)
+
+// BookingEventTypes lists every booking event type
+var BookingEventTypes = []string{
+	BookingEventCreated,
+	BookingEventUpdated,
+	BookingEventApproved,
+	BookingEventAccepted,
+	BookingEventStarted,
+	BookingEventCompleted,
+	BookingEventCancelled,
+}
+
+// BookingEvent is a change to a booking, with a snapshot of the booking after the change
+type BookingEvent struct {
+	ID         int64           `json:"id" db:"id"`
+	BookingID  int64           `json:"booking_id" db:"booking_id"`
+	Type       string          `json:"type" db:"type"`
+	UserID     *int64          `json:"-" db:"user_id"`
+	DriverID   *int64          `json:"-" db:"driver_id"`
+	BookStatus string          `json:"book_status" db:"book_status"`
+	RideStatus string          `json:"ride_status" db:"ride_status"`
+	Booking    json.RawMessage `json:"booking" db:"data"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,142 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Booking series statuses
+const (
+	SeriesStatusActive    = "active"
+	SeriesStatusCancelled = "cancelled"
+	SeriesStatusCompleted = "completed"
+)
+
+// BookingSeries is a recurring booking; its occurrences are generated ahead as ordinary bookings
+type BookingSeries struct {
+	ID             int64          `json:"id" db:"id"`
+	UserID         int64          `json:"user_id" db:"user_id"`
+	Recurrence     string         `json:"recurrence" db:"recurrence"`
+	StartDate      string         `json:"start_date" db:"start_date"`
+	Time           string         `json:"time" db:"time"`
+	Template       SeriesTemplate `json:"template" db:"template"`
+	PaymentMethod  string         `json:"-" db:"payment_method"`
+	Status         string         `json:"status" db:"status"`
+	GeneratedUntil *string        `json:"generated_until,omitempty" db:"generated_until"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+	Bookings       []*BookRide    `json:"bookings,omitempty" db:"-"`
+}
+
+// SeriesTemplate holds the booking fields copied to every occurrence of a series
+type SeriesTemplate struct {
+	YourName           string                `json:"your_name"`
+	Email              string                `json:"email"`
+	PhoneNumber        string                `json:"phone_number"`
+	PassengerName      string                `json:"passenger_name,omitempty"`
+	PassengerEmail     string                `json:"passenger_email,omitempty"`
+	PassengerPhone     string                `json:"passenger_phone,omitempty"`
+	Notifications      *BookingNotifications `json:"notifications,omitempty"`
+	RideType           string                `json:"ride_type"`
+	BookingMode        string                `json:"booking_mode"`
+	DurationHours      *int                  `json:"duration_hours,omitempty"`
+	PickupLocation     string                `json:"pickup_location"`
+	DropoffLocation    string                `json:"dropoff_location"`
+	Pickup             *Location             `json:"pickup,omitempty"`
+	Dropoff            *Location             `json:"dropoff,omitempty"`
+	Stops              []BookingStop         `json:"stops,omitempty"`
+	AddOns             BookingAddOns         `json:"add_ons,omitempty"`
+	NumberOfPassengers int                   `json:"number_of_passengers"`
+	NumberOfLuggage    int                   `json:"number_of_luggage"`
+	AdditionalNotes    string                `json:"additional_notes,omitempty"`
+}
+
+// NewSeriesTemplate copies the recurring fields of a booking
+func NewSeriesTemplate(br *BookRide) SeriesTemplate {
+	return SeriesTemplate{
+		YourName:           br.YourName,
+		Email:              br.Email,
+		PhoneNumber:        br.PhoneNumber,
+		PassengerName:      br.PassengerName,
+		PassengerEmail:     br.PassengerEmail,
+		PassengerPhone:     br.PassengerPhone,
+		Notifications:      br.Notifications,
+		RideType:           br.RideType,
+		BookingMode:        br.BookingMode,
+		DurationHours:      br.DurationHours,
+		PickupLocation:     br.PickupLocation,
+		DropoffLocation:    br.DropoffLocation,
+		Pickup:             br.Pickup,
+		Dropoff:            br.Dropoff,
+		Stops:              br.Stops,
+		AddOns:             br.AddOns,
+		NumberOfPassengers: br.NumberOfPassengers,
+		NumberOfLuggage:    br.NumberOfLuggage,
+		AdditionalNotes:    br.AdditionalNotes,
+	}
+}
+
+// Occurrence builds the booking of the series for the given date
+func (s *BookingSeries) Occurrence(date string) *BookRide {
+	t := s.Template
+	stops := make([]BookingStop, len(t.Stops))
+	for i, stop := range t.Stops {
+		stops[i] = BookingStop{Position: stop.Position, Address: stop.Address, Place: stop.Place}
+	}
+	occurrenceDate := date
+	return &BookRide{
+		UserID:             &s.UserID,
+		YourName:           t.YourName,
+		Email:              t.Email,
+		PhoneNumber:        t.PhoneNumber,
+		PassengerName:      t.PassengerName,
+		PassengerEmail:     t.PassengerEmail,
+		PassengerPhone:     t.PassengerPhone,
+		Notifications:      t.Notifications,
+		RideType:           t.RideType,
+		BookingMode:        t.BookingMode,
+		DurationHours:      t.DurationHours,
+		PickupLocation:     t.PickupLocation,
+		DropoffLocation:    t.DropoffLocation,
+		Pickup:             t.Pickup,
+		Dropoff:            t.Dropoff,
+		Stops:              stops,
+		AddOns:             t.AddOns,
+		Date:               date,
+		Time:               s.Time,
+		NumberOfPassengers: t.NumberOfPassengers,
+		NumberOfLuggage:    t.NumberOfLuggage,
+		AdditionalNotes:    t.AdditionalNotes,
+		SeriesID:           &s.ID,
+		OccurrenceDate:     &occurrenceDate,
+		PaymentMethod:      s.PaymentMethod,
+	}
+}
+
+// Value implements driver.Valuer
+func (t SeriesTemplate) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+// Scan implements sql.Scanner
+func (t *SeriesTemplate) Scan(src interface{}) error {
+	return scanJSON(src, t)
+}
+
+// CreateBookingSeriesRequest is a booking plus the recurrence rule that repeats it.
+// The booking date is the first date the rule may fire.
+type CreateBookingSeriesRequest struct {
+	BookRide
+	Recurrence string `json:"recurrence"`
+}
+
+// UpdateBookingSeriesRequest changes every upcoming open occurrence of a series
+type UpdateBookingSeriesRequest struct {
+	YourName           string  `json:"your_name,omitempty"`
+	PhoneNumber        string  `json:"phone_number,omitempty"`
+	Time               string  `json:"time,omitempty"`
+	NumberOfPassengers *int    `json:"number_of_passengers,omitempty"`
+	NumberOfLuggage    *int    `json:"number_of_luggage,omitempty"`
+	AdditionalNotes    *string `json:"additional_notes,omitempty"`
+}
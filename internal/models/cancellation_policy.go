@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// DefaultPolicyRideType is the ride type key of the fallback cancellation policy
+const DefaultPolicyRideType = "default"
+
+// CancellationPolicy defines tiered cancellation fees for a ride type.
+// Cancelling at least FreeUntilHours before pickup is free, at least LateUntilHours
+// before pickup costs LateFeePercent of the fare, and anything later (including
+// no-shows) costs NoShowFeePercent of the fare.
+type CancellationPolicy struct {
+	RideType         string    `json:"ride_type" db:"ride_type"`
+	FreeUntilHours   int       `json:"free_until_hours" db:"free_until_hours"`
+	LateUntilHours   int       `json:"late_until_hours" db:"late_until_hours"`
+	LateFeePercent   int       `json:"late_fee_percent" db:"late_fee_percent"`
+	NoShowFeePercent int       `json:"no_show_fee_percent" db:"no_show_fee_percent"`
+	UpdatedBy        *int64    `json:"updated_by,omitempty" db:"updated_by"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Cancellation fee tiers
+const (
+	CancellationTierFree   = "free"
+	CancellationTierLate   = "late"
+	CancellationTierNoShow = "no_show"
+)
+
+// CancellationQuote is the fee that applies if a booking is cancelled now
+type CancellationQuote struct {
+	BookingID         int64   `json:"booking_id"`
+	PolicyRideType    string  `json:"policy_ride_type"`
+	Tier              string  `json:"tier"`
+	HoursBeforePickup float64 `json:"hours_before_pickup"`
+	FeePercent        int     `json:"fee_percent"`
+	Fee               Money   `json:"fee"`
+	Currency          string  `json:"currency"`
+}
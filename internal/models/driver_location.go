@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// DriverLocation is a GPS ping posted by a driver during a ride
+type DriverLocation struct {
+	ID         int64     `json:"-" db:"id"`
+	DriverID   int64     `json:"-" db:"driver_id"`
+	BookingID  int64     `json:"-" db:"booking_id"`
+	Lat        float64   `json:"lat" db:"lat"`
+	Lng        float64   `json:"lng" db:"lng"`
+	SpeedKmh   *float64  `json:"speed_kmh,omitempty" db:"speed_kmh"`
+	Heading    *float64  `json:"heading,omitempty" db:"heading"` // Degrees clockwise from north
+	AccuracyM  *float64  `json:"accuracy_m,omitempty" db:"accuracy_m"`
+	RecordedAt time.Time `json:"recorded_at" db:"recorded_at"`
+	ReceivedAt time.Time `json:"-" db:"received_at"`
+}
+
+// LocationPingsRequest is a batch of GPS pings a driver's app collected since its last upload
+type LocationPingsRequest struct {
+	Pings []DriverLocation `json:"pings"`
+}
+
+// Tracking targets of a ride
+const (
+	TrackingTargetPickup  = "pickup"
+	TrackingTargetDropoff = "dropoff"
+)
+
+// RideTracking is the rider's live view of their driver: the last known location and an ETA to the
+// pickup while the driver is en route, or to the drop-off once the ride is in progress
+type RideTracking struct {
+	BookingID  int64           `json:"booking_id"`
+	RideStatus string          `json:"ride_status"`
+	Sharing    bool            `json:"sharing"` // False before a driver is assigned and after the ride ends
+	Location   *DriverLocation `json:"location,omitempty"`
+	Stale      bool            `json:"stale,omitempty"` // The driver has not reported a location recently
+	Target     string          `json:"target,omitempty"`
+	DistanceKm *float64        `json:"distance_km,omitempty"`
+	ETAMinutes *int            `json:"eta_minutes,omitempty"`
+	ETA        *time.Time      `json:"eta,omitempty"`
+}
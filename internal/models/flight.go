@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Flight statuses reported by flight status providers
+const (
+	FlightStatusScheduled = "scheduled"
+	FlightStatusDelayed   = "delayed"
+	FlightStatusLanded    = "landed"
+	FlightStatusCancelled = "cancelled"
+)
+
+// FlightDetails is the inbound flight of an airport pickup, stored as JSONB on book_rides.
+// The tracking fields are maintained by the flight tracker, not by clients.
+type FlightDetails struct {
+	Airline          string     `json:"airline"`
+	FlightNumber     string     `json:"flight_number"`
+	ScheduledArrival time.Time  `json:"scheduled_arrival"`
+	Terminal         string     `json:"terminal,omitempty"`
+	MeetAndGreet     bool       `json:"meet_and_greet"`
+	Status           string     `json:"status,omitempty"`
+	EstimatedArrival *time.Time `json:"estimated_arrival,omitempty"`
+	OriginalPickup   string     `json:"original_pickup,omitempty"` // Pickup date and time before any delay shift
+	CheckedAt        *time.Time `json:"checked_at,omitempty"`
+}
+
+// Tracked reports whether the flight still needs status checks
+func (f *FlightDetails) Tracked() bool {
+	return f != nil && f.Status != FlightStatusLanded && f.Status != FlightStatusCancelled
+}
+
+// Delay returns how late the flight is expected to arrive; early arrivals count as no delay
+func (f *FlightDetails) Delay() time.Duration {
+	if f == nil || f.EstimatedArrival == nil {
+		return 0
+	}
+	if delay := f.EstimatedArrival.Sub(f.ScheduledArrival); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
+// Value implements driver.Valuer
+func (f *FlightDetails) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return json.Marshal(f)
+}
+
+// Scan implements sql.Scanner
+func (f *FlightDetails) Scan(src interface{}) error {
+	return scanJSON(src, f)
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// GeoJSONGeometry is a raw GeoJSON geometry object (Polygon or MultiPolygon)
+type GeoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// Value implements driver.Valuer
+func (g *GeoJSONGeometry) Value() (driver.Value, error) {
+	if g == nil {
+		return nil, nil
+	}
+	return json.Marshal(g)
+}
+
+// Scan implements sql.Scanner
+func (g *GeoJSONGeometry) Scan(src interface{}) error {
+	return scanJSON(src, g)
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// IdempotencyKeyHeader carries the client's key for safely retrying a mutating request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader is set on responses replayed from an earlier request with the same key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// IdempotencyKey is the first response to a request sent with an Idempotency-Key header
+type IdempotencyKey struct {
+	ID                  int64     `db:"id"`
+	Principal           string    `db:"principal"` // "user:<id>", "api_key:<id>" or "guest"
+	Key                 string    `db:"key"`
+	RequestHash         string    `db:"request_hash"`
+	ResponseStatus      *int      `db:"response_status"` // Nil while the first request is in progress
+	ResponseContentType string    `db:"response_content_type"`
+	ResponseBody        []byte    `db:"response_body"`
+	CreatedAt           time.Time `db:"created_at"`
+	ExpiresAt           time.Time `db:"expires_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// JobLease is the schedule and current lease of a background job
+type JobLease struct {
+	Name        string     `json:"name" db:"name"`
+	Schedule    string     `json:"schedule" db:"schedule"`       // e.g. "every 5m0s" or "daily at 06:00 UTC"
+	Holder      string     `json:"holder,omitempty" db:"holder"` // Instance that last ran the job
+	LeasedUntil time.Time  `json:"leased_until" db:"leased_until"`
+	NextRunAt   time.Time  `json:"next_run_at" db:"next_run_at"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty" db:"last_run_at"`
+	LastError   string     `json:"last_error,omitempty" db:"last_error"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Running reports whether an instance currently holds the job's lease
+func (l *JobLease) Running(now time.Time) bool {
+	return l.LeasedUntil.After(now)
+}
+
+// Kinds of notices sent about a booking
+const (
+	NoticeUnassignedAlert = "unassigned_alert"
+)
+
+// ReminderNotice is the notice kind of the reminder sent the given time before pickup
+func ReminderNotice(before time.Duration) string {
+	return fmt.Sprintf("reminder_%dm", int(before.Minutes()))
+}
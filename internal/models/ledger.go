@@ -0,0 +1,130 @@
+package models
+
+import "time"
+
+// Ledger accounts. Entries are signed: debits are positive and credits negative,
+// so the entries of every transaction sum to zero.
+const (
+	LedgerAccountRiderPayments  = "rider_payments" // Money collected from riders
+	LedgerAccountDriverPayable  = "driver_payable" // Owed to a driver until paid out
+	LedgerAccountCommission     = "platform_commission"
+	LedgerAccountPayoutClearing = "payout_clearing" // Payouts sent but not yet confirmed
+	LedgerAccountCash           = "cash"
+)
+
+// Ledger entry categories, used to break down driver earnings
+const (
+	LedgerCategoryFare       = "fare"
+	LedgerCategoryTip        = "tip"
+	LedgerCategoryExtra      = "extra"
+	LedgerCategoryAdjustment = "adjustment"
+	LedgerCategoryCommission = "commission"
+	LedgerCategoryPayout     = "payout"
+)
+
+// Ledger transaction kinds
+const (
+	LedgerKindBookingCompleted = "booking_completed"
+	LedgerKindPostRide         = "post_ride_adjustment"
+	LedgerKindFareAdjustment   = "fare_adjustment"
+	LedgerKindRefund           = "refund"
+	LedgerKindPayout           = "payout"
+	LedgerKindPayoutPaid       = "payout_paid"
+	LedgerKindPayoutFailed     = "payout_failed"
+)
+
+// LedgerTransaction groups balanced ledger entries; its reference makes posting idempotent
+type LedgerTransaction struct {
+	ID          int64         `json:"id" db:"id"`
+	Reference   string        `json:"reference" db:"reference"`
+	Kind        string        `json:"kind" db:"kind"`
+	BookingID   *int64        `json:"booking_id,omitempty" db:"booking_id"`
+	PayoutID    *int64        `json:"payout_id,omitempty" db:"payout_id"`
+	Description string        `json:"description" db:"description"`
+	OccurredAt  time.Time     `json:"occurred_at" db:"occurred_at"`
+	Entries     []LedgerEntry `json:"entries,omitempty" db:"-"`
+}
+
+// LedgerEntry is a single debit (positive) or credit (negative) to an account
+type LedgerEntry struct {
+	ID            int64     `json:"id" db:"id"`
+	TransactionID int64     `json:"transaction_id" db:"transaction_id"`
+	Account       string    `json:"account" db:"account"`
+	DriverID      *int64    `json:"driver_id,omitempty" db:"driver_id"`
+	Category      string    `json:"category" db:"category"`
+	Amount        Money     `json:"amount" db:"amount"`
+	Currency      string    `json:"currency" db:"currency"`
+	BookingID     *int64    `json:"booking_id,omitempty" db:"booking_id"`   // From the transaction
+	Description   string    `json:"description,omitempty" db:"description"` // From the transaction
+	OccurredAt    time.Time `json:"occurred_at" db:"occurred_at"`           // From the transaction
+}
+
+// EarningsSummary breaks down what a driver earned in a period
+type EarningsSummary struct {
+	DriverID    int64     `json:"driver_id"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	Currency    string    `json:"currency"`
+	Bookings    int       `json:"bookings"`
+	Gross       Money     `json:"gross"` // Collected from riders, before commission
+	Commission  Money     `json:"commission"`
+	FareShare   Money     `json:"fare_share"`
+	Tips        Money     `json:"tips"`
+	Extras      Money     `json:"extras"`
+	Adjustments Money     `json:"adjustments"`
+	Earned      Money     `json:"earned"`
+	PaidOut     Money     `json:"paid_out"`
+	Balance     Money     `json:"balance"` // Owed to the driver across all time
+}
+
+// Payout statuses
+const (
+	PayoutStatusPending = "pending"
+	PayoutStatusPaid    = "paid"
+	PayoutStatusFailed  = "failed"
+)
+
+// PayoutBatch collects the payouts of one weekly period
+type PayoutBatch struct {
+	ID          int64     `json:"id" db:"id"`
+	PeriodStart time.Time `json:"period_start" db:"period_start"`
+	PeriodEnd   time.Time `json:"period_end" db:"period_end"` // Exclusive
+	Currency    string    `json:"currency" db:"currency"`
+	TotalAmount Money     `json:"total_amount" db:"total_amount"`
+	PayoutCount int       `json:"payout_count" db:"payout_count"`
+	CreatedBy   *int64    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	Payouts     []*Payout `json:"payouts,omitempty" db:"-"`
+}
+
+// Payout is the amount sent to one driver in a batch
+type Payout struct {
+	ID             int64      `json:"id" db:"id"`
+	BatchID        int64      `json:"batch_id" db:"batch_id"`
+	DriverID       int64      `json:"driver_id" db:"driver_id"`
+	DriverUsername string     `json:"driver_username,omitempty" db:"driver_username"`
+	DriverEmail    string     `json:"driver_email,omitempty" db:"driver_email"`
+	Amount         Money      `json:"amount" db:"amount"`
+	Currency       string     `json:"currency" db:"currency"`
+	Status         string     `json:"status" db:"status"`
+	Reference      string     `json:"reference,omitempty" db:"reference"` // Bank or provider reference once paid
+	FailureReason  string     `json:"failure_reason,omitempty" db:"failure_reason"`
+	PaidAt         *time.Time `json:"paid_at,omitempty" db:"paid_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// GeneratePayoutBatchRequest selects the week to pay out; it defaults to the last full week
+type GeneratePayoutBatchRequest struct {
+	WeekStart string `json:"week_start,omitempty"` // YYYY-MM-DD, must be a Monday
+}
+
+// PayoutPaidRequest confirms a payout with the bank or provider reference
+type PayoutPaidRequest struct {
+	Reference string `json:"reference"`
+}
+
+// PayoutFailedRequest records why a payout failed; its amount returns to the driver's balance
+type PayoutFailedRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Location is a resolved address with coordinates
+type Location struct {
+	FormattedAddress string   `json:"formatted_address,omitempty"`
+	Lat              *float64 `json:"lat,omitempty"`
+	Lng              *float64 `json:"lng,omitempty"`
+	PlaceID          string   `json:"place_id,omitempty"`
+	DriverNotes      string   `json:"driver_notes,omitempty"` // e.g. "gate code 1234, use side entrance"
+}
+
+// HasCoordinates reports whether the location has been resolved to a point
+func (l *Location) HasCoordinates() bool {
+	return l != nil && l.Lat != nil && l.Lng != nil
+}
+
+// Value implements driver.Valuer
+func (l *Location) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements sql.Scanner
+func (l *Location) Scan(src interface{}) error {
+	return scanJSON(src, l)
+}
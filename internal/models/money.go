@@ -0,0 +1,99 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultCurrency is the ISO 4217 currency code used when none is specified
+const DefaultCurrency = "USD"
+
+// Money is an amount in integer minor units (cents for USD).
+// Fares must always be carried as Money, never as float64.
+type Money int64
+
+// Add returns the sum of two amounts
+func (m Money) Add(other Money) Money {
+	return m + other
+}
+
+// Mul multiplies the amount by an integer quantity
+func (m Money) Mul(quantity int64) Money {
+	return m * Money(quantity)
+}
+
+// Percent returns the given percentage of the amount, rounded half away from zero
+func (m Money) Percent(percent int64) Money {
+	product := int64(m) * percent
+	if product >= 0 {
+		return Money((product + 50) / 100)
+	}
+	return Money((product - 50) / 100)
+}
+
+// Format renders the amount with two decimal places and its currency code
+func (m Money) Format(currency string) string {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, value/100, value%100, currency)
+}
+
+// LineItemAdjustment is the line item code recorded for admin fare overrides
+const LineItemAdjustment = "admin_adjustment"
+
+// LineItem is a single priced component of a fare
+type LineItem struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	UnitAmount  Money  `json:"unit_amount"`
+	Amount      Money  `json:"amount"`
+}
+
+// NewLineItem builds a line item whose amount is unit amount times quantity
+func NewLineItem(code, description string, quantity int64, unitAmount Money) LineItem {
+	return LineItem{
+		Code:        code,
+		Description: description,
+		Quantity:    quantity,
+		UnitAmount:  unitAmount,
+		Amount:      unitAmount.Mul(quantity),
+	}
+}
+
+// LineItems is stored as a JSONB array on book_rides
+type LineItems []LineItem
+
+// Total sums the amounts of all line items
+func (l LineItems) Total() Money {
+	var total Money
+	for _, item := range l {
+		total = total.Add(item.Amount)
+	}
+	return total
+}
+
+// Value implements driver.Valuer
+func (l LineItems) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements sql.Scanner
+func (l *LineItems) Scan(src interface{}) error {
+	if src == nil {
+		*l = nil
+		return nil
+	}
+	return scanJSON(src, l)
+}
@@ -0,0 +1,46 @@
+package models
+
+import "testing"
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		percent int64
+		want    Money
+	}{
+		{"exact", 10000, 15, 1500},
+		{"half rounds up", 333, 50, 167},
+		{"half cent rounds up", 1, 50, 1},
+		{"below half rounds down", 1, 49, 0},
+		{"negative half rounds away from zero", -333, 50, -167},
+		{"zero percent", 12345, 0, 0},
+		{"over one hundred percent", 999, 150, 1499},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.amount.Percent(tt.percent); got != tt.want {
+				t.Errorf("Money(%d).Percent(%d) = %d, want %d", tt.amount, tt.percent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		amount   Money
+		currency string
+		want     string
+	}{
+		{12345, "USD", "123.45 USD"},
+		{100, "EUR", "1.00 EUR"},
+		{7, "", "0.07 USD"},
+		{-5, "USD", "-0.05 USD"},
+		{-12345, "USD", "-123.45 USD"},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Format(tt.currency); got != tt.want {
+			t.Errorf("Money(%d).Format(%q) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
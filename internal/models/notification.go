@@ -0,0 +1,153 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Channels a notification can be delivered on
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelPush    = "push"
+	ChannelWebhook = "webhook"
+)
+
+// NotificationChannelNames lists every channel users can choose
+var NotificationChannelNames = []string{ChannelEmail, ChannelSMS, ChannelPush, ChannelWebhook}
+
+// Notification event types
+const (
+	NotificationBookingConfirmed  = "booking_confirmed"
+	NotificationDriverAssigned    = "driver_assigned"
+	NotificationDriverArriving    = "driver_arriving"
+	NotificationBookingCancelled  = "booking_cancelled"
+	NotificationRideReminder      = "ride_reminder"
+	NotificationFlightDelay       = "flight_delay"
+	NotificationRideCompleted     = "ride_completed"
+	NotificationAdjustmentReceipt = "adjustment_receipt"
+	NotificationDriverManifest    = "driver_manifest"
+	NotificationWelcome           = "welcome"
+	NotificationBookingLink       = "booking_link"
+	NotificationPasswordReset     = "password_reset"
+	NotificationUnassignedAlert   = "unassigned_alert"
+)
+
+// NotificationEvent describes an event users are notified of
+type NotificationEvent struct {
+	Type     string `json:"type"`
+	Critical bool   `json:"critical"` // Always sent, whatever the recipient's preferences
+	Urgent   bool   `json:"urgent"`   // Sent during quiet hours
+}
+
+// NotificationEvents lists every event type in the order shown to users
+var NotificationEvents = []NotificationEvent{
+	{Type: NotificationBookingConfirmed},
+	{Type: NotificationDriverAssigned},
+	{Type: NotificationDriverArriving, Urgent: true},
+	{Type: NotificationBookingCancelled},
+	{Type: NotificationRideReminder},
+	{Type: NotificationFlightDelay, Urgent: true},
+	{Type: NotificationRideCompleted},
+	{Type: NotificationAdjustmentReceipt},
+	{Type: NotificationDriverManifest},
+	{Type: NotificationWelcome},
+	{Type: NotificationBookingLink, Critical: true},
+	{Type: NotificationPasswordReset, Critical: true},
+	{Type: NotificationUnassignedAlert, Critical: true},
+}
+
+// LookupNotificationEvent returns the event of a type
+func LookupNotificationEvent(eventType string) (NotificationEvent, bool) {
+	for _, event := range NotificationEvents {
+		if event.Type == eventType {
+			return event, true
+		}
+	}
+	return NotificationEvent{}, false
+}
+
+// Languages notifications are written in
+const (
+	LanguageEnglish = "en"
+	LanguageSpanish = "es"
+)
+
+// DefaultLanguage is used for recipients without preferences and for messages not yet translated
+const DefaultLanguage = LanguageEnglish
+
+// NotificationLanguages lists the languages users can choose
+var NotificationLanguages = []string{LanguageEnglish, LanguageSpanish}
+
+// ChannelChoices maps an event type to whether each channel is used for it
+type ChannelChoices map[string]map[string]bool
+
+// Value implements driver.Valuer
+func (c ChannelChoices) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *ChannelChoices) Scan(src interface{}) error {
+	return scanJSON(src, c)
+}
+
+// NotificationPreferences are a user's choices of what they are told and how
+type NotificationPreferences struct {
+	UserID          int64          `json:"user_id" db:"user_id"`
+	Channels        ChannelChoices `json:"channels" db:"channels"`
+	QuietHoursStart *string        `json:"quiet_hours_start,omitempty" db:"quiet_hours_start"` // HH:MM
+	QuietHoursEnd   *string        `json:"quiet_hours_end,omitempty" db:"quiet_hours_end"`     // HH:MM
+	Timezone        string         `json:"timezone" db:"timezone"`
+	Language        string         `json:"language" db:"language"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultNotificationPreferences are the preferences of users who have not chosen any, and of guests
+func DefaultNotificationPreferences(userID int64) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:   userID,
+		Channels: ChannelChoices{},
+		Timezone: "UTC",
+		Language: DefaultLanguage,
+	}
+}
+
+// Enabled reports whether an event is sent on a channel. Channels not chosen are on, except
+// webhooks, which must be turned on.
+func (p *NotificationPreferences) Enabled(eventType, channel string) bool {
+	if enabled, ok := p.Channels[eventType][channel]; ok {
+		return enabled
+	}
+	return channel != ChannelWebhook
+}
+
+// InQuietHours reports whether a time falls within the quiet hours, which may span midnight
+func (p *NotificationPreferences) InQuietHours(t time.Time) bool {
+	if p.QuietHoursStart == nil || p.QuietHoursEnd == nil || *p.QuietHoursStart == *p.QuietHoursEnd {
+		return false
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	clock := t.In(loc).Format("15:04")
+	start, end := *p.QuietHoursStart, *p.QuietHoursEnd
+	if start < end {
+		return clock >= start && clock < end
+	}
+	return clock >= start || clock < end
+}
+
+// UpdateNotificationPreferencesRequest replaces a user's notification preferences
+type UpdateNotificationPreferencesRequest struct {
+	Channels        ChannelChoices `json:"channels"`
+	QuietHoursStart *string        `json:"quiet_hours_start"`
+	QuietHoursEnd   *string        `json:"quiet_hours_end"`
+	Timezone        string         `json:"timezone"`
+	Language        string         `json:"language"`
+}
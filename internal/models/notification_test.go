@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInQuietHours(t *testing.T) {
+	clock := func(s string) *string { return &s }
+	tests := []struct {
+		name     string
+		start    *string
+		end      *string
+		timezone string
+		at       time.Time
+		want     bool
+	}{
+		{"no quiet hours", nil, nil, "UTC", time.Date(2025, 10, 18, 23, 0, 0, 0, time.UTC), false},
+		{"equal start and end", clock("22:00"), clock("22:00"), "UTC", time.Date(2025, 10, 18, 22, 0, 0, 0, time.UTC), false},
+		{"same day inside", clock("13:00"), clock("15:00"), "UTC", time.Date(2025, 10, 18, 14, 0, 0, 0, time.UTC), true},
+		{"same day at end", clock("13:00"), clock("15:00"), "UTC", time.Date(2025, 10, 18, 15, 0, 0, 0, time.UTC), false},
+		{"spanning midnight at start", clock("22:00"), clock("07:00"), "UTC", time.Date(2025, 10, 18, 22, 0, 0, 0, time.UTC), true},
+		{"spanning midnight after midnight", clock("22:00"), clock("07:00"), "UTC", time.Date(2025, 10, 19, 3, 30, 0, 0, time.UTC), true},
+		{"spanning midnight at end", clock("22:00"), clock("07:00"), "UTC", time.Date(2025, 10, 19, 7, 0, 0, 0, time.UTC), false},
+		{"spanning midnight during the day", clock("22:00"), clock("07:00"), "UTC", time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC), false},
+		// 02:00 UTC is 22:00 the evening before in New York (EDT)
+		{"user time zone", clock("22:00"), clock("07:00"), "America/New_York", time.Date(2025, 10, 19, 2, 0, 0, 0, time.UTC), true},
+		{"user time zone daytime", clock("22:00"), clock("07:00"), "America/New_York", time.Date(2025, 10, 19, 12, 0, 0, 0, time.UTC), false},
+		{"unknown time zone falls back to UTC", clock("22:00"), clock("07:00"), "Nowhere/City", time.Date(2025, 10, 19, 2, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &NotificationPreferences{QuietHoursStart: tt.start, QuietHoursEnd: tt.end, Timezone: tt.timezone}
+			if got := p.InQuietHours(tt.at); got != tt.want {
+				t.Errorf("InQuietHours(%s) = %v, want %v", tt.at.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
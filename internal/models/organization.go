@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// Organization is a corporate account whose members' bookings are billed centrally with a monthly invoice
+type Organization struct {
+	ID                int64     `json:"id" db:"id"`
+	Name              string    `json:"name" db:"name"`
+	BillingEmail      string    `json:"billing_email" db:"billing_email"`
+	BillingAddress    string    `json:"billing_address,omitempty" db:"billing_address"`
+	TaxID             string    `json:"tax_id,omitempty" db:"tax_id"`
+	Currency          string    `json:"currency" db:"currency"`
+	ApprovalThreshold *Money    `json:"approval_threshold,omitempty" db:"approval_threshold"` // Bookings quoted above this need an org admin's approval
+	RequireCostCenter bool      `json:"require_cost_center" db:"require_cost_center"`
+	Active            bool      `json:"active" db:"active"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NeedsApproval reports whether a booking quoted at amount must be approved by an org admin
+func (o *Organization) NeedsApproval(amount Money) bool {
+	return o.ApprovalThreshold != nil && amount > *o.ApprovalThreshold
+}
+
+// Organization member roles
+const (
+	OrgRoleAdmin    = "org_admin" // Manages members and cost centers, approves bookings and sees invoices
+	OrgRoleEmployee = "employee"
+)
+
+// OrganizationMember links a user to the organization they book for
+type OrganizationMember struct {
+	ID                  int64     `json:"id" db:"id"`
+	OrganizationID      int64     `json:"organization_id" db:"organization_id"`
+	UserID              int64     `json:"user_id" db:"user_id"`
+	Username            string    `json:"username,omitempty" db:"username"`
+	Email               string    `json:"email,omitempty" db:"email"`
+	Role                string    `json:"role" db:"role"`
+	DefaultCostCenterID *int64    `json:"default_cost_center_id,omitempty" db:"default_cost_center_id"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// IsOrgAdmin reports whether the member administers their organization
+func (m *OrganizationMember) IsOrgAdmin() bool {
+	return m.Role == OrgRoleAdmin
+}
+
+// CostCenter is an internal budget of an organization that bookings are charged to
+type CostCenter struct {
+	ID             int64     `json:"id" db:"id"`
+	OrganizationID int64     `json:"organization_id" db:"organization_id"`
+	Code           string    `json:"code" db:"code"`
+	Name           string    `json:"name" db:"name"`
+	Active         bool      `json:"active" db:"active"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Organization approval statuses of a booking
+const (
+	OrgApprovalNotRequired = "not_required"
+	OrgApprovalPending     = "pending"
+	OrgApprovalApproved    = "approved"
+	OrgApprovalRejected    = "rejected"
+)
+
+// AddOrganizationMemberRequest adds an existing user to an organization by email
+type AddOrganizationMemberRequest struct {
+	Email      string `json:"email"`
+	Role       string `json:"role"`
+	CostCenter string `json:"cost_center,omitempty"` // Default cost center code
+}
+
+// UpdateOrganizationMemberRequest changes a member's role or default cost center
+type UpdateOrganizationMemberRequest struct {
+	Role       string  `json:"role,omitempty"`
+	CostCenter *string `json:"cost_center,omitempty"` // Default cost center code; empty clears it
+}
+
+// ApprovalRulesRequest sets an organization's booking approval rules; a null threshold turns approvals off
+type ApprovalRulesRequest struct {
+	ApprovalThreshold *Money `json:"approval_threshold"`
+	RequireCostCenter bool   `json:"require_cost_center"`
+}
+
+// RejectOrgBookingRequest records why an org admin rejected a booking
+type RejectOrgBookingRequest struct {
+	Reason string `json:"reason"`
+}
+
+// GenerateInvoiceRequest selects the month to invoice; it defaults to the previous month
+type GenerateInvoiceRequest struct {
+	Month string `json:"month,omitempty"` // YYYY-MM
+}
+
+// OrganizationInvoice is the consolidated monthly invoice of an organization
+type OrganizationInvoice struct {
+	ID             int64                      `json:"id" db:"id"`
+	OrganizationID int64                      `json:"organization_id" db:"organization_id"`
+	Number         string                     `json:"number" db:"number"`
+	PeriodStart    time.Time                  `json:"period_start" db:"period_start"`
+	PeriodEnd      time.Time                  `json:"period_end" db:"period_end"` // Exclusive
+	Currency       string                     `json:"currency" db:"currency"`
+	TotalAmount    Money                      `json:"total_amount" db:"total_amount"`
+	BookingCount   int                        `json:"booking_count" db:"booking_count"`
+	IssuedAt       time.Time                  `json:"issued_at" db:"issued_at"`
+	Lines          []*OrganizationInvoiceLine `json:"lines,omitempty" db:"-"`
+}
+
+// OrganizationInvoiceLine is one booking on an organization invoice
+type OrganizationInvoiceLine struct {
+	ID             int64  `json:"id" db:"id"`
+	InvoiceID      int64  `json:"invoice_id" db:"invoice_id"`
+	BookingID      int64  `json:"booking_id" db:"booking_id"`
+	CostCenterCode string `json:"cost_center_code,omitempty" db:"cost_center_code"`
+	RiderName      string `json:"rider_name" db:"rider_name"`
+	RideDate       string `json:"ride_date" db:"ride_date"`
+	Description    string `json:"description" db:"description"`
+	Amount         Money  `json:"amount" db:"amount"`
+}
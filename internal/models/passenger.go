@@ -0,0 +1,116 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Notification recipients of a booking
+const (
+	NotifyBooker    = "booker"
+	NotifyPassenger = "passenger"
+	NotifyBoth      = "both"
+)
+
+// Contact is a person's name and contact details on a booking
+type Contact struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+// BookingNotifications chooses who receives each kind of message about a booking booked for
+// someone else. Empty choices use the defaults: trip updates go to both, receipts to the booker
+// and text messages to the passenger.
+type BookingNotifications struct {
+	TripUpdates string `json:"trip_updates,omitempty"` // Flight delays, reminders and driver updates by email
+	Receipts    string `json:"receipts,omitempty"`     // Completion and final receipt emails
+	SMS         string `json:"sms,omitempty"`          // Text messages about the ride
+}
+
+// Value implements driver.Valuer
+func (n *BookingNotifications) Value() (driver.Value, error) {
+	if n == nil {
+		return nil, nil
+	}
+	return json.Marshal(n)
+}
+
+// Scan implements sql.Scanner
+func (n *BookingNotifications) Scan(src interface{}) error {
+	return scanJSON(src, n)
+}
+
+// HasPassenger reports whether the booking was made for someone other than the booker
+func (br *BookRide) HasPassenger() bool {
+	return br.PassengerName != ""
+}
+
+// Booker returns the contact details of the person who made and manages the booking
+func (br *BookRide) Booker() Contact {
+	return Contact{Name: br.YourName, Email: br.Email, Phone: br.PhoneNumber}
+}
+
+// Passenger returns the contact details of the person riding. A passenger without a phone
+// number is reached through the booker's.
+func (br *BookRide) Passenger() Contact {
+	if !br.HasPassenger() {
+		return br.Booker()
+	}
+	passenger := Contact{Name: br.PassengerName, Email: br.PassengerEmail, Phone: br.PassengerPhone}
+	if passenger.Phone == "" {
+		passenger.Phone = br.PhoneNumber
+	}
+	return passenger
+}
+
+// TripUpdateRecipients returns who is emailed flight delays, reminders and driver updates
+func (br *BookRide) TripUpdateRecipients() []Contact {
+	return br.emailRecipients(br.notifications().TripUpdates, NotifyBoth)
+}
+
+// ReceiptRecipients returns who is emailed completion and final receipts
+func (br *BookRide) ReceiptRecipients() []Contact {
+	return br.emailRecipients(br.notifications().Receipts, NotifyBooker)
+}
+
+// SMSRecipients returns who is texted about the ride
+func (br *BookRide) SMSRecipients() []Contact {
+	choice := br.notifications().SMS
+	if choice == "" {
+		choice = NotifyPassenger
+	}
+	booker, passenger := br.Booker(), br.Passenger()
+	switch {
+	case !br.HasPassenger() || choice == NotifyBooker || passenger.Phone == booker.Phone:
+		return []Contact{booker}
+	case choice == NotifyPassenger:
+		return []Contact{passenger}
+	default:
+		return []Contact{booker, passenger}
+	}
+}
+
+// emailRecipients resolves an email recipient choice. The booker is always emailed when the
+// passenger has no email address.
+func (br *BookRide) emailRecipients(choice, fallback string) []Contact {
+	if choice == "" {
+		choice = fallback
+	}
+	booker, passenger := br.Booker(), br.Passenger()
+	switch {
+	case !br.HasPassenger() || choice == NotifyBooker || passenger.Email == "" || passenger.Email == booker.Email:
+		return []Contact{booker}
+	case choice == NotifyPassenger:
+		return []Contact{passenger}
+	default:
+		return []Contact{booker, passenger}
+	}
+}
+
+func (br *BookRide) notifications() BookingNotifications {
+	if br.Notifications == nil {
+		return BookingNotifications{}
+	}
+	return *br.Notifications
+}
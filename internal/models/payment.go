@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Payment tracks a single payment intent against a booking
+type Payment struct {
+	ID               int64      `json:"id" db:"id"`
+	BookingID        int64      `json:"booking_id" db:"booking_id"`
+	Provider         string     `json:"provider" db:"provider"`
+	ProviderRef      string     `json:"provider_ref" db:"provider_ref"`
+	Purpose          string     `json:"purpose" db:"purpose"`
+	PaymentMethod    string     `json:"-" db:"payment_method"` // Provider token, reused for post-ride charges
+	Status           string     `json:"status" db:"status"`
+	AuthorizedAmount Money      `json:"authorized_amount" db:"authorized_amount"`
+	CapturedAmount   Money      `json:"captured_amount" db:"captured_amount"`
+	RefundedAmount   Money      `json:"refunded_amount" db:"refunded_amount"`
+	Currency         string     `json:"currency" db:"currency"`
+	FailureReason    string     `json:"failure_reason,omitempty" db:"failure_reason"`
+	LastEventAt      *time.Time `json:"-" db:"last_event_at"` // Time of the newest applied provider event
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// PaymentEvent records a provider event that was handled, so redeliveries are skipped
+type PaymentEvent struct {
+	Provider   string    `json:"provider" db:"provider"`
+	EventID    string    `json:"event_id" db:"event_id"`
+	PaymentID  int64     `json:"payment_id" db:"payment_id"`
+	Type       string    `json:"type" db:"type"`
+	Applied    bool      `json:"applied" db:"applied"` // False when the event did not move the payment forward
+	ReceivedAt time.Time `json:"received_at" db:"received_at"`
+}
+
+// Payment purposes; the fare payment is the one captured, voided and refunded with the booking
+const (
+	PaymentPurposeFare     = "fare"
+	PaymentPurposePostRide = "post_ride"
+)
+
+// RefundRequest represents an admin refund of a captured payment
+type RefundRequest struct {
+	Amount Money  `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+// PaymentRefund records who refunded how much of a payment, and why
+type PaymentRefund struct {
+	ID        int64     `json:"id" db:"id"`
+	PaymentID int64     `json:"payment_id" db:"payment_id"`
+	BookingID int64     `json:"booking_id" db:"booking_id"`
+	AdminID   *int64    `json:"admin_id,omitempty" db:"admin_id"`
+	Amount    Money     `json:"amount" db:"amount"`
+	Currency  string    `json:"currency" db:"currency"`
+	Reason    string    `json:"reason" db:"reason"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Payment status constants
+const (
+	PaymentStatusAuthorized        = "authorized"
+	PaymentStatusCaptured          = "captured"
+	PaymentStatusVoided            = "voided"
+	PaymentStatusPartiallyRefunded = "partially_refunded"
+	PaymentStatusRefunded          = "refunded"
+	PaymentStatusFailed            = "failed"
+)
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Post-ride adjustment kinds; tips come from riders, the others are extras submitted by drivers
+const (
+	AdjustmentTip         = "tip"
+	AdjustmentTolls       = "tolls"
+	AdjustmentParking     = "parking"
+	AdjustmentWaitingTime = "waiting_time"
+)
+
+// Post-ride adjustment statuses
+const (
+	AdjustmentStatusPending  = "pending"  // Waiting for rider or admin approval
+	AdjustmentStatusCharging = "charging" // Claimed for a charge that has not returned yet
+	AdjustmentStatusRejected = "rejected" // Declined; never charged
+	AdjustmentStatusCharged  = "charged"  // Charged and added to the final fare
+	AdjustmentStatusFailed   = "failed"   // Approved, but the charge failed
+)
+
+// PostRideAdjustment is an amount added to a completed booking: a rider tip or a driver extra
+type PostRideAdjustment struct {
+	ID               int64      `json:"id" db:"id"`
+	BookingID        int64      `json:"booking_id" db:"booking_id"`
+	Kind             string     `json:"kind" db:"kind"`
+	Amount           Money      `json:"amount" db:"amount"`
+	Currency         string     `json:"currency" db:"currency"`
+	Minutes          *int       `json:"minutes,omitempty" db:"minutes"` // Waiting time only
+	Description      string     `json:"description,omitempty" db:"description"`
+	SubmittedBy      *int64     `json:"submitted_by,omitempty" db:"submitted_by"` // Nil for guest tips
+	Status           string     `json:"status" db:"status"`
+	RequiresApproval bool       `json:"requires_approval" db:"requires_approval"`
+	DecidedBy        *int64     `json:"decided_by,omitempty" db:"decided_by"`
+	DecidedAt        *time.Time `json:"decided_at,omitempty" db:"decided_at"`
+	PaymentID        *int64     `json:"payment_id,omitempty" db:"payment_id"`
+	FailureReason    string     `json:"failure_reason,omitempty" db:"failure_reason"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// TipRequest is a tip a rider adds after a completed ride
+type TipRequest struct {
+	Amount Money `json:"amount"`
+}
+
+// RideExtraRequest is an extra a driver records after a completed ride.
+// Tolls and parking carry an amount; waiting time carries minutes and is priced from the rate card.
+type RideExtraRequest struct {
+	Kind        string `json:"kind"`
+	Amount      Money  `json:"amount,omitempty"`
+	Minutes     int    `json:"minutes,omitempty"`
+	Description string `json:"description,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Rating directions; each party of a completed booking rates the other once
+const (
+	RatingRiderToDriver = "rider_to_driver"
+	RatingDriverToRider = "driver_to_rider"
+)
+
+// Rating is the score one party of a completed booking gave the other
+type Rating struct {
+	ID         int64          `json:"id" db:"id"`
+	BookingID  int64          `json:"booking_id" db:"booking_id"`
+	Direction  string         `json:"direction" db:"direction"`
+	RaterID    *int64         `json:"rater_id,omitempty" db:"rater_id"` // Nil for guest riders
+	RateeID    *int64         `json:"ratee_id,omitempty" db:"ratee_id"` // Nil when rating a guest rider
+	Score      int            `json:"score" db:"score"`
+	Tags       pq.StringArray `json:"tags" db:"tags"`
+	Comment    string         `json:"comment,omitempty" db:"comment"`
+	Flagged    bool           `json:"flagged" db:"flagged"`
+	ReviewedBy *int64         `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt *time.Time     `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewNote string         `json:"review_note,omitempty" db:"review_note"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+// CreateRatingRequest is the score a rider or driver submits after a completed ride
+type CreateRatingRequest struct {
+	Score   int      `json:"score"`
+	Tags    []string `json:"tags,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// ReviewRatingRequest closes the review of a flagged rating
+type ReviewRatingRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+// RatingSummary aggregates the ratings a user has received
+type RatingSummary struct {
+	Count        int     `json:"count" db:"count"`
+	Average      float64 `json:"average" db:"average"` // Rounded to two decimals; 0 without ratings
+	FlaggedCount int     `json:"flagged_count" db:"flagged_count"`
+}
+
+// DriverProfile is the public profile of a driver
+type DriverProfile struct {
+	ID       int64          `json:"id"`
+	Username string         `json:"username"`
+	Rating   *RatingSummary `json:"rating"`
+}
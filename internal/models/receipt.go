@@ -0,0 +1,55 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultLegalEntityCode is the code of the legal entity used when a booking's zone names none
+const DefaultLegalEntityCode = "default"
+
+// LegalEntity is a company that issues receipts, with its own receipt number sequence
+type LegalEntity struct {
+	ID                int64     `json:"id" db:"id"`
+	Code              string    `json:"code" db:"code"`
+	Name              string    `json:"name" db:"name"`
+	Address           string    `json:"address" db:"address"`
+	TaxID             string    `json:"tax_id" db:"tax_id"`
+	TaxLabel          string    `json:"tax_label" db:"tax_label"`
+	TaxRateBP         int       `json:"tax_rate_bp" db:"tax_rate_bp"` // Basis points included in fares, 2000 = 20%
+	Email             string    `json:"email" db:"email"`
+	Phone             string    `json:"phone" db:"phone"`
+	ReceiptPrefix     string    `json:"receipt_prefix" db:"receipt_prefix"`
+	NextReceiptNumber int64     `json:"next_receipt_number" db:"next_receipt_number"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ReceiptNumber formats a sequence number of the entity, e.g. LUX-000042
+func (e *LegalEntity) ReceiptNumber(sequence int64) string {
+	return fmt.Sprintf("%s-%06d", e.ReceiptPrefix, sequence)
+}
+
+// TaxRate formats the tax rate as a percentage, e.g. 8.25%
+func (e *LegalEntity) TaxRate() string {
+	return fmt.Sprintf("%d.%02d%%", e.TaxRateBP/100, e.TaxRateBP%100)
+}
+
+// IncludedTax returns the tax contained in a tax-inclusive amount, rounded to the nearest minor unit
+func (e *LegalEntity) IncludedTax(amount Money) Money {
+	if e.TaxRateBP <= 0 {
+		return 0
+	}
+	divisor := int64(10000 + e.TaxRateBP)
+	return Money((int64(amount)*int64(e.TaxRateBP) + divisor/2) / divisor)
+}
+
+// Receipt records the number issued to a completed booking; it never changes once issued
+type Receipt struct {
+	ID             int64     `json:"id" db:"id"`
+	BookingID      int64     `json:"booking_id" db:"booking_id"`
+	LegalEntityID  int64     `json:"legal_entity_id" db:"legal_entity_id"`
+	SequenceNumber int64     `json:"sequence_number" db:"sequence_number"`
+	Number         string    `json:"number" db:"number"`
+	IssuedAt       time.Time `json:"issued_at" db:"issued_at"`
+}
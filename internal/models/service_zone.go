@@ -0,0 +1,120 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DefaultZoneCode is the code of the fallback service zone
+const DefaultZoneCode = "default"
+
+// ServiceZone holds the booking rules of an operating area
+type ServiceZone struct {
+	ID              int64            `json:"id" db:"id"`
+	Code            string           `json:"code" db:"code"`
+	Name            string           `json:"name" db:"name"`
+	Timezone        string           `json:"timezone" db:"timezone"`
+	MinLeadMinutes  int              `json:"min_lead_minutes" db:"min_lead_minutes"`
+	MaxAdvanceDays  int              `json:"max_advance_days" db:"max_advance_days"`
+	OpensAt         string           `json:"opens_at,omitempty" db:"opens_at"`   // HH:MM, empty for 24 hours
+	ClosesAt        string           `json:"closes_at,omitempty" db:"closes_at"` // HH:MM, may be before OpensAt for overnight hours
+	MatchKeywords   pq.StringArray   `json:"match_keywords" db:"match_keywords"`
+	Boundary        *GeoJSONGeometry `json:"boundary,omitempty" db:"boundary"`
+	BlackoutDates   ZoneBlackouts    `json:"blackout_dates" db:"blackout_dates"`
+	Holidays        ZoneHolidays     `json:"holidays" db:"holidays"`
+	LegalEntityCode *string          `json:"legal_entity_code,omitempty" db:"legal_entity_code"` // Issues receipts for pickups in the zone
+	CreatedAt       time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// Location returns the time zone bookings in this zone are scheduled in
+func (z *ServiceZone) Location() *time.Location {
+	loc, err := time.LoadLocation(z.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// HolidayOn returns the holiday falling on the given YYYY-MM-DD date, if any
+func (z *ServiceZone) HolidayOn(date string) *ZoneHoliday {
+	for i := range z.Holidays {
+		if z.Holidays[i].Date == date {
+			return &z.Holidays[i]
+		}
+	}
+	return nil
+}
+
+// BlackoutOn returns the blackout falling on the given YYYY-MM-DD date, if any
+func (z *ServiceZone) BlackoutOn(date string) *ZoneBlackout {
+	for i := range z.BlackoutDates {
+		if z.BlackoutDates[i].Date == date {
+			return &z.BlackoutDates[i]
+		}
+	}
+	return nil
+}
+
+// ZoneBlackout is a date on which no pickups are accepted
+type ZoneBlackout struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ZoneHoliday is a date on which a surcharge applies
+type ZoneHoliday struct {
+	Date             string `json:"date"`
+	Name             string `json:"name"`
+	SurchargePercent int    `json:"surcharge_percent"`
+}
+
+// ZoneBlackouts is stored as a JSONB array
+type ZoneBlackouts []ZoneBlackout
+
+// Value implements driver.Valuer
+func (b ZoneBlackouts) Value() (driver.Value, error) {
+	if b == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(b)
+}
+
+// Scan implements sql.Scanner
+func (b *ZoneBlackouts) Scan(src interface{}) error {
+	return scanJSON(src, b)
+}
+
+// ZoneHolidays is stored as a JSONB array
+type ZoneHolidays []ZoneHoliday
+
+// Value implements driver.Valuer
+func (h ZoneHolidays) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(h)
+}
+
+// Scan implements sql.Scanner
+func (h *ZoneHolidays) Scan(src interface{}) error {
+	return scanJSON(src, h)
+}
+
+// scanJSON decodes a JSON/JSONB column into dest
+func scanJSON(src interface{}, dest interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return errors.New("unsupported type for JSON column")
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Sources of an SMS opt-out
+const (
+	SMSOptOutSourceReply = "reply" // The recipient texted STOP
+	SMSOptOutSourceAdmin = "admin"
+)
+
+// SMSOptOut is a phone number that no longer receives text messages
+type SMSOptOut struct {
+	Phone     string    `json:"phone" db:"phone"`
+	Source    string    `json:"source" db:"source"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// SMSOptOutRequest opts a phone number out of text messages (admin only)
+type SMSOptOutRequest struct {
+	Phone string `json:"phone"`
+}
+
+// InboundSMS is a text message a recipient sent back, as forwarded by the SMS provider
+type InboundSMS struct {
+	From string `json:"from"`
+	Body string `json:"body"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // Out of attempts
+)
+
+// WebhookEndpoint is a partner URL told about changes to its organization's bookings
+type WebhookEndpoint struct {
+	ID                  int64          `json:"id" db:"id"`
+	OrganizationID      int64          `json:"organization_id" db:"organization_id"`
+	URL                 string         `json:"url" db:"url"`
+	Description         string         `json:"description" db:"description"`
+	EventTypes          pq.StringArray `json:"event_types" db:"event_types"`
+	Secret              string         `json:"-" db:"secret"` // Shown once, when the endpoint is created
+	Active              bool           `json:"active" db:"active"`
+	ConsecutiveFailures int            `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledAt          *time.Time     `json:"disabled_at,omitempty" db:"disabled_at"` // Set when disabled after repeated failures
+	CreatedAt           time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookEndpointRequest registers or replaces a webhook endpoint. Setting active re-enables an
+// endpoint disabled after repeated failures.
+type WebhookEndpointRequest struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// WebhookDelivery is one event queued for, or delivered to, an endpoint
+type WebhookDelivery struct {
+	ID             int64           `json:"id" db:"id"`
+	EndpointID     int64           `json:"endpoint_id" db:"endpoint_id"`
+	EventID        int64           `json:"event_id" db:"event_id"`
+	EventType      string          `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LockedUntil    time.Time       `json:"-" db:"locked_until"`
+	LastStatusCode *int            `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      string          `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+	ReplayOf       *int64          `json:"replay_of,omitempty" db:"replay_of"` // Delivery this one replays
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// DueWebhookDelivery is a delivery claimed for an attempt, with where to send it
+type DueWebhookDelivery struct {
+	WebhookDelivery
+	URL    string `db:"url"`
+	Secret string `db:"secret"`
+}
+
+// WebhookPayload is the body POSTed to an endpoint
+type WebhookPayload struct {
+	ID        int64           `json:"id"` // Booking event ID; increases with every change, so it orders events
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"` // The booking after the change
+}
@@ -0,0 +1,251 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/email"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// ErrEmailDisabled is returned for critical emails when no email service is configured
+var ErrEmailDisabled = errors.New("email service is not configured")
+
+// Dispatcher is the single route every notification takes. It looks up the preferences of each
+// recipient, matched to a user by email address, and sends the event on the channels they allow,
+// in their language. Critical events ignore preferences and quiet hours; texts and pushes that are
+// not urgent are held back during quiet hours. Push and webhook preferences are stored but have
+// no sender yet.
+//
+// Failed notifications are logged and never fail the request that triggered them, except for
+// critical ones, whose errors are returned.
+type Dispatcher struct {
+	preferences  repository.NotificationPreferenceRepository
+	users        repository.UserRepository
+	emailService *email.Service
+	sms          *Service
+	logger       *logger.Logger
+}
+
+func NewDispatcher(preferences repository.NotificationPreferenceRepository, users repository.UserRepository, emailService *email.Service, sms *Service, logger *logger.Logger) *Dispatcher {
+	return &Dispatcher{
+		preferences:  preferences,
+		users:        users,
+		emailService: emailService,
+		sms:          sms,
+		logger:       logger,
+	}
+}
+
+// Emails reports whether an email service is configured
+func (d *Dispatcher) Emails() bool {
+	return d.emailService != nil
+}
+
+// Preferences returns a user's notification preferences, or the defaults if they have not chosen any
+func (d *Dispatcher) Preferences(ctx context.Context, userID int64) (*models.NotificationPreferences, error) {
+	prefs, err := d.preferences.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DefaultNotificationPreferences(userID), nil
+	}
+	return prefs, err
+}
+
+// UpdatePreferences replaces a user's notification preferences
+func (d *Dispatcher) UpdatePreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	return d.preferences.Upsert(ctx, prefs)
+}
+
+// BookingConfirmed texts the rider that a new booking is confirmed
+func (d *Dispatcher) BookingConfirmed(ctx context.Context, booking *models.BookRide) {
+	d.textBooking(ctx, models.NotificationBookingConfirmed, booking, messageData{})
+}
+
+// DriverAssigned texts the rider that a driver accepted the ride
+func (d *Dispatcher) DriverAssigned(ctx context.Context, booking *models.BookRide) {
+	d.textBooking(ctx, models.NotificationDriverAssigned, booking, messageData{DriverName: d.driverName(ctx, booking)})
+}
+
+// DriverArriving texts the rider that the driver is close to the pickup
+func (d *Dispatcher) DriverArriving(ctx context.Context, booking *models.BookRide, etaMinutes *int) {
+	d.textBooking(ctx, models.NotificationDriverArriving, booking, messageData{DriverName: d.driverName(ctx, booking), ETAMinutes: etaMinutes})
+}
+
+// BookingCancelled texts the rider that a booking was cancelled
+func (d *Dispatcher) BookingCancelled(ctx context.Context, booking *models.BookRide) {
+	d.textBooking(ctx, models.NotificationBookingCancelled, booking, messageData{})
+}
+
+// RideReminder emails and texts the rider about an upcoming ride
+func (d *Dispatcher) RideReminder(ctx context.Context, booking *models.BookRide) {
+	driverName := d.driverName(ctx, booking)
+	for _, recipient := range booking.TripUpdateRecipients() {
+		d.sendEmail(ctx, models.NotificationRideReminder, booking.ID, recipient.Email, func() error {
+			return d.emailService.SendRideReminderEmail(recipient.Email, recipient.Name, booking, driverName)
+		})
+	}
+	d.textBooking(ctx, models.NotificationRideReminder, booking, messageData{DriverName: driverName})
+}
+
+// FlightDelay emails the rider and, once assigned, the driver that a flight delay moved the pickup
+func (d *Dispatcher) FlightDelay(ctx context.Context, booking *models.BookRide, previousDate, previousTime string) {
+	recipients := booking.TripUpdateRecipients()
+	if booking.DriverID != nil {
+		if driver, err := d.users.GetByID(ctx, *booking.DriverID); err != nil {
+			d.logger.Err(fmt.Sprintf("Failed to load driver %d of booking %d: %s", *booking.DriverID, booking.ID, err.Error()))
+		} else {
+			recipients = append(recipients, models.Contact{Name: driver.Username, Email: driver.Email})
+		}
+	}
+	for _, recipient := range recipients {
+		d.sendEmail(ctx, models.NotificationFlightDelay, booking.ID, recipient.Email, func() error {
+			return d.emailService.SendFlightDelayEmail(recipient.Email, recipient.Name, booking, previousDate, previousTime)
+		})
+	}
+}
+
+// RideCompleted emails the receipt recipients that the ride is complete
+func (d *Dispatcher) RideCompleted(ctx context.Context, booking *models.BookRide, attachments ...email.Attachment) {
+	for _, recipient := range booking.ReceiptRecipients() {
+		d.sendEmail(ctx, models.NotificationRideCompleted, booking.ID, recipient.Email, func() error {
+			return d.emailService.SendRideCompletedEmail(recipient.Email, booking, attachments...)
+		})
+	}
+}
+
+// AdjustmentReceipt emails the receipt recipients a receipt for a tip or extra charged after the ride
+func (d *Dispatcher) AdjustmentReceipt(ctx context.Context, booking *models.BookRide, item models.LineItem) {
+	for _, recipient := range booking.ReceiptRecipients() {
+		d.sendEmail(ctx, models.NotificationAdjustmentReceipt, booking.ID, recipient.Email, func() error {
+			return d.emailService.SendAdjustmentReceiptEmail(recipient.Email, booking, item)
+		})
+	}
+}
+
+// DriverManifest emails a driver the rides they are assigned on a date
+func (d *Dispatcher) DriverManifest(ctx context.Context, driver *models.User, date string, bookings []*models.BookRide) {
+	d.sendEmail(ctx, models.NotificationDriverManifest, 0, driver.Email, func() error {
+		return d.emailService.SendDriverManifestEmail(driver.Email, driver.Username, date, bookings)
+	})
+}
+
+// Welcome emails a newly registered user
+func (d *Dispatcher) Welcome(ctx context.Context, user *models.User) {
+	d.sendEmail(ctx, models.NotificationWelcome, 0, user.Email, func() error {
+		return d.emailService.SendWelcomeEmail(user.Email, user.Username)
+	})
+}
+
+// BookingLink emails a secure link to manage a booking
+func (d *Dispatcher) BookingLink(ctx context.Context, to, token string, booking *models.BookRide) error {
+	if d.emailService == nil {
+		return ErrEmailDisabled
+	}
+	return d.emailService.SendBookingUpdateEmail(to, token, booking)
+}
+
+// PasswordReset emails a password reset link
+func (d *Dispatcher) PasswordReset(ctx context.Context, to, resetToken string) error {
+	if d.emailService == nil {
+		return ErrEmailDisabled
+	}
+	return d.emailService.SendPasswordResetEmail(to, resetToken)
+}
+
+// UnassignedAlert emails dispatch the bookings no driver has accepted close to pickup
+func (d *Dispatcher) UnassignedAlert(ctx context.Context, to string, bookings []*models.BookRide) error {
+	if d.emailService == nil {
+		return ErrEmailDisabled
+	}
+	return d.emailService.SendUnassignedAlertEmail(to, bookings)
+}
+
+// sendEmail emails one recipient about an event if their preferences allow it. bookingID is zero for
+// events that are not about a booking.
+func (d *Dispatcher) sendEmail(ctx context.Context, eventType string, bookingID int64, to string, send func() error) {
+	if d.emailService == nil {
+		return
+	}
+	if _, ok := d.allowed(ctx, eventType, to, models.ChannelEmail, time.Now()); !ok {
+		d.logger.Info(fmt.Sprintf("Skipped %s email to %s: turned off in preferences", eventType, to))
+		return
+	}
+	if err := send(); err != nil {
+		if bookingID != 0 {
+			d.logger.Err(fmt.Sprintf("Failed to send %s email for booking %d to %s: %s", eventType, bookingID, to, err.Error()))
+		} else {
+			d.logger.Err(fmt.Sprintf("Failed to send %s email to %s: %s", eventType, to, err.Error()))
+		}
+	}
+}
+
+// textBooking texts each SMS recipient of a booking about an event their preferences allow now
+func (d *Dispatcher) textBooking(ctx context.Context, eventType string, booking *models.BookRide, data messageData) {
+	if d.sms == nil {
+		return
+	}
+	data.Booking = booking
+	for _, recipient := range booking.SMSRecipients() {
+		prefs, ok := d.allowed(ctx, eventType, recipient.Email, models.ChannelSMS, time.Now())
+		if !ok {
+			d.logger.Info(fmt.Sprintf("Held back %s SMS for booking %d: turned off or quiet hours", eventType, booking.ID))
+			continue
+		}
+		if err := d.sms.text(ctx, eventType, prefs.Language, recipient, data); err != nil {
+			d.logger.Warn(fmt.Sprintf("Failed to send %s SMS for booking %d: %s", eventType, booking.ID, err.Error()))
+			continue
+		}
+		d.logger.Info(fmt.Sprintf("Sent %s SMS for booking %d via %s", eventType, booking.ID, d.sms.provider.Name()))
+	}
+}
+
+// allowed returns the preferences of a recipient and whether an event may be sent to them on a
+// channel at a time
+func (d *Dispatcher) allowed(ctx context.Context, eventType, recipientEmail, channel string, now time.Time) (*models.NotificationPreferences, bool) {
+	prefs := d.preferencesFor(ctx, recipientEmail)
+	event, _ := models.LookupNotificationEvent(eventType)
+	if event.Critical {
+		return prefs, true
+	}
+	if !prefs.Enabled(eventType, channel) {
+		return prefs, false
+	}
+	quietChannel := channel == models.ChannelSMS || channel == models.ChannelPush
+	if quietChannel && !event.Urgent && prefs.InQuietHours(now) {
+		return prefs, false
+	}
+	return prefs, true
+}
+
+// preferencesFor returns the preferences of the user registered with an email address. Guests,
+// and recipients whose preferences cannot be loaded, get the defaults.
+func (d *Dispatcher) preferencesFor(ctx context.Context, recipientEmail string) *models.NotificationPreferences {
+	if recipientEmail == "" {
+		return models.DefaultNotificationPreferences(0)
+	}
+	prefs, err := d.preferences.GetByEmail(ctx, recipientEmail)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			d.logger.Warn(fmt.Sprintf("Failed to load notification preferences of %s: %s", recipientEmail, err.Error()))
+		}
+		return models.DefaultNotificationPreferences(0)
+	}
+	return prefs
+}
+
+func (d *Dispatcher) driverName(ctx context.Context, booking *models.BookRide) string {
+	if booking.DriverID == nil {
+		return ""
+	}
+	driver, err := d.users.GetByID(ctx, *booking.DriverID)
+	if err != nil {
+		d.logger.Warn(fmt.Sprintf("Failed to load driver %d for booking %d: %s", *booking.DriverID, booking.ID, err.Error()))
+		return ""
+	}
+	return driver.Username
+}
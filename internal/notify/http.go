@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPProviderName identifies the HTTP provider
+const HTTPProviderName = "http"
+
+// HTTPProvider sends messages through an SMS gateway's REST API
+// (POST {apiURL} with {"from": ..., "to": ..., "text": ...} and a bearer API key)
+type HTTPProvider struct {
+	apiURL string
+	apiKey string
+	from   string
+	client *http.Client
+}
+
+func NewHTTPProvider(apiURL, apiKey, from string) *HTTPProvider {
+	return &HTTPProvider{
+		apiURL: apiURL,
+		apiKey: apiKey,
+		from:   from,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type httpMessage struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+func (p *HTTPProvider) Name() string {
+	return HTTPProviderName
+}
+
+func (p *HTTPProvider) Send(ctx context.Context, to, body string) error {
+	payload, err := json.Marshal(httpMessage{From: p.from, To: to, Text: body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("SMS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SMS request failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidPhone is returned for numbers that cannot be written in E.164 form
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// NormalizePhone writes a phone number in E.164 form (+ and up to 15 digits). Spaces, dashes,
+// dots and brackets are ignored. Numbers without a leading + or 00 are national numbers in
+// countryCode: a leading trunk 0 is dropped, and North American numbers must have 10 digits.
+func NormalizePhone(raw, countryCode string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	international := strings.HasPrefix(raw, "+")
+	if international {
+		raw = raw[1:]
+	}
+
+	var digits strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+	number := digits.String()
+
+	if !international && strings.HasPrefix(number, "00") {
+		international = true
+		number = number[2:]
+	}
+	if !international {
+		if countryCode == "1" {
+			if len(number) == 11 && number[0] == '1' {
+				number = number[1:]
+			}
+			if len(number) != 10 {
+				return "", ErrInvalidPhone
+			}
+		} else {
+			number = strings.TrimPrefix(number, "0")
+		}
+		number = countryCode + number
+	}
+
+	if len(number) < 8 || len(number) > 15 || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	if number[0] == '1' && len(number) != 11 {
+		return "", ErrInvalidPhone
+	}
+	return "+" + number, nil
+}
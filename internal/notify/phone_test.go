@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		raw         string
+		countryCode string
+		want        string // empty when the number is invalid
+	}{
+		{"+1 (212) 555-0123", "1", "+12125550123"},
+		{"212-555-0123", "1", "+12125550123"},
+		{"1 212 555 0123", "1", "+12125550123"},
+		{"212.555.0123", "1", "+12125550123"},
+		{"  +12125550123  ", "44", "+12125550123"},
+		{"555-0123", "1", ""},
+		{"+1 212 555 012", "1", ""},
+		{"+44 20 7946 0958", "1", "+442079460958"},
+		{"0044 20 7946 0958", "1", "+442079460958"},
+		{"020 7946 0958", "44", "+442079460958"},
+		{"030 1234567", "49", "+49301234567"},
+		{"+0123456789", "1", ""},
+		{"212-555-0123 ext 4", "1", ""},
+		{"+1234567", "1", ""},
+		{"+1234567890123456", "1", ""},
+		{"", "1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := NormalizePhone(tt.raw, tt.countryCode)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalidPhone) {
+					t.Errorf("NormalizePhone(%q, %q) = %q, %v; want ErrInvalidPhone", tt.raw, tt.countryCode, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizePhone(%q, %q) = %q, %v; want %q", tt.raw, tt.countryCode, got, err, tt.want)
+			}
+		})
+	}
+}
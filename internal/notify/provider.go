@@ -0,0 +1,11 @@
+package notify
+
+import "context"
+
+// SMSProvider delivers text messages
+type SMSProvider interface {
+	// Name identifies the provider in logs
+	Name() string
+	// Send texts body to an E.164 phone number
+	Send(ctx context.Context, to, body string) error
+}
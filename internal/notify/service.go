@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// Keywords recipients reply with to opt out of, or back in to, text messages
+var (
+	optOutKeywords = map[string]bool{"STOP": true, "STOPALL": true, "UNSUBSCRIBE": true, "CANCEL": true, "END": true, "QUIT": true}
+	optInKeywords  = map[string]bool{"START": true, "UNSTOP": true, "YES": true}
+	helpKeywords   = map[string]bool{"HELP": true, "INFO": true}
+)
+
+// Service texts riders about their bookings, as routed by the Dispatcher, and honours opt-outs
+type Service struct {
+	provider      SMSProvider
+	optOuts       repository.SMSOptOutRepository
+	countryCode   string
+	webhookSecret string
+	logger        *logger.Logger
+}
+
+func NewService(provider SMSProvider, optOuts repository.SMSOptOutRepository, countryCode, webhookSecret string, logger *logger.Logger) *Service {
+	return &Service{
+		provider:      provider,
+		optOuts:       optOuts,
+		countryCode:   countryCode,
+		webhookSecret: webhookSecret,
+		logger:        logger,
+	}
+}
+
+// text renders a message about a booking for one recipient and sends it
+func (s *Service) text(ctx context.Context, message, language string, recipient models.Contact, data messageData) error {
+	data.Name = recipient.Name
+	body, err := render(language, message, data)
+	if err != nil {
+		return fmt.Errorf("failed to render message: %w", err)
+	}
+	return s.send(ctx, recipient.Phone, body)
+}
+
+// send texts a phone number unless it has opted out
+func (s *Service) send(ctx context.Context, phone, body string) error {
+	to, err := NormalizePhone(phone, s.countryCode)
+	if err != nil {
+		return fmt.Errorf("%w: %q", err, phone)
+	}
+	optedOut, err := s.optOuts.IsOptedOut(ctx, to)
+	if err != nil {
+		return fmt.Errorf("failed to check opt-out: %w", err)
+	}
+	if optedOut {
+		return fmt.Errorf("%s has opted out", to)
+	}
+	return s.provider.Send(ctx, to, body)
+}
+
+// HandleReply applies a recipient's STOP, START or HELP reply and confirms it. Other replies are ignored.
+func (s *Service) HandleReply(ctx context.Context, reply *models.InboundSMS) error {
+	phone, err := NormalizePhone(reply.From, s.countryCode)
+	if err != nil {
+		return err
+	}
+
+	keyword := strings.ToUpper(strings.TrimSpace(reply.Body))
+	var confirmation string
+	switch {
+	case optOutKeywords[keyword]:
+		if err := s.optOuts.Add(ctx, phone, models.SMSOptOutSourceReply); err != nil {
+			return err
+		}
+		s.logger.Info(fmt.Sprintf("%s opted out of SMS", phone))
+		confirmation = messageOptedOut
+	case optInKeywords[keyword]:
+		if err := s.optOuts.Remove(ctx, phone); err != nil {
+			return err
+		}
+		s.logger.Info(fmt.Sprintf("%s opted back in to SMS", phone))
+		confirmation = messageOptedIn
+	case helpKeywords[keyword]:
+		confirmation = messageHelp
+	default:
+		return nil
+	}
+
+	// Confirmations are sent even to numbers that just opted out
+	body, err := render(models.DefaultLanguage, confirmation, messageData{})
+	if err != nil {
+		return err
+	}
+	if err := s.provider.Send(ctx, phone, body); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to confirm SMS %s to %s: %s", keyword, phone, err.Error()))
+	}
+	return nil
+}
+
+// ListOptOuts returns every opted-out phone number, newest first
+func (s *Service) ListOptOuts(ctx context.Context) ([]*models.SMSOptOut, error) {
+	return s.optOuts.List(ctx)
+}
+
+// OptOut stops text messages to a phone number
+func (s *Service) OptOut(ctx context.Context, phone, source string) (string, error) {
+	normalized, err := NormalizePhone(phone, s.countryCode)
+	if err != nil {
+		return "", err
+	}
+	return normalized, s.optOuts.Add(ctx, normalized, source)
+}
+
+// OptIn resumes text messages to a phone number
+func (s *Service) OptIn(ctx context.Context, phone string) (string, error) {
+	normalized, err := NormalizePhone(phone, s.countryCode)
+	if err != nil {
+		return "", err
+	}
+	return normalized, s.optOuts.Remove(ctx, normalized)
+}
+
+// VerifySignature checks the hex HMAC-SHA256 signature of an inbound SMS webhook payload
+func (s *Service) VerifySignature(payload []byte, signature string) bool {
+	if s.webhookSecret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
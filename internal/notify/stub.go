@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// StubProviderName identifies the stub provider
+const StubProviderName = "stub"
+
+// Message is a text message recorded by the stub provider
+type Message struct {
+	To     string    `json:"to"`
+	Body   string    `json:"body"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// StubProvider keeps sent messages in memory for local development and tests, and
+// appends them to a JSON lines file when one is configured. Nothing is delivered.
+type StubProvider struct {
+	path string
+
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewStubProvider records messages in memory, and also in the file at path unless it is empty
+func NewStubProvider(path string) *StubProvider {
+	return &StubProvider{path: path}
+}
+
+func (p *StubProvider) Name() string {
+	return StubProviderName
+}
+
+func (p *StubProvider) Send(ctx context.Context, to, body string) error {
+	msg := Message{To: to, Body: body, SentAt: time.Now().UTC()}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, msg)
+	if p.path == "" {
+		return nil
+	}
+
+	line, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open SMS stub file: %w", err)
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Messages returns the messages sent so far, oldest first
+func (p *StubProvider) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.messages...)
+}
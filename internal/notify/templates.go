@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"strings"
+	"text/template"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// Replies to STOP, START and HELP. Messages about a booking are named after their notification event.
+const (
+	messageOptedOut = "opted_out"
+	messageOptedIn  = "opted_in"
+	messageHelp     = "help"
+)
+
+// messageData is what message templates are rendered with
+type messageData struct {
+	Name       string // Recipient's name
+	Booking    *models.BookRide
+	DriverName string
+	ETAMinutes *int
+}
+
+// Messages stay short so most fit in a single SMS segment. Each language defines the messages
+// translated into it; the rest are sent in the default language.
+var templates = map[string]*template.Template{
+	models.LanguageEnglish: template.Must(template.New("sms").Parse(`
+{{define "booking_confirmed"}}LuxSUV: Hi {{.Name}}, booking #{{.Booking.ID}} is confirmed for {{.Booking.Date}} at {{.Booking.Time}}, pickup at {{.Booking.PickupLocation}}. Reply STOP to opt out.{{end}}
+{{define "driver_assigned"}}LuxSUV: {{if .DriverName}}{{.DriverName}} is{{else}}A driver has been{{end}} assigned to your ride #{{.Booking.ID}} on {{.Booking.Date}} at {{.Booking.Time}}.{{end}}
+{{define "driver_arriving"}}LuxSUV: Your driver{{if .DriverName}} {{.DriverName}}{{end}} is arriving at {{.Booking.PickupLocation}}{{if .ETAMinutes}} in about {{.ETAMinutes}} min{{end}}.{{end}}
+{{define "booking_cancelled"}}LuxSUV: Booking #{{.Booking.ID}} on {{.Booking.Date}} at {{.Booking.Time}} has been cancelled.{{end}}
+{{define "ride_reminder"}}LuxSUV: Reminder: your ride #{{.Booking.ID}} is on {{.Booking.Date}} at {{.Booking.Time}} from {{.Booking.PickupLocation}}{{if .DriverName}} with {{.DriverName}}{{end}}.{{end}}
+{{define "opted_out"}}LuxSUV: You will no longer receive text messages from us. Reply START to opt back in.{{end}}
+{{define "opted_in"}}LuxSUV: You will receive text messages about your rides again. Reply STOP to opt out.{{end}}
+{{define "help"}}LuxSUV ride notifications. Reply STOP to opt out, START to opt back in.{{end}}
+`)),
+	models.LanguageSpanish: template.Must(template.New("sms").Parse(`
+{{define "booking_confirmed"}}LuxSUV: Hola {{.Name}}, la reserva #{{.Booking.ID}} está confirmada para el {{.Booking.Date}} a las {{.Booking.Time}}, recogida en {{.Booking.PickupLocation}}. Responde STOP para darte de baja.{{end}}
+{{define "driver_assigned"}}LuxSUV: {{if .DriverName}}{{.DriverName}} será tu conductor{{else}}Ya tienes conductor{{end}} para el viaje #{{.Booking.ID}} del {{.Booking.Date}} a las {{.Booking.Time}}.{{end}}
+{{define "driver_arriving"}}LuxSUV: Tu conductor{{if .DriverName}} {{.DriverName}}{{end}} está llegando a {{.Booking.PickupLocation}}{{if .ETAMinutes}} en unos {{.ETAMinutes}} min{{end}}.{{end}}
+{{define "booking_cancelled"}}LuxSUV: La reserva #{{.Booking.ID}} del {{.Booking.Date}} a las {{.Booking.Time}} ha sido cancelada.{{end}}
+{{define "ride_reminder"}}LuxSUV: Recordatorio: tu viaje #{{.Booking.ID}} es el {{.Booking.Date}} a las {{.Booking.Time}} desde {{.Booking.PickupLocation}}{{if .DriverName}} con {{.DriverName}}{{end}}.{{end}}
+`)),
+}
+
+// render renders a message in a language, falling back to the default language
+func render(language, name string, data messageData) (string, error) {
+	tmpl, ok := templates[language]
+	if !ok || tmpl.Lookup(name) == nil {
+		tmpl = templates[models.DefaultLanguage]
+	}
+	var b strings.Builder
+	if err := tmpl.ExecuteTemplate(&b, name, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
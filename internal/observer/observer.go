@@ -1 +1,281 @@
 package observer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/lib/pq"
+)
+
+const (
+	// subscriptionBuffer is how many events a slow client may fall behind before it is disconnected
+	subscriptionBuffer = 64
+	// ReplayBatch is how many events Replay returns at a time
+	ReplayBatch = 500
+	// fetchBatch is how many new events are loaded at a time after a notification
+	fetchBatch = 500
+	// pollInterval is how often the hub checks for events even without a notification, and pings the listener
+	pollInterval = 30 * time.Second
+	// cleanupInterval is how often events past their retention are deleted
+	cleanupInterval = time.Hour
+	// gapWait is how long a missing event ID is looked for. IDs are taken when an event is inserted but
+	// only become visible when its transaction commits, so a lower ID may appear after a higher one,
+	// or never if its transaction rolled back.
+	gapWait = time.Minute
+)
+
+// Scope is what a subscriber may see: their own bookings as a rider, their assignments as a driver,
+// or every booking as a dispatcher
+type Scope struct {
+	UserID     int64
+	Driver     bool
+	Dispatcher bool
+}
+
+// Allows reports whether an event is visible within the scope
+func (s Scope) Allows(event *models.BookingEvent) bool {
+	switch {
+	case s.Dispatcher:
+		return true
+	case s.Driver:
+		return event.DriverID != nil && *event.DriverID == s.UserID
+	default:
+		return event.UserID != nil && *event.UserID == s.UserID
+	}
+}
+
+// filter returns the repository filter matching the scope
+func (s Scope) filter() (userID, driverID *int64) {
+	switch {
+	case s.Dispatcher:
+		return nil, nil
+	case s.Driver:
+		return nil, &s.UserID
+	default:
+		return &s.UserID, nil
+	}
+}
+
+// Subscription receives the live events of one connected client
+type Subscription struct {
+	scope  Scope
+	events chan *models.BookingEvent
+}
+
+// Events returns the live events of the subscription. The channel is closed when the client falls
+// too far behind; it should reconnect and replay from its last event.
+func (s *Subscription) Events() <-chan *models.BookingEvent {
+	return s.events
+}
+
+// Sink receives every stored booking event, e.g. to queue it for delivery outside the hub
+type Sink interface {
+	Enqueue(ctx context.Context, event *models.BookingEvent, booking *models.BookRide) error
+}
+
+// Hub stores booking events and fans them out to the clients connected to this server instance.
+// Events are announced over Postgres LISTEN/NOTIFY, so clients see changes made on any instance.
+type Hub struct {
+	repo        repository.BookingEventRepository
+	databaseURL string
+	retention   time.Duration
+	logger      *logger.Logger
+	sinks       []Sink
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	lastID      int64               // Newest event delivered; only used by Run
+	gaps        map[int64]time.Time // IDs below lastID not seen yet, and when they were first missed; only used by Run
+}
+
+func NewHub(repo repository.BookingEventRepository, databaseURL string, retention time.Duration, logger *logger.Logger) *Hub {
+	return &Hub{
+		repo:        repo,
+		databaseURL: databaseURL,
+		retention:   retention,
+		logger:      logger,
+		subscribers: make(map[*Subscription]struct{}),
+		gaps:        make(map[int64]time.Time),
+	}
+}
+
+// AddSink passes every event published from now on to a sink. Sinks are added at startup.
+func (h *Hub) AddSink(sink Sink) {
+	h.sinks = append(h.sinks, sink)
+}
+
+// Publish records an event for a booking and passes it to the sinks. Failures are logged rather
+// than returned, so a booking change never fails because its event could not be stored.
+func (h *Hub) Publish(ctx context.Context, eventType string, booking *models.BookRide) {
+	data, err := json.Marshal(booking)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to encode %s event for booking %d: %s", eventType, booking.ID, err.Error()))
+		return
+	}
+	event := &models.BookingEvent{
+		BookingID:  booking.ID,
+		Type:       eventType,
+		UserID:     booking.UserID,
+		DriverID:   booking.DriverID,
+		BookStatus: booking.BookStatus,
+		RideStatus: booking.RideStatus,
+		Booking:    data,
+	}
+	ctx = context.WithoutCancel(ctx)
+	if err := h.repo.Create(ctx, event); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to store %s event for booking %d: %s", eventType, booking.ID, err.Error()))
+		return
+	}
+	for _, sink := range h.sinks {
+		if err := sink.Enqueue(ctx, event, booking); err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to queue %s event %d of booking %d: %s", eventType, event.ID, booking.ID, err.Error()))
+		}
+	}
+}
+
+// Subscribe registers a client for live events within its scope
+func (h *Hub) Subscribe(scope Scope) *Subscription {
+	sub := &Subscription{scope: scope, events: make(chan *models.BookingEvent, subscriptionBuffer)}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Unsubscribe stops delivering events to a client
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Replay returns up to ReplayBatch stored events within a scope after the client's last event ID, oldest first
+func (h *Hub) Replay(ctx context.Context, scope Scope, afterID int64) ([]*models.BookingEvent, error) {
+	userID, driverID := scope.filter()
+	return h.repo.GetSince(ctx, afterID, userID, driverID, ReplayBatch)
+}
+
+// Run listens for new events and delivers them to subscribers until the context is cancelled.
+// It also deletes events past their retention.
+func (h *Hub) Run(ctx context.Context) {
+	listener := pq.NewListener(h.databaseURL, 10*time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			h.logger.Warn(fmt.Sprintf("Booking event listener: %s", err.Error()))
+		}
+	})
+	defer listener.Close()
+	if err := listener.Listen(models.BookingEventChannel); err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to listen for booking events: %s", err.Error()))
+		return
+	}
+
+	lastID, err := h.repo.GetLatestID(ctx)
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to load latest booking event: %s", err.Error()))
+	}
+	h.lastID = lastID
+
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(cleanupInterval)
+	defer cleanup.Stop()
+
+	h.cleanup(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-listener.Notify:
+			// A nil notification means the connection was re-established; catching up covers both
+			h.dispatch(ctx)
+		case <-poll.C:
+			if err := listener.Ping(); err != nil {
+				h.logger.Warn(fmt.Sprintf("Booking event listener ping failed: %s", err.Error()))
+			}
+			h.dispatch(ctx)
+		case <-cleanup.C:
+			h.cleanup(ctx)
+		}
+	}
+}
+
+// dispatch loads the events stored since the last delivery and sends each to the subscribers that may see it.
+// Reading resumes from the oldest ID still missing, so an event committed after a newer one is delivered
+// too; events delivered before are skipped.
+func (h *Hub) dispatch(ctx context.Context) {
+	now := time.Now()
+	from := h.lastID
+	for id, missedAt := range h.gaps {
+		if now.Sub(missedAt) > gapWait {
+			delete(h.gaps, id)
+			continue
+		}
+		from = min(from, id-1)
+	}
+
+	for {
+		events, err := h.repo.GetSince(ctx, from, nil, nil, fetchBatch)
+		if err != nil {
+			h.logger.Err(fmt.Sprintf("Failed to load booking events after %d: %s", from, err.Error()))
+			return
+		}
+		for _, event := range events {
+			from = event.ID
+			if event.ID <= h.lastID {
+				if _, missing := h.gaps[event.ID]; !missing {
+					continue
+				}
+				delete(h.gaps, event.ID)
+			} else {
+				// Track at most a batch of skipped IDs; a larger jump is taken as rolled back inserts
+				for id := max(h.lastID+1, event.ID-fetchBatch); id < event.ID; id++ {
+					h.gaps[id] = now
+				}
+				h.lastID = event.ID
+			}
+			h.deliver(event)
+		}
+		if len(events) < fetchBatch {
+			return
+		}
+	}
+}
+
+// deliver sends an event to every subscriber in scope, disconnecting those that have fallen behind
+func (h *Hub) deliver(event *models.BookingEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		if !sub.scope.Allows(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(h.subscribers, sub)
+			close(sub.events)
+			h.logger.Warn(fmt.Sprintf("Disconnected event subscriber %d that fell behind", sub.scope.UserID))
+		}
+	}
+}
+
+// cleanup deletes events older than the retention period
+func (h *Hub) cleanup(ctx context.Context) {
+	deleted, err := h.repo.DeleteBefore(ctx, time.Now().Add(-h.retention))
+	if err != nil {
+		h.logger.Err(fmt.Sprintf("Failed to delete expired booking events: %s", err.Error()))
+		return
+	}
+	if deleted > 0 {
+		h.logger.Info(fmt.Sprintf("Deleted %d expired booking events", deleted))
+	}
+}
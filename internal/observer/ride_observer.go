@@ -1 +1,118 @@
 package observer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// rideObserver publishes an event after every successful change to a booking
+type rideObserver struct {
+	repository.BookRideRepository
+	hub *Hub
+}
+
+// ObserveRides wraps a booking repository so every lifecycle change is published to the hub
+func ObserveRides(repo repository.BookRideRepository, hub *Hub) repository.BookRideRepository {
+	return &rideObserver{BookRideRepository: repo, hub: hub}
+}
+
+func (r *rideObserver) Create(ctx context.Context, br *models.BookRide) error {
+	if err := r.BookRideRepository.Create(ctx, br); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventCreated, br.ID)
+	return nil
+}
+
+func (r *rideObserver) Accept(ctx context.Context, id int64, driverID int64) error {
+	if err := r.BookRideRepository.Accept(ctx, id, driverID); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventAccepted, id)
+	return nil
+}
+
+func (r *rideObserver) Update(ctx context.Context, id int64, updates *models.UpdateBookRideRequest) error {
+	if err := r.BookRideRepository.Update(ctx, id, updates); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventUpdated, id)
+	return nil
+}
+
+func (r *rideObserver) UpdateQuote(ctx context.Context, id int64, items models.LineItems) error {
+	if err := r.BookRideRepository.UpdateQuote(ctx, id, items); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventUpdated, id)
+	return nil
+}
+
+func (r *rideObserver) UpdateFlight(ctx context.Context, id int64, flight *models.FlightDetails, date, time string) error {
+	if err := r.BookRideRepository.UpdateFlight(ctx, id, flight, date, time); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventUpdated, id)
+	return nil
+}
+
+func (r *rideObserver) AdjustFare(ctx context.Context, adj *models.FareAdjustment) error {
+	if err := r.BookRideRepository.AdjustFare(ctx, adj); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventUpdated, adj.BookingID)
+	return nil
+}
+
+func (r *rideObserver) Approve(ctx context.Context, id int64, adminID int64) error {
+	if err := r.BookRideRepository.Approve(ctx, id, adminID); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventApproved, id)
+	return nil
+}
+
+func (r *rideObserver) Cancel(ctx context.Context, id int64, reason string) error {
+	if err := r.BookRideRepository.Cancel(ctx, id, reason); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventCancelled, id)
+	return nil
+}
+
+func (r *rideObserver) CancelWithFee(ctx context.Context, id int64, reason string, fee models.Money) error {
+	if err := r.BookRideRepository.CancelWithFee(ctx, id, reason, fee); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventCancelled, id)
+	return nil
+}
+
+func (r *rideObserver) Start(ctx context.Context, id int64, driverID int64) error {
+	if err := r.BookRideRepository.Start(ctx, id, driverID); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventStarted, id)
+	return nil
+}
+
+func (r *rideObserver) Complete(ctx context.Context, id int64, driverID int64, finalItems models.LineItems, actualMinutes *int) error {
+	if err := r.BookRideRepository.Complete(ctx, id, driverID, finalItems, actualMinutes); err != nil {
+		return err
+	}
+	r.publish(ctx, models.BookingEventCompleted, id)
+	return nil
+}
+
+// publish reloads the booking so the event carries its state after the change
+func (r *rideObserver) publish(ctx context.Context, eventType string, id int64) {
+	booking, err := r.BookRideRepository.GetByID(context.WithoutCancel(ctx), id)
+	if err != nil {
+		r.hub.logger.Err(fmt.Sprintf("Failed to load booking %d for %s event: %s", id, eventType, err.Error()))
+		return
+	}
+	r.hub.Publish(ctx, eventType, booking)
+}
@@ -0,0 +1,224 @@
+package organizations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+var (
+	// ErrNotMember is returned when a rider books for an organization they do not belong to
+	ErrNotMember = errors.New("you are not a member of this organization")
+	// ErrInvalidBooking wraps the reasons a booking cannot be billed to its organization
+	ErrInvalidBooking = errors.New("invalid organization booking")
+	// ErrInvoiceExists is returned when the invoice of a month has already been issued
+	ErrInvoiceExists = errors.New("invoice already issued for this month")
+	// ErrNothingToInvoice is returned when an organization has no billable bookings for a month
+	ErrNothingToInvoice = errors.New("no billable bookings to invoice")
+)
+
+// Service attributes bookings to corporate accounts and issues their monthly invoices
+type Service struct {
+	repo   repository.OrganizationRepository
+	logger *logger.Logger
+}
+
+func NewService(repo repository.OrganizationRepository, logger *logger.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Attribute bills a booking to the organization it names. The rider must be a member; the booking is charged
+// to the requested cost center or the member's default, and waits for an org admin when it is quoted above
+// the organization's approval threshold. Org admins' own bookings never need approval.
+func (s *Service) Attribute(ctx context.Context, br *models.BookRide, userID *int64) error {
+	br.CostCenterID = nil
+	br.OrgApprovalStatus = models.OrgApprovalNotRequired
+	br.OrgApprovedBy = nil
+	br.OrgApprovedAt = nil
+	if br.OrganizationID == nil {
+		br.CostCenter = ""
+		return nil
+	}
+	if userID == nil {
+		return invalid("sign in to book on an organization account")
+	}
+
+	member, err := s.repo.GetMemberByUserID(ctx, *userID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && member.OrganizationID != *br.OrganizationID) {
+		return ErrNotMember
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load membership: %w", err)
+	}
+	org, err := s.repo.GetByID(ctx, member.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("failed to load organization: %w", err)
+	}
+	if !org.Active {
+		return invalid("this organization account is inactive")
+	}
+
+	if code := strings.TrimSpace(br.CostCenter); code != "" {
+		costCenter, err := s.repo.GetCostCenterByCode(ctx, org.ID, code)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && !costCenter.Active) {
+			return invalid("unknown cost center " + code)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load cost center: %w", err)
+		}
+		br.CostCenterID = &costCenter.ID
+	} else {
+		br.CostCenterID = member.DefaultCostCenterID
+	}
+	if br.CostCenterID == nil && org.RequireCostCenter {
+		return invalid("a cost center is required for this organization")
+	}
+	br.CostCenter = ""
+
+	if org.NeedsApproval(br.QuotedAmount) && !member.IsOrgAdmin() {
+		br.OrgApprovalStatus = models.OrgApprovalPending
+		s.logger.Info(fmt.Sprintf("Booking for %s needs approval from organization %d", br.Email, org.ID))
+	}
+	return nil
+}
+
+// invalid wraps a reason the booking cannot be billed to its organization
+func invalid(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidBooking, reason)
+}
+
+// RecheckApproval sends a re-quoted booking back for approval when its new fare is above the threshold
+func (s *Service) RecheckApproval(ctx context.Context, booking *models.BookRide) error {
+	if booking.OrganizationID == nil || booking.OrgApprovalStatus == models.OrgApprovalPending {
+		return nil
+	}
+	org, err := s.repo.GetByID(ctx, *booking.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if !org.NeedsApproval(booking.QuotedAmount) || booking.UserID == nil {
+		return nil
+	}
+	if member, err := s.repo.GetMemberByUserID(ctx, *booking.UserID); err == nil && member.OrganizationID == org.ID && member.IsOrgAdmin() {
+		return nil
+	}
+	if err := s.repo.RequireBookingApproval(ctx, booking.ID); err != nil {
+		return err
+	}
+	booking.OrgApprovalStatus = models.OrgApprovalPending
+	booking.OrgApprovedBy = nil
+	booking.OrgApprovedAt = nil
+	return nil
+}
+
+// MonthStart returns the first day of the month containing t, in UTC
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// InvoiceLastMonth issues last month's invoices that have not been issued yet; run as a scheduled job
+func (s *Service) InvoiceLastMonth(ctx context.Context) error {
+	return s.InvoiceAll(ctx, MonthStart(time.Now()).AddDate(0, -1, 0))
+}
+
+// InvoiceAll issues the invoice of the given month for every active organization that has billable bookings
+func (s *Service) InvoiceAll(ctx context.Context, month time.Time) error {
+	orgs, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list organizations for invoicing: %w", err)
+	}
+	for _, org := range orgs {
+		_, err := s.Invoice(ctx, org, month)
+		if err != nil && !errors.Is(err, ErrInvoiceExists) && !errors.Is(err, ErrNothingToInvoice) {
+			s.logger.Err(fmt.Sprintf("Failed to invoice organization %d for %s: %s", org.ID, month.Format("2006-01"), err.Error()))
+		}
+	}
+	return nil
+}
+
+// Invoice issues the consolidated invoice of an organization for the month starting at month. It bills
+// every completed booking, and every cancellation fee, with a pickup date before the end of the month
+// that has not been invoiced yet, so rides completed late are billed with the next invoice.
+func (s *Service) Invoice(ctx context.Context, org *models.Organization, month time.Time) (*models.OrganizationInvoice, error) {
+	periodEnd := month.AddDate(0, 1, 0)
+	if periodEnd.After(time.Now()) {
+		return nil, errors.New("invoices can only be issued for months that have ended")
+	}
+
+	bookings, err := s.repo.GetUninvoicedBookings(ctx, org.ID, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bookings: %w", err)
+	}
+	if len(bookings) == 0 {
+		return nil, ErrNothingToInvoice
+	}
+	costCenters, err := s.repo.GetCostCenters(ctx, org.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cost centers: %w", err)
+	}
+	codes := make(map[int64]string, len(costCenters))
+	for _, costCenter := range costCenters {
+		codes[costCenter.ID] = costCenter.Code
+	}
+
+	invoice := &models.OrganizationInvoice{
+		OrganizationID: org.ID,
+		Number:         fmt.Sprintf("ORG%d-%s", org.ID, month.Format("200601")),
+		PeriodStart:    month,
+		PeriodEnd:      periodEnd,
+		Currency:       org.Currency,
+	}
+	for _, booking := range bookings {
+		line := &models.OrganizationInvoiceLine{
+			BookingID: booking.ID,
+			RiderName: booking.Passenger().Name,
+			RideDate:  booking.Date,
+		}
+		if booking.CostCenterID != nil {
+			line.CostCenterCode = codes[*booking.CostCenterID]
+		}
+		if booking.BookStatus == models.BookStatusCancelled {
+			line.Description = fmt.Sprintf("Cancellation fee, %s from %s", booking.RideType, booking.PickupLocation)
+			line.Amount = *booking.CancellationFee
+		} else {
+			line.Description = describeRide(booking)
+			line.Amount = booking.QuotedAmount
+			if booking.FinalAmount != nil {
+				line.Amount = *booking.FinalAmount
+			}
+		}
+		invoice.Lines = append(invoice.Lines, line)
+		invoice.TotalAmount += line.Amount
+	}
+	invoice.BookingCount = len(invoice.Lines)
+
+	if err := s.repo.CreateInvoice(ctx, invoice); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvoiceExists
+		}
+		return nil, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Invoice %s issued to organization %d: %d bookings totalling %s",
+		invoice.Number, org.ID, invoice.BookingCount, invoice.TotalAmount.Format(invoice.Currency)))
+	return invoice, nil
+}
+
+// describeRide summarizes the route of a booking for an invoice line
+func describeRide(booking *models.BookRide) string {
+	if booking.IsHourly() {
+		return fmt.Sprintf("%s hourly charter from %s", booking.RideType, booking.PickupLocation)
+	}
+	return fmt.Sprintf("%s from %s to %s", booking.RideType, booking.PickupLocation, booking.DropoffLocation)
+}
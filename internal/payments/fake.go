@@ -0,0 +1,179 @@
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// FakeProviderName is the provider name recorded for payments made through FakeGateway
+const FakeProviderName = "fake"
+
+// Payment methods with special behaviour in FakeGateway; any other value succeeds
+const (
+	FakeMethodDeclined          = "pm_card_declined"
+	FakeMethodInsufficientFunds = "pm_insufficient_funds"
+)
+
+// fakeOvercapturePercent is how far above the hold a capture may go, as with most card networks
+const fakeOvercapturePercent = 20
+
+// FakeGateway is an in-process gateway for tests and local development. Outcomes depend only on
+// the inputs and on the stored payment, which stands in for the provider's record of the intent,
+// so holds survive restarts. Events are held until the service has recorded the payment they
+// refer to, then delivered to the registered event handler as a webhook would be.
+type FakeGateway struct {
+	repo repository.PaymentRepository
+
+	mu       sync.Mutex
+	started  time.Time
+	eventSeq int64
+	pending  []Event
+	handler  EventHandler
+}
+
+func NewFakeGateway(repo repository.PaymentRepository) *FakeGateway {
+	return &FakeGateway{repo: repo, started: time.Now()}
+}
+
+// SetEventHandler registers the callback that receives webhook-style events
+func (g *FakeGateway) SetEventHandler(handler EventHandler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.handler = handler
+}
+
+func (g *FakeGateway) Name() string {
+	return FakeProviderName
+}
+
+func (g *FakeGateway) Authorize(ctx context.Context, req *AuthorizeRequest) (*Result, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("authorization amount must be positive")
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate payment reference: %w", err)
+	}
+	ref := "fake_pi_" + hex.EncodeToString(suffix)
+
+	var failure string
+	switch req.PaymentMethod {
+	case FakeMethodDeclined:
+		failure = "card declined"
+	case FakeMethodInsufficientFunds:
+		failure = "insufficient funds"
+	}
+
+	if failure != "" {
+		g.queue(Event{Type: EventFailed, ProviderRef: ref, FailureReason: failure})
+		return &Result{ProviderRef: ref, Status: models.PaymentStatusFailed, FailureReason: failure}, ErrDeclined
+	}
+
+	g.queue(Event{Type: EventAuthorized, ProviderRef: ref, Amount: req.Amount})
+	return &Result{ProviderRef: ref, Status: models.PaymentStatusAuthorized, Amount: req.Amount}, nil
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, providerRef string, amount models.Money) (*Result, error) {
+	intent, err := g.intent(ctx, providerRef)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != models.PaymentStatusAuthorized {
+		return nil, fmt.Errorf("cannot capture payment in status %s", intent.Status)
+	}
+	if amount < 0 || amount > intent.AuthorizedAmount+intent.AuthorizedAmount.Percent(fakeOvercapturePercent) {
+		return nil, fmt.Errorf("capture amount exceeds authorized amount: %w", ErrDeclined)
+	}
+
+	g.queue(Event{Type: EventCaptured, ProviderRef: providerRef, Amount: amount})
+	return &Result{ProviderRef: providerRef, Status: models.PaymentStatusCaptured, Amount: amount}, nil
+}
+
+func (g *FakeGateway) Void(ctx context.Context, providerRef string) (*Result, error) {
+	intent, err := g.intent(ctx, providerRef)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != models.PaymentStatusAuthorized {
+		return nil, fmt.Errorf("cannot void payment in status %s", intent.Status)
+	}
+
+	g.queue(Event{Type: EventVoided, ProviderRef: providerRef})
+	return &Result{ProviderRef: providerRef, Status: models.PaymentStatusVoided}, nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, providerRef string, amount models.Money) (*Result, error) {
+	intent, err := g.intent(ctx, providerRef)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != models.PaymentStatusCaptured && intent.Status != models.PaymentStatusPartiallyRefunded {
+		return nil, fmt.Errorf("cannot refund payment in status %s", intent.Status)
+	}
+	if amount <= 0 || intent.RefundedAmount+amount > intent.CapturedAmount {
+		return nil, errors.New("refund amount exceeds captured amount")
+	}
+
+	refunded := intent.RefundedAmount + amount
+	status := models.PaymentStatusPartiallyRefunded
+	if refunded == intent.CapturedAmount {
+		status = models.PaymentStatusRefunded
+	}
+
+	g.queue(Event{Type: EventRefunded, ProviderRef: providerRef, Amount: refunded})
+	return &Result{ProviderRef: providerRef, Status: status, Amount: refunded}, nil
+}
+
+// intent returns the stored payment of a reference
+func (g *FakeGateway) intent(ctx context.Context, providerRef string) (*models.Payment, error) {
+	payment, err := g.repo.GetByProviderRef(ctx, FakeProviderName, providerRef)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("unknown payment %s", providerRef)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// queue holds an event until the next Flush
+func (g *FakeGateway) queue(event Event) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// Event IDs must stay unique across restarts, since handled IDs are stored to skip redeliveries
+	g.eventSeq++
+	event.ID = fmt.Sprintf("fake_evt_%d_%06d", g.started.UnixNano(), g.eventSeq)
+	event.Provider = FakeProviderName
+	event.CreatedAt = time.Now().UTC()
+	g.pending = append(g.pending, event)
+}
+
+// Flush delivers the held events to the registered handler, in the order they happened
+func (g *FakeGateway) Flush(ctx context.Context) error {
+	g.mu.Lock()
+	events := g.pending
+	g.pending = nil
+	handler := g.handler
+	g.mu.Unlock()
+
+	if handler == nil {
+		return nil
+	}
+	var errs []error
+	for _, event := range events {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("event %s (%s): %w", event.ID, event.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
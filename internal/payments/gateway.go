@@ -0,0 +1,67 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// ErrDeclined is returned when the provider refuses an operation
+var ErrDeclined = errors.New("payment declined")
+
+// Gateway is implemented by every payment provider adapter
+type Gateway interface {
+	// Name identifies the provider; it is stored on each payment row
+	Name() string
+	// Authorize places a hold on the payment method without moving money
+	Authorize(ctx context.Context, req *AuthorizeRequest) (*Result, error)
+	// Capture settles a previously authorized hold
+	Capture(ctx context.Context, providerRef string, amount models.Money) (*Result, error)
+	// Void releases an uncaptured hold
+	Void(ctx context.Context, providerRef string) (*Result, error)
+	// Refund returns money from a captured payment
+	Refund(ctx context.Context, providerRef string, amount models.Money) (*Result, error)
+}
+
+// AuthorizeRequest describes a hold to place for a booking
+type AuthorizeRequest struct {
+	BookingID     int64
+	Amount        models.Money
+	Currency      string
+	PaymentMethod string
+}
+
+// Result is the provider's answer to a gateway call.
+// Amount is the cumulative amount for the operation type (e.g. total refunded so far).
+type Result struct {
+	ProviderRef   string
+	Status        string
+	Amount        models.Money
+	FailureReason string
+}
+
+// Webhook-style event types sent by providers
+const (
+	EventAuthorized = "payment.authorized"
+	EventCaptured   = "payment.captured"
+	EventVoided     = "payment.voided"
+	EventRefunded   = "payment.refunded"
+	EventFailed     = "payment.failed"
+)
+
+// Event is a provider callback describing a payment state change.
+// ID is unique per provider; CreatedAt, when the provider sends it, orders late deliveries.
+type Event struct {
+	ID            string       `json:"id"`
+	Type          string       `json:"type"`
+	Provider      string       `json:"provider"`
+	ProviderRef   string       `json:"provider_ref"`
+	Amount        models.Money `json:"amount"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time    `json:"created_at,omitempty"`
+}
+
+// EventHandler receives provider events
+type EventHandler func(ctx context.Context, event Event) error
@@ -0,0 +1,406 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// ErrUnknownPayment is returned for events that reference a payment we never recorded
+var ErrUnknownPayment = errors.New("unknown payment")
+
+// eventFlusher is implemented by in-process gateways, such as FakeGateway, that hold their events
+// until the service has recorded the payment they refer to
+type eventFlusher interface {
+	Flush(ctx context.Context) error
+}
+
+// Service ties the booking lifecycle to a payment gateway and the payments table
+type Service struct {
+	gateway       Gateway
+	repo          repository.PaymentRepository
+	webhookSecret string
+	logger        *logger.Logger
+}
+
+func NewService(gateway Gateway, repo repository.PaymentRepository, webhookSecret string, logger *logger.Logger) *Service {
+	return &Service{
+		gateway:       gateway,
+		repo:          repo,
+		webhookSecret: webhookSecret,
+		logger:        logger,
+	}
+}
+
+// AuthorizeBooking places a hold for the quoted fare of a booking
+func (s *Service) AuthorizeBooking(ctx context.Context, booking *models.BookRide, paymentMethod string) (*models.Payment, error) {
+	defer s.flushEvents(ctx)
+
+	result, err := s.gateway.Authorize(ctx, &AuthorizeRequest{
+		BookingID:     booking.ID,
+		Amount:        booking.QuotedAmount,
+		Currency:      booking.Currency,
+		PaymentMethod: paymentMethod,
+	})
+	if err != nil && result == nil {
+		return nil, fmt.Errorf("failed to authorize payment: %w", err)
+	}
+
+	payment := &models.Payment{
+		BookingID:     booking.ID,
+		Provider:      s.gateway.Name(),
+		ProviderRef:   result.ProviderRef,
+		Purpose:       models.PaymentPurposeFare,
+		PaymentMethod: paymentMethod,
+		Status:        result.Status,
+		Currency:      booking.Currency,
+	}
+	if result.Status == models.PaymentStatusAuthorized {
+		payment.AuthorizedAmount = result.Amount
+	}
+	payment.FailureReason = result.FailureReason
+
+	if createErr := s.repo.Create(ctx, payment); createErr != nil {
+		return nil, fmt.Errorf("failed to record payment: %w", createErr)
+	}
+
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Payment authorization declined for booking %d: %s", booking.ID, result.FailureReason))
+		return payment, err
+	}
+
+	s.logger.Info(fmt.Sprintf("Payment authorized for booking %d: %s (%s)", booking.ID,
+		payment.AuthorizedAmount.Format(payment.Currency), payment.ProviderRef))
+	return payment, nil
+}
+
+// CaptureBooking settles the hold of a booking for the given amount
+func (s *Service) CaptureBooking(ctx context.Context, bookingID int64, amount models.Money) (*models.Payment, error) {
+	defer s.flushEvents(ctx)
+
+	payment, err := s.repo.GetActiveByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != models.PaymentStatusAuthorized {
+		return nil, fmt.Errorf("payment %d is %s and cannot be captured", payment.ID, payment.Status)
+	}
+
+	result, err := s.gateway.Capture(ctx, payment.ProviderRef, amount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture payment: %w", err)
+	}
+
+	payment.Status = result.Status
+	payment.CapturedAmount = result.Amount
+	if err := s.repo.Update(ctx, payment); err != nil {
+		return nil, fmt.Errorf("failed to record capture: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Payment captured for booking %d: %s", bookingID, payment.CapturedAmount.Format(payment.Currency)))
+	return payment, nil
+}
+
+// ReauthorizeBooking raises the hold of a booking whose quote rose above it. The new hold is placed
+// before the old one is released, so a declined payment method leaves the old hold in place. Bookings
+// without a hold, or whose hold already covers the quote, are left alone.
+func (s *Service) ReauthorizeBooking(ctx context.Context, booking *models.BookRide) (*models.Payment, error) {
+	defer s.flushEvents(ctx)
+
+	current, err := s.repo.GetActiveByBookingID(ctx, booking.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != models.PaymentStatusAuthorized || booking.QuotedAmount <= current.AuthorizedAmount {
+		return current, nil
+	}
+
+	payment, err := s.AuthorizeBooking(ctx, booking, current.PaymentMethod)
+	if err != nil {
+		return payment, err
+	}
+
+	result, err := s.gateway.Void(ctx, current.ProviderRef)
+	if err != nil {
+		// The new hold is the active one; the old hold lapses with the provider
+		s.logger.Warn(fmt.Sprintf("Failed to release replaced hold %s of booking %d: %s", current.ProviderRef, booking.ID, err.Error()))
+		return payment, nil
+	}
+	current.Status = result.Status
+	if err := s.repo.Update(ctx, current); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to record release of replaced hold %s of booking %d: %s", current.ProviderRef, booking.ID, err.Error()))
+	}
+	return payment, nil
+}
+
+// ChargeBooking charges an amount after the fare was settled, such as a tip or post-ride extras.
+// It reuses the payment method of the booking's fare and captures the new hold immediately.
+func (s *Service) ChargeBooking(ctx context.Context, bookingID int64, amount models.Money, currency string) (*models.Payment, error) {
+	defer s.flushEvents(ctx)
+
+	fare, err := s.repo.GetActiveByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, fmt.Errorf("no fare payment to charge: %w", err)
+	}
+
+	result, err := s.gateway.Authorize(ctx, &AuthorizeRequest{
+		BookingID:     bookingID,
+		Amount:        amount,
+		Currency:      currency,
+		PaymentMethod: fare.PaymentMethod,
+	})
+	if err != nil && result == nil {
+		return nil, fmt.Errorf("failed to authorize charge: %w", err)
+	}
+
+	payment := &models.Payment{
+		BookingID:     bookingID,
+		Provider:      s.gateway.Name(),
+		ProviderRef:   result.ProviderRef,
+		Purpose:       models.PaymentPurposePostRide,
+		PaymentMethod: fare.PaymentMethod,
+		Status:        result.Status,
+		Currency:      currency,
+		FailureReason: result.FailureReason,
+	}
+	if result.Status == models.PaymentStatusAuthorized {
+		payment.AuthorizedAmount = result.Amount
+	}
+	if createErr := s.repo.Create(ctx, payment); createErr != nil {
+		return nil, fmt.Errorf("failed to record payment: %w", createErr)
+	}
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Post-ride charge declined for booking %d: %s", bookingID, result.FailureReason))
+		return payment, err
+	}
+
+	captured, err := s.gateway.Capture(ctx, payment.ProviderRef, amount)
+	if err != nil {
+		return payment, fmt.Errorf("failed to capture charge: %w", err)
+	}
+	payment.Status = captured.Status
+	payment.CapturedAmount = captured.Amount
+	if err := s.repo.Update(ctx, payment); err != nil {
+		return payment, fmt.Errorf("failed to record capture: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Post-ride charge captured for booking %d: %s", bookingID, payment.CapturedAmount.Format(currency)))
+	return payment, nil
+}
+
+// VoidBooking releases the hold of a booking; bookings without a hold are left alone
+func (s *Service) VoidBooking(ctx context.Context, bookingID int64) error {
+	defer s.flushEvents(ctx)
+
+	payment, err := s.repo.GetActiveByBookingID(ctx, bookingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if payment.Status != models.PaymentStatusAuthorized {
+		return fmt.Errorf("payment %d is %s and cannot be voided", payment.ID, payment.Status)
+	}
+
+	result, err := s.gateway.Void(ctx, payment.ProviderRef)
+	if err != nil {
+		return fmt.Errorf("failed to void payment: %w", err)
+	}
+
+	payment.Status = result.Status
+	if err := s.repo.Update(ctx, payment); err != nil {
+		return fmt.Errorf("failed to record void: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Payment voided for booking %d", bookingID))
+	return nil
+}
+
+// RefundBooking returns part or all of a captured payment and records who refunded it and why
+func (s *Service) RefundBooking(ctx context.Context, bookingID int64, amount models.Money, reason string, adminID *int64) (*models.Payment, *models.PaymentRefund, error) {
+	defer s.flushEvents(ctx)
+
+	payment, err := s.repo.GetActiveByBookingID(ctx, bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payment.Status != models.PaymentStatusCaptured && payment.Status != models.PaymentStatusPartiallyRefunded {
+		return nil, nil, fmt.Errorf("payment %d is %s and cannot be refunded", payment.ID, payment.Status)
+	}
+	if amount <= 0 || payment.RefundedAmount+amount > payment.CapturedAmount {
+		return nil, nil, errors.New("refund amount must be positive and not exceed the captured amount")
+	}
+
+	result, err := s.gateway.Refund(ctx, payment.ProviderRef, amount)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	payment.Status = result.Status
+	payment.RefundedAmount = result.Amount
+	refund := &models.PaymentRefund{
+		PaymentID: payment.ID,
+		BookingID: bookingID,
+		AdminID:   adminID,
+		Amount:    amount,
+		Currency:  payment.Currency,
+		Reason:    reason,
+	}
+	if err := s.repo.RecordRefund(ctx, payment, refund); err != nil {
+		return nil, nil, fmt.Errorf("failed to record refund: %w", err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Payment refunded for booking %d: %s", bookingID, amount.Format(payment.Currency)))
+	return payment, refund, nil
+}
+
+// flushEvents delivers the events an in-process gateway held back while the payment was recorded
+func (s *Service) flushEvents(ctx context.Context) {
+	flusher, ok := s.gateway.(eventFlusher)
+	if !ok {
+		return
+	}
+	if err := flusher.Flush(ctx); err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to apply payment events: %s", err.Error()))
+	}
+}
+
+// GetBookingPayments lists every payment attempt of a booking
+func (s *Service) GetBookingPayments(ctx context.Context, bookingID int64) ([]*models.Payment, error) {
+	return s.repo.GetByBookingID(ctx, bookingID)
+}
+
+// GetBookingRefunds returns the refund audit trail of a booking
+func (s *Service) GetBookingRefunds(ctx context.Context, bookingID int64) ([]*models.PaymentRefund, error) {
+	return s.repo.GetRefundsByBookingID(ctx, bookingID)
+}
+
+// HandleEvent applies a provider callback to the stored payment. Each provider event ID is handled
+// once, and an event only moves a payment forward, so a redelivered or late event cannot undo a
+// capture, void or refund.
+func (s *Service) HandleEvent(ctx context.Context, event Event) error {
+	switch event.Type {
+	case EventAuthorized, EventCaptured, EventVoided, EventRefunded, EventFailed:
+	default:
+		s.logger.Warn(fmt.Sprintf("Ignoring unsupported payment event type %s (%s)", event.Type, event.ID))
+		return nil
+	}
+
+	payment, err := s.repo.GetByProviderRef(ctx, event.Provider, event.ProviderRef)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownPayment
+	}
+	if err != nil {
+		return err
+	}
+
+	record := &models.PaymentEvent{
+		Provider:  event.Provider,
+		EventID:   event.ID,
+		PaymentID: payment.ID,
+		Type:      event.Type,
+	}
+	fromStatus := payment.Status
+	var update *models.Payment
+	if applyEvent(payment, event) {
+		record.Applied = true
+		update = payment
+	}
+
+	recorded, err := s.repo.ApplyEvent(ctx, record, update, fromStatus)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		s.logger.Info(fmt.Sprintf("Skipped payment event %s (%s): already handled or payment %d changed meanwhile",
+			event.Type, event.ID, payment.ID))
+		return nil
+	}
+	if !record.Applied {
+		s.logger.Info(fmt.Sprintf("Payment event %s (%s) does not move payment %d on from %s",
+			event.Type, event.ID, payment.ID, fromStatus))
+		return nil
+	}
+
+	s.logger.Info(fmt.Sprintf("Applied payment event %s (%s) to payment %d", event.Type, event.ID, payment.ID))
+	return nil
+}
+
+// applyEvent moves a payment to the state an event reports and reports whether it changed. Only
+// forward transitions are made: authorized to captured, voided or failed, and captured to refunded
+// by a growing amount. Events older than the last applied one are ignored. Payments are stored as
+// authorized when created, so an authorization event never changes them.
+func applyEvent(payment *models.Payment, event Event) bool {
+	if !event.CreatedAt.IsZero() && payment.LastEventAt != nil && event.CreatedAt.Before(*payment.LastEventAt) {
+		return false
+	}
+
+	switch event.Type {
+	case EventCaptured:
+		if payment.Status != models.PaymentStatusAuthorized {
+			return false
+		}
+		payment.Status = models.PaymentStatusCaptured
+		payment.CapturedAmount = event.Amount
+	case EventVoided:
+		if payment.Status != models.PaymentStatusAuthorized {
+			return false
+		}
+		payment.Status = models.PaymentStatusVoided
+	case EventFailed:
+		if payment.Status != models.PaymentStatusAuthorized {
+			return false
+		}
+		payment.Status = models.PaymentStatusFailed
+		payment.FailureReason = event.FailureReason
+	case EventRefunded:
+		if payment.Status != models.PaymentStatusCaptured && payment.Status != models.PaymentStatusPartiallyRefunded {
+			return false
+		}
+		// Refund events carry the total refunded so far, so a smaller total is an older event
+		if event.Amount <= payment.RefundedAmount {
+			return false
+		}
+		payment.RefundedAmount = event.Amount
+		payment.Status = models.PaymentStatusPartiallyRefunded
+		if payment.RefundedAmount >= payment.CapturedAmount {
+			payment.Status = models.PaymentStatusRefunded
+		}
+	default:
+		return false
+	}
+
+	if !event.CreatedAt.IsZero() {
+		occurred := event.CreatedAt.UTC()
+		payment.LastEventAt = &occurred
+	}
+	return true
+}
+
+// VerifySignature checks the hex HMAC-SHA256 signature of a webhook payload
+func (s *Service) VerifySignature(payload []byte, signature string) bool {
+	if s.webhookSecret == "" {
+		return false
+	}
+	expected := SignPayload(s.webhookSecret, payload)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// SignPayload returns the hex HMAC-SHA256 of a payload, as sent in the X-Payment-Signature header
+func SignPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
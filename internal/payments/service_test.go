@@ -0,0 +1,74 @@
+package payments
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+func TestApplyEvent(t *testing.T) {
+	lastEvent := time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		status       string
+		refunded     models.Money
+		event        Event
+		want         bool
+		wantStatus   string
+		wantRefunded models.Money
+	}{
+		{"capture authorized", models.PaymentStatusAuthorized, 0,
+			Event{Type: EventCaptured, Amount: 5000}, true, models.PaymentStatusCaptured, 0},
+		{"void authorized", models.PaymentStatusAuthorized, 0,
+			Event{Type: EventVoided}, true, models.PaymentStatusVoided, 0},
+		{"fail authorized", models.PaymentStatusAuthorized, 0,
+			Event{Type: EventFailed, FailureReason: "card declined"}, true, models.PaymentStatusFailed, 0},
+		{"authorize again", models.PaymentStatusAuthorized, 0,
+			Event{Type: EventAuthorized}, false, models.PaymentStatusAuthorized, 0},
+		{"authorize captured", models.PaymentStatusCaptured, 0,
+			Event{Type: EventAuthorized}, false, models.PaymentStatusCaptured, 0},
+		{"void captured", models.PaymentStatusCaptured, 0,
+			Event{Type: EventVoided}, false, models.PaymentStatusCaptured, 0},
+		{"capture again", models.PaymentStatusCaptured, 0,
+			Event{Type: EventCaptured, Amount: 5000}, false, models.PaymentStatusCaptured, 0},
+		{"fail captured", models.PaymentStatusCaptured, 0,
+			Event{Type: EventFailed}, false, models.PaymentStatusCaptured, 0},
+		{"refund authorized", models.PaymentStatusAuthorized, 0,
+			Event{Type: EventRefunded, Amount: 1000}, false, models.PaymentStatusAuthorized, 0},
+		{"partial refund", models.PaymentStatusCaptured, 0,
+			Event{Type: EventRefunded, Amount: 1000}, true, models.PaymentStatusPartiallyRefunded, 1000},
+		{"full refund", models.PaymentStatusPartiallyRefunded, 1000,
+			Event{Type: EventRefunded, Amount: 5000}, true, models.PaymentStatusRefunded, 5000},
+		{"smaller refund total", models.PaymentStatusPartiallyRefunded, 3000,
+			Event{Type: EventRefunded, Amount: 1000}, false, models.PaymentStatusPartiallyRefunded, 3000},
+		{"same refund total", models.PaymentStatusPartiallyRefunded, 3000,
+			Event{Type: EventRefunded, Amount: 3000}, false, models.PaymentStatusPartiallyRefunded, 3000},
+		{"refund refunded", models.PaymentStatusRefunded, 5000,
+			Event{Type: EventRefunded, Amount: 6000}, false, models.PaymentStatusRefunded, 5000},
+		{"older than last event", models.PaymentStatusAuthorized, 0,
+			Event{Type: EventCaptured, Amount: 5000, CreatedAt: lastEvent.Add(-time.Minute)}, false,
+			models.PaymentStatusAuthorized, 0},
+		{"newer than last event", models.PaymentStatusAuthorized, 0,
+			Event{Type: EventCaptured, Amount: 5000, CreatedAt: lastEvent.Add(time.Minute)}, true,
+			models.PaymentStatusCaptured, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last := lastEvent
+			payment := &models.Payment{
+				Status:         tt.status,
+				CapturedAmount: 5000,
+				RefundedAmount: tt.refunded,
+				LastEventAt:    &last,
+			}
+			if got := applyEvent(payment, tt.event); got != tt.want {
+				t.Errorf("applyEvent() = %v, want %v", got, tt.want)
+			}
+			if payment.Status != tt.wantStatus || payment.RefundedAmount != tt.wantRefunded {
+				t.Errorf("payment = %s refunded %d, want %s refunded %d",
+					payment.Status, payment.RefundedAmount, tt.wantStatus, tt.wantRefunded)
+			}
+		})
+	}
+}
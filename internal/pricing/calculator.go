@@ -0,0 +1,296 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/diagnosis/luxsuv-v4/internal/geo"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// Line item codes used on fares
+const (
+	ItemBaseFare     = "base_fare"
+	ItemExtraLuggage = "extra_luggage"
+	ItemDistance     = "distance"
+	ItemWaitingTime  = "waiting_time"
+	ItemExtraStop    = "extra_stop"
+	ItemStop         = "scheduled_stop"
+	ItemTolls        = "tolls"
+	ItemHoliday      = "holiday_surcharge"
+	ItemHourly       = "hourly_charter"
+	ItemOvertime     = "overtime"
+	ItemMeetAndGreet = "meet_and_greet"
+	ItemAddOn        = "add_on"
+	ItemTip          = "tip"
+	ItemParking      = "parking"
+)
+
+// OvertimeBlockMinutes is the increment in which charter overtime is billed
+const OvertimeBlockMinutes = 15
+
+// RateCard holds the prices for a single ride type, all in minor units
+type RateCard struct {
+	BaseFare           models.Money
+	IncludedKm         int
+	PerKm              models.Money
+	IncludedLuggage    int
+	ExtraLuggageFee    models.Money
+	FreeWaitingMinutes int
+	WaitingPerMinute   models.Money
+	ExtraStopFee       models.Money
+	HourlyRate         models.Money
+	MinimumHours       int
+	MeetAndGreetFee    models.Money
+}
+
+// DefaultRateCards returns the standard rate cards keyed by lower-case ride type
+func DefaultRateCards() map[string]RateCard {
+	return map[string]RateCard{
+		"standard": {
+			BaseFare:           8500,
+			IncludedKm:         15,
+			PerKm:              250,
+			IncludedLuggage:    4,
+			ExtraLuggageFee:    500,
+			FreeWaitingMinutes: 15,
+			WaitingPerMinute:   100,
+			ExtraStopFee:       1500,
+			HourlyRate:         9500,
+			MinimumHours:       2,
+			MeetAndGreetFee:    2500,
+		},
+		"premium": {
+			BaseFare:           12500,
+			IncludedKm:         15,
+			PerKm:              350,
+			IncludedLuggage:    4,
+			ExtraLuggageFee:    500,
+			FreeWaitingMinutes: 15,
+			WaitingPerMinute:   150,
+			ExtraStopFee:       2000,
+			HourlyRate:         13500,
+			MinimumHours:       3,
+			MeetAndGreetFee:    2500,
+		},
+		"airport": {
+			BaseFare:           9500,
+			IncludedKm:         30,
+			PerKm:              250,
+			IncludedLuggage:    6,
+			ExtraLuggageFee:    500,
+			FreeWaitingMinutes: 30,
+			WaitingPerMinute:   100,
+			ExtraStopFee:       1500,
+			HourlyRate:         9500,
+			MinimumHours:       2,
+			MeetAndGreetFee:    2500,
+		},
+	}
+}
+
+// Calculator computes quoted and final fares for bookings
+type Calculator struct {
+	currency    string
+	rates       map[string]RateCard
+	defaultRate string
+}
+
+// NewCalculator creates a calculator using the default rate cards
+func NewCalculator(currency string) *Calculator {
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+	return &Calculator{
+		currency:    currency,
+		rates:       DefaultRateCards(),
+		defaultRate: "standard",
+	}
+}
+
+// Currency returns the currency all fares are computed in
+func (c *Calculator) Currency() string {
+	return c.currency
+}
+
+// rateFor returns the rate card for a ride type, falling back to the default card
+func (c *Calculator) rateFor(rideType string) RateCard {
+	if rate, ok := c.rates[strings.ToLower(strings.TrimSpace(rideType))]; ok {
+		return rate
+	}
+	return c.rates[c.defaultRate]
+}
+
+// Quote returns the line items for the fare quoted at booking time.
+// Hourly charters are billed per booked hour (at least the rate card minimum)
+// instead of base fare and distance. A holiday surcharge of the pickup zone
+// is applied to the subtotal.
+func (c *Calculator) Quote(br *models.BookRide, zone *models.ServiceZone) models.LineItems {
+	rate := c.rateFor(br.RideType)
+
+	var items models.LineItems
+	if br.IsHourly() {
+		items = models.LineItems{c.hourlyItem(br, rate)}
+	} else {
+		items = models.LineItems{
+			models.NewLineItem(ItemBaseFare, "Base fare ("+br.RideType+")", 1, rate.BaseFare),
+		}
+	}
+
+	// Distance is only priced when every point of the itinerary has been geocoded
+	if km, ok := itineraryKm(br); ok && !br.IsHourly() {
+		if billable := int64(math.Ceil(km)) - int64(rate.IncludedKm); billable > 0 {
+			description := fmt.Sprintf("Distance beyond %d km", rate.IncludedKm)
+			items = append(items, models.NewLineItem(ItemDistance, description, billable, rate.PerKm))
+		}
+	}
+
+	if len(br.Stops) > 0 {
+		items = append(items, models.NewLineItem(ItemStop, "Scheduled stops", int64(len(br.Stops)), rate.ExtraStopFee))
+	}
+
+	if br.Flight != nil && br.Flight.MeetAndGreet {
+		items = append(items, models.NewLineItem(ItemMeetAndGreet, "Meet and greet", 1, rate.MeetAndGreetFee))
+	}
+
+	// Add-ons keep the catalog price recorded when they were selected
+	for _, addOn := range br.AddOns {
+		items = append(items, models.NewLineItem(ItemAddOn, addOn.Name, int64(addOn.Quantity), addOn.UnitPrice))
+	}
+
+	if extra := br.NumberOfLuggage - rate.IncludedLuggage; extra > 0 {
+		items = append(items, models.NewLineItem(ItemExtraLuggage, "Extra luggage", int64(extra), rate.ExtraLuggageFee))
+	}
+
+	if zone != nil {
+		if holiday := zone.HolidayOn(br.Date); holiday != nil && holiday.SurchargePercent > 0 {
+			surcharge := items.Total().Percent(int64(holiday.SurchargePercent))
+			items = append(items, models.NewLineItem(ItemHoliday, "Holiday surcharge ("+holiday.Name+")", 1, surcharge))
+		}
+	}
+
+	return items
+}
+
+// PriceBooking quotes a new booking and stores the quote on it, clearing any final fare
+func (c *Calculator) PriceBooking(br *models.BookRide, zone *models.ServiceZone) {
+	br.Currency = c.currency
+	br.LineItems = c.Quote(br, zone)
+	br.QuotedAmount = br.LineItems.Total()
+	br.FinalAmount = nil
+	br.FinalLineItems = nil
+}
+
+// Requote prices a changed booking again. Admin adjustments of the current quote are carried
+// over, so changing a booking does not undo a fare override.
+func (c *Calculator) Requote(br *models.BookRide, zone *models.ServiceZone) models.LineItems {
+	items := c.Quote(br, zone)
+	for _, item := range br.LineItems {
+		if item.Code == models.LineItemAdjustment {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// hourlyItem bills the booked hours of a charter, never less than the minimum
+func (c *Calculator) hourlyItem(br *models.BookRide, rate RateCard) models.LineItem {
+	hours := 0
+	if br.DurationHours != nil {
+		hours = *br.DurationHours
+	}
+	description := fmt.Sprintf("Hourly charter (%s)", br.RideType)
+	if hours < rate.MinimumHours {
+		hours = rate.MinimumHours
+		description = fmt.Sprintf("Hourly charter (%s, %d hour minimum)", br.RideType, rate.MinimumHours)
+	}
+	return models.NewLineItem(ItemHourly, description, int64(hours), rate.HourlyRate)
+}
+
+// itineraryKm sums the straight-line legs from pickup through each stop to drop-off
+func itineraryKm(br *models.BookRide) (float64, bool) {
+	points := []*models.Location{br.Pickup}
+	for _, stop := range br.Stops {
+		points = append(points, stop.Place)
+	}
+	points = append(points, br.Dropoff)
+
+	var km float64
+	for i, point := range points {
+		if !point.HasCoordinates() {
+			return 0, false
+		}
+		if i > 0 {
+			km += geo.DistanceKm(points[i-1], point)
+		}
+	}
+	return km, true
+}
+
+// Final recomputes the fare on completion, adding the extras recorded by the driver
+// to the line items quoted at booking time. Hourly charters add overtime beyond
+// the billed hours instead of waiting time and extra stops.
+func (c *Calculator) Final(br *models.BookRide, extras *models.CompleteRideRequest) (models.LineItems, error) {
+	if extras.ActualMinutes < 0 || extras.WaitingMinutes < 0 || extras.ExtraStops < 0 || extras.Tolls < 0 {
+		return nil, errors.New("actual minutes, waiting minutes, extra stops and tolls cannot be negative")
+	}
+
+	rate := c.rateFor(br.RideType)
+	items := append(models.LineItems{}, br.LineItems...)
+
+	if br.IsHourly() {
+		if extras.ActualMinutes == 0 {
+			return nil, errors.New("actual minutes are required to complete an hourly charter")
+		}
+		billedMinutes := int(c.hourlyItem(br, rate).Quantity) * 60
+		if over := extras.ActualMinutes - billedMinutes; over > 0 {
+			blocks := (over + OvertimeBlockMinutes - 1) / OvertimeBlockMinutes
+			unit := rate.HourlyRate.Percent(int64(OvertimeBlockMinutes * 100 / 60))
+			description := fmt.Sprintf("Overtime (%d-minute blocks)", OvertimeBlockMinutes)
+			items = append(items, models.NewLineItem(ItemOvertime, description, int64(blocks), unit))
+		}
+		if extras.Tolls > 0 {
+			items = append(items, models.NewLineItem(ItemTolls, "Tolls", 1, extras.Tolls))
+		}
+		return items, nil
+	}
+
+	if billable := extras.WaitingMinutes - rate.FreeWaitingMinutes; billable > 0 {
+		items = append(items, models.NewLineItem(ItemWaitingTime, "Waiting time (minutes)", int64(billable), rate.WaitingPerMinute))
+	}
+
+	if extras.ExtraStops > 0 {
+		items = append(items, models.NewLineItem(ItemExtraStop, "Extra stops", int64(extras.ExtraStops), rate.ExtraStopFee))
+	}
+
+	if extras.Tolls > 0 {
+		items = append(items, models.NewLineItem(ItemTolls, "Tolls", 1, extras.Tolls))
+	}
+
+	return items, nil
+}
+
+// WaitingCharge prices waiting time reported after completion at the per-minute rate of the ride type
+func (c *Calculator) WaitingCharge(rideType string, minutes int) models.Money {
+	return c.rateFor(rideType).WaitingPerMinute.Mul(int64(minutes))
+}
+
+// AdjustmentItem returns the line item a post-ride adjustment adds to the final fare
+func AdjustmentItem(adj *models.PostRideAdjustment) models.LineItem {
+	switch adj.Kind {
+	case models.AdjustmentTip:
+		return models.NewLineItem(ItemTip, "Tip", 1, adj.Amount)
+	case models.AdjustmentParking:
+		return models.NewLineItem(ItemParking, "Parking", 1, adj.Amount)
+	case models.AdjustmentWaitingTime:
+		minutes := int64(1)
+		if adj.Minutes != nil && *adj.Minutes > 0 {
+			minutes = int64(*adj.Minutes)
+		}
+		return models.NewLineItem(ItemWaitingTime, "Waiting time after completion (minutes)", minutes, adj.Amount/models.Money(minutes))
+	default:
+		return models.NewLineItem(ItemTolls, "Tolls", 1, adj.Amount)
+	}
+}
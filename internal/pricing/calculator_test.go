@@ -0,0 +1,92 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+func TestQuoteHolidaySurchargeRounding(t *testing.T) {
+	calc := NewCalculator("USD")
+	zone := &models.ServiceZone{Holidays: models.ZoneHolidays{{Date: "2025-12-25", Name: "Christmas", SurchargePercent: 10}}}
+
+	tests := []struct {
+		name          string
+		addOnPrice    models.Money
+		wantSurcharge models.Money
+	}{
+		// Base fare 8500 plus the add-on, 10% rounded half away from zero
+		{"whole cents", 1500, 1000},
+		{"rounds up", 999, 950},
+		{"rounds half up", 1005, 951},
+		{"rounds down", 1004, 950},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := &models.BookRide{
+				RideType: "standard",
+				Date:     "2025-12-25",
+				AddOns:   models.BookingAddOns{{Code: "extra", Name: "Extra", Quantity: 1, UnitPrice: tt.addOnPrice}},
+			}
+			items := calc.Quote(br, zone)
+			last := items[len(items)-1]
+			if last.Code != ItemHoliday {
+				t.Fatalf("last line item is %s, want %s", last.Code, ItemHoliday)
+			}
+			if last.Amount != tt.wantSurcharge {
+				t.Errorf("surcharge = %d, want %d", last.Amount, tt.wantSurcharge)
+			}
+		})
+	}
+}
+
+func TestFinalOvertimeBlocks(t *testing.T) {
+	calc := NewCalculator("USD")
+	hours := 3
+	br := &models.BookRide{RideType: "standard", BookingMode: models.BookingModeHourly, DurationHours: &hours}
+	br.LineItems = calc.Quote(br, nil)
+
+	tests := []struct {
+		name          string
+		actualMinutes int
+		wantBlocks    int64
+	}{
+		{"within booked hours", 180, 0},
+		{"one minute over", 181, 1},
+		{"one full block", 195, 1},
+		{"into a second block", 196, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := calc.Final(br, &models.CompleteRideRequest{ActualMinutes: tt.actualMinutes})
+			if err != nil {
+				t.Fatalf("Final: %v", err)
+			}
+			var blocks int64
+			for _, item := range items {
+				if item.Code == ItemOvertime {
+					blocks = item.Quantity
+					// A quarter of the 95.00 hourly rate
+					if item.UnitAmount != 2375 {
+						t.Errorf("overtime unit = %d, want 2375", item.UnitAmount)
+					}
+				}
+			}
+			if blocks != tt.wantBlocks {
+				t.Errorf("overtime blocks = %d, want %d", blocks, tt.wantBlocks)
+			}
+		})
+	}
+}
+
+func TestRequoteKeepsAdjustments(t *testing.T) {
+	calc := NewCalculator("USD")
+	br := &models.BookRide{RideType: "standard"}
+	calc.PriceBooking(br, nil)
+	br.LineItems = append(br.LineItems, models.NewLineItem(models.LineItemAdjustment, "Goodwill discount", 1, -1000))
+
+	items := calc.Requote(br, nil)
+	if got, want := items.Total(), models.Money(8500-1000); got != want {
+		t.Errorf("requoted total = %d, want %d", got, want)
+	}
+}
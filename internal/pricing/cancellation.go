@@ -0,0 +1,41 @@
+package pricing
+
+import (
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+// ItemCancellationFee is the line item code of a cancellation fee
+const ItemCancellationFee = "cancellation_fee"
+
+// CancellationFee returns the fee that applies if the booking is cancelled at the given time;
+// loc is the time zone of the booking's pickup zone
+func CancellationFee(policy *models.CancellationPolicy, booking *models.BookRide, loc *time.Location, now time.Time) (*models.CancellationQuote, error) {
+	pickupAt, err := booking.PickupAt(loc)
+	if err != nil {
+		return nil, err
+	}
+
+	hoursBefore := pickupAt.Sub(now).Hours()
+	quote := &models.CancellationQuote{
+		BookingID:         booking.ID,
+		PolicyRideType:    policy.RideType,
+		HoursBeforePickup: hoursBefore,
+		Currency:          booking.Currency,
+	}
+
+	switch {
+	case hoursBefore >= float64(policy.FreeUntilHours):
+		quote.Tier = models.CancellationTierFree
+	case hoursBefore >= float64(policy.LateUntilHours):
+		quote.Tier = models.CancellationTierLate
+		quote.FeePercent = policy.LateFeePercent
+	default:
+		quote.Tier = models.CancellationTierNoShow
+		quote.FeePercent = policy.NoShowFeePercent
+	}
+
+	quote.Fee = booking.QuotedAmount.Percent(int64(quote.FeePercent))
+	return quote, nil
+}
@@ -0,0 +1,71 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+func TestCancellationFee(t *testing.T) {
+	policy := &models.CancellationPolicy{
+		RideType:         "standard",
+		FreeUntilHours:   24,
+		LateUntilHours:   2,
+		LateFeePercent:   50,
+		NoShowFeePercent: 100,
+	}
+	booking := &models.BookRide{ID: 1, Date: "2025-10-20", Time: "10:00", QuotedAmount: 10001, Currency: "USD"}
+	pickup := time.Date(2025, 10, 20, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		wantTier string
+		wantFee  models.Money
+	}{
+		{"two days ahead", pickup.Add(-48 * time.Hour), models.CancellationTierFree, 0},
+		{"exactly at the free limit", pickup.Add(-24 * time.Hour), models.CancellationTierFree, 0},
+		{"just inside the late tier", pickup.Add(-24*time.Hour + time.Minute), models.CancellationTierLate, 5001},
+		{"exactly at the late limit", pickup.Add(-2 * time.Hour), models.CancellationTierLate, 5001},
+		{"within the no-show window", pickup.Add(-time.Hour), models.CancellationTierNoShow, 10001},
+		{"after pickup", pickup.Add(time.Hour), models.CancellationTierNoShow, 10001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, err := CancellationFee(policy, booking, time.UTC, tt.now)
+			if err != nil {
+				t.Fatalf("CancellationFee: %v", err)
+			}
+			if quote.Tier != tt.wantTier || quote.Fee != tt.wantFee {
+				t.Errorf("got tier %s fee %d, want tier %s fee %d", quote.Tier, quote.Fee, tt.wantTier, tt.wantFee)
+			}
+		})
+	}
+}
+
+func TestCancellationFeeUsesZoneTimeZone(t *testing.T) {
+	policy := &models.CancellationPolicy{FreeUntilHours: 24, LateUntilHours: 2, LateFeePercent: 50, NoShowFeePercent: 100}
+	booking := &models.BookRide{Date: "2025-10-20", Time: "10:00", QuotedAmount: 10000}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	// 10:00 in New York (EDT) is 14:00 UTC, so 14:00 UTC the day before is a full day ahead
+	quote, err := CancellationFee(policy, booking, newYork, time.Date(2025, 10, 19, 14, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CancellationFee: %v", err)
+	}
+	if quote.Tier != models.CancellationTierFree {
+		t.Errorf("got tier %s with %.1f hours before pickup, want %s", quote.Tier, quote.HoursBeforePickup, models.CancellationTierFree)
+	}
+}
+
+func TestCancellationFeeInvalidPickup(t *testing.T) {
+	policy := &models.CancellationPolicy{FreeUntilHours: 24, LateUntilHours: 2}
+	booking := &models.BookRide{Date: "2025-10-20", Time: "25:00"}
+	if _, err := CancellationFee(policy, booking, time.UTC, time.Now()); err == nil {
+		t.Error("expected an error for an invalid pickup time")
+	}
+}
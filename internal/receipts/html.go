@@ -0,0 +1,148 @@
+package receipts
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+var htmlTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"money": func(d *Document, m models.Money) string { return m.Format(d.Booking.Currency) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Receipt {{.Number}}</title>
+    <style>
+        body {
+            font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, Oxygen, Ubuntu, Cantarell, sans-serif;
+            line-height: 1.5;
+            color: #333;
+            margin: 0;
+            padding: 0;
+            background-color: #f8f9fa;
+        }
+        .container {
+            max-width: 720px;
+            margin: 40px auto;
+            background: white;
+            border-radius: 8px;
+            box-shadow: 0 2px 10px rgba(0,0,0,0.1);
+            overflow: hidden;
+        }
+        .header {
+            background: linear-gradient(135deg, #2d3748 0%, #1a202c 100%);
+            color: white;
+            padding: 30px;
+            display: flex;
+            justify-content: space-between;
+        }
+        .header h1 { margin: 0; font-size: 26px; font-weight: 600; }
+        .header p { margin: 4px 0 0; font-size: 14px; color: #cbd5e0; }
+        .content { padding: 30px; }
+        .columns { display: flex; justify-content: space-between; margin-bottom: 24px; }
+        .columns div { width: 48%; font-size: 14px; }
+        h3 { color: #2d3748; margin: 0 0 8px; font-size: 15px; text-transform: uppercase; letter-spacing: 0.5px; }
+        table { width: 100%; border-collapse: collapse; font-size: 14px; }
+        th { text-align: left; border-bottom: 2px solid #2d3748; padding: 8px 4px; }
+        td { border-bottom: 1px solid #e2e8f0; padding: 8px 4px; }
+        .num { text-align: right; white-space: nowrap; }
+        .totals td { border-bottom: none; }
+        .grand td { font-weight: 600; font-size: 16px; border-top: 2px solid #2d3748; }
+        .footer {
+            background-color: #f8f9fa;
+            padding: 20px 30px;
+            font-size: 12px;
+            color: #718096;
+        }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <div>
+                <h1>{{.Entity.Name}}</h1>
+                {{if .Entity.Address}}<p>{{.Entity.Address}}</p>{{end}}
+                {{if .Entity.TaxID}}<p>Tax ID: {{.Entity.TaxID}}</p>{{end}}
+            </div>
+            <div style="text-align: right;">
+                <h1>Receipt</h1>
+                <p>No. {{.Number}}</p>
+                <p>Issued {{.IssuedAt.Format "2006-01-02"}}</p>
+            </div>
+        </div>
+        <div class="content">
+            <div class="columns">
+                <div>
+                    <h3>Billed To</h3>
+                    {{.Booking.YourName}}<br>
+                    {{.Booking.Email}}<br>
+                    {{.Booking.PhoneNumber}}
+                </div>
+                <div>
+                    <h3>Trip</h3>
+                    Booking #{{.Booking.ID}} ({{.Booking.RideType}})<br>
+                    {{.Booking.Date}} at {{.Booking.Time}}<br>
+                    From: {{.Booking.PickupLocation}}<br>
+                    {{range .Booking.Stops}}Stop {{.Position}}: {{.Address}}<br>{{end}}
+                    {{if .Booking.DropoffLocation}}To: {{.Booking.DropoffLocation}}{{end}}
+                </div>
+            </div>
+
+            <table>
+                <tr>
+                    <th>Description</th>
+                    <th class="num">Qty</th>
+                    <th class="num">Unit</th>
+                    <th class="num">Amount</th>
+                </tr>
+                {{range .Items}}
+                <tr>
+                    <td>{{.Description}}</td>
+                    <td class="num">{{.Quantity}}</td>
+                    <td class="num">{{money $ .UnitAmount}}</td>
+                    <td class="num">{{money $ .Amount}}</td>
+                </tr>
+                {{end}}
+                <tr class="totals">
+                    <td colspan="3" class="num">Net</td>
+                    <td class="num">{{money . .Net}}</td>
+                </tr>
+                <tr class="totals">
+                    <td colspan="3" class="num">{{.Entity.TaxLabel}} ({{.Entity.TaxRate}}, included)</td>
+                    <td class="num">{{money . .Tax}}</td>
+                </tr>
+                <tr class="grand">
+                    <td colspan="3" class="num">Total</td>
+                    <td class="num">{{money . .Total}}</td>
+                </tr>
+                <tr class="totals">
+                    <td colspan="3" class="num">Paid{{if .PaymentMethod}} by {{.PaymentMethod}}{{end}}</td>
+                    <td class="num">{{money . .Paid}}</td>
+                </tr>
+                {{if .Refunded}}
+                <tr class="totals">
+                    <td colspan="3" class="num">Refunded</td>
+                    <td class="num">{{money . .Refunded}}</td>
+                </tr>
+                {{end}}
+            </table>
+        </div>
+        <div class="footer">
+            <p><strong>{{.Entity.Name}}</strong>{{if .Entity.Email}} · {{.Entity.Email}}{{end}}{{if .Entity.Phone}} · {{.Entity.Phone}}{{end}}</p>
+            <p>Thank you for riding with LuxSUV.</p>
+        </div>
+    </div>
+</body>
+</html>
+`))
+
+// RenderHTML renders the receipt as a standalone HTML page
+func RenderHTML(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,279 @@
+package receipts
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+// Letter page size and margins in PDF points
+const (
+	pageWidth    = 612.0
+	pageHeight   = 792.0
+	pageMargin   = 50.0
+	bottomMargin = 60.0
+)
+
+// RenderPDF renders the receipt as a PDF document using the built-in Helvetica fonts,
+// so no external binaries or font files are needed
+func RenderPDF(doc *Document) ([]byte, error) {
+	p := newPDFWriter()
+	currency := doc.Booking.Currency
+	right := pageWidth - pageMargin
+
+	// Header band with the issuing company and receipt number
+	p.fillRect(0, pageHeight-110, pageWidth, 110, 0.176, 0.216, 0.282)
+	p.setGray(1)
+	p.text(pageMargin, pageHeight-50, 18, true, doc.Entity.Name)
+	y := pageHeight - 68
+	if doc.Entity.Address != "" {
+		p.text(pageMargin, y, 9, false, doc.Entity.Address)
+		y -= 12
+	}
+	if doc.Entity.TaxID != "" {
+		p.text(pageMargin, y, 9, false, "Tax ID: "+doc.Entity.TaxID)
+	}
+	p.textRight(right, pageHeight-50, 18, true, "Receipt")
+	p.textRight(right, pageHeight-68, 9, false, "No. "+doc.Number)
+	p.textRight(right, pageHeight-80, 9, false, "Issued "+doc.IssuedAt.Format("2006-01-02"))
+	p.setGray(0.2)
+
+	// Billed to and trip details side by side
+	y = pageHeight - 140
+	column := pageMargin + 270
+	p.text(pageMargin, y, 10, true, "BILLED TO")
+	p.text(column, y, 10, true, "TRIP")
+	billed := []string{doc.Booking.YourName, doc.Booking.Email, doc.Booking.PhoneNumber}
+	trip := []string{
+		fmt.Sprintf("Booking #%d (%s)", doc.Booking.ID, doc.Booking.RideType),
+		fmt.Sprintf("%s at %s", doc.Booking.Date, doc.Booking.Time),
+		"From: " + doc.Booking.PickupLocation,
+	}
+	for _, stop := range doc.Booking.Stops {
+		trip = append(trip, fmt.Sprintf("Stop %d: %s", stop.Position, stop.Address))
+	}
+	if doc.Booking.DropoffLocation != "" {
+		trip = append(trip, "To: "+doc.Booking.DropoffLocation)
+	}
+	for i := 0; i < len(billed) || i < len(trip); i++ {
+		y -= 13
+		if i < len(billed) {
+			p.text(pageMargin, y, 9, false, truncate(billed[i], 48))
+		}
+		if i < len(trip) {
+			p.text(column, y, 9, false, truncate(trip[i], 52))
+		}
+	}
+
+	// Itemized fare
+	y -= 30
+	qtyX, unitX := right-190, right-95
+	itemHeader := func() {
+		p.text(pageMargin, y, 10, true, "Description")
+		p.textRight(qtyX, y, 10, true, "Qty")
+		p.textRight(unitX, y, 10, true, "Unit")
+		p.textRight(right, y, 10, true, "Amount")
+		p.line(pageMargin, y-5, right, y-5, 1.2)
+		y -= 20
+	}
+	itemHeader()
+	for _, item := range doc.Items {
+		if y < bottomMargin+100 {
+			p.newPage()
+			y = pageHeight - pageMargin
+			itemHeader()
+		}
+		p.text(pageMargin, y, 9, false, truncate(item.Description, 60))
+		p.textRight(qtyX, y, 9, false, fmt.Sprintf("%d", item.Quantity))
+		p.textRight(unitX, y, 9, false, item.UnitAmount.Format(currency))
+		p.textRight(right, y, 9, false, item.Amount.Format(currency))
+		p.line(pageMargin, y-6, right, y-6, 0.3)
+		y -= 18
+	}
+
+	// Totals
+	labelX := right - 110
+	total := func(label, amount string, bold bool) {
+		y -= 14
+		p.textRight(labelX, y, 9, bold, label)
+		p.textRight(right, y, 9, bold, amount)
+	}
+	total("Net", doc.Net.Format(currency), false)
+	total(fmt.Sprintf("%s (%s, included)", doc.Entity.TaxLabel, doc.Entity.TaxRate()), doc.Tax.Format(currency), false)
+	p.line(labelX-150, y-6, right, y-6, 1.2)
+	y -= 4
+	total("Total", doc.Total.Format(currency), true)
+	paid := "Paid"
+	if doc.PaymentMethod != "" {
+		paid += " by " + doc.PaymentMethod
+	}
+	total(paid, doc.Paid.Format(currency), false)
+	if doc.Refunded > 0 {
+		total("Refunded", doc.Refunded.Format(currency), false)
+	}
+
+	// Footer with the company contact details
+	footer := doc.Entity.Name
+	if doc.Entity.Email != "" {
+		footer += " - " + doc.Entity.Email
+	}
+	if doc.Entity.Phone != "" {
+		footer += " - " + doc.Entity.Phone
+	}
+	p.setGray(0.45)
+	p.text(pageMargin, bottomMargin-20, 8, false, footer)
+	p.text(pageMargin, bottomMargin-32, 8, false, "Thank you for riding with LuxSUV.")
+
+	return p.bytes(fmt.Sprintf("Receipt %s", doc.Number))
+}
+
+// truncate shortens text that would overflow its column
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
+
+// pdfWriter builds a minimal PDF: text in the standard Helvetica fonts, lines and filled rectangles
+type pdfWriter struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+}
+
+func newPDFWriter() *pdfWriter {
+	p := &pdfWriter{}
+	p.newPage()
+	return p
+}
+
+func (p *pdfWriter) newPage() {
+	p.current = &bytes.Buffer{}
+	p.pages = append(p.pages, p.current)
+}
+
+func (p *pdfWriter) setGray(gray float64) {
+	fmt.Fprintf(p.current, "%.3f g %.3f G\n", gray, gray)
+}
+
+func (p *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.current, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDF(s))
+}
+
+// textRight draws text ending at x
+func (p *pdfWriter) textRight(x, y, size float64, bold bool, s string) {
+	p.text(x-textWidth(s, size), y, size, bold, s)
+}
+
+func (p *pdfWriter) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(p.current, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+func (p *pdfWriter) fillRect(x, y, w, h, r, g, b float64) {
+	fmt.Fprintf(p.current, "q %.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f Q\n", r, g, b, x, y, w, h)
+}
+
+// bytes assembles the document: catalog, page tree, fonts, then a page and compressed content stream per page
+func (p *pdfWriter) bytes(title string) ([]byte, error) {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	const firstPage = 6
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (LuxSUV) >>", escapePDF(title)))
+
+	for i, page := range p.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, firstPage+2*i+1))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes(), nil
+}
+
+// escapePDF encodes text as a WinAnsi PDF string literal; characters outside the encoding become '?'
+func escapePDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '€':
+			b.WriteString(`\200`)
+		case r == '•':
+			b.WriteString(`\225`)
+		case r == '–':
+			b.WriteString(`\226`)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// helveticaWidths holds the advance widths of printable ASCII in Helvetica, per 1000 units of font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space to /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 to 9
+	278, 278, 584, 584, 584, 556, 1015, // : to @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A to M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N to Z
+	278, 278, 278, 469, 556, 333, // [ to `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a to m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n to z
+	334, 260, 334, 584, // { to ~
+}
+
+// textWidth estimates the width of text in points; it is exact for regular Helvetica and close
+// enough for bold, whose digits (used in right-aligned amounts) have the same width
+func textWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		if r >= 32 && r < 127 {
+			units += helveticaWidths[r-32]
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
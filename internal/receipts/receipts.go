@@ -0,0 +1,112 @@
+package receipts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// ErrNotCompleted is returned for bookings that have not been completed yet
+var ErrNotCompleted = errors.New("receipts are only available for completed bookings")
+
+// Document is everything printed on the receipt of a completed booking
+type Document struct {
+	Number        string
+	IssuedAt      time.Time
+	Entity        *models.LegalEntity
+	Booking       *models.BookRide
+	Items         models.LineItems
+	Total         models.Money // Tax-inclusive
+	Tax           models.Money // Tax included in Total
+	Net           models.Money
+	Paid          models.Money // Captured across all payments, less refunds
+	Refunded      models.Money
+	PaymentMethod string
+}
+
+// Filename returns the file name of the receipt PDF, e.g. receipt-LUX-000042.pdf
+func (d *Document) Filename() string {
+	return "receipt-" + d.Number + ".pdf"
+}
+
+// Service numbers receipts and assembles their documents
+type Service struct {
+	receipts repository.ReceiptRepository
+	entities repository.LegalEntityRepository
+	payments repository.PaymentRepository
+	logger   *logger.Logger
+}
+
+func NewService(receipts repository.ReceiptRepository, entities repository.LegalEntityRepository, payments repository.PaymentRepository, logger *logger.Logger) *Service {
+	return &Service{
+		receipts: receipts,
+		entities: entities,
+		payments: payments,
+		logger:   logger,
+	}
+}
+
+// ForBooking returns the receipt document of a completed booking, issuing its number on first use.
+// The number never changes; the amounts always reflect the current final fare and payments.
+func (s *Service) ForBooking(ctx context.Context, booking *models.BookRide) (*Document, error) {
+	if booking.BookStatus != models.BookStatusCompleted {
+		return nil, ErrNotCompleted
+	}
+
+	receipt, issued, err := s.receipts.Issue(ctx, booking.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue receipt: %w", err)
+	}
+	entity, err := s.entities.GetByID(ctx, receipt.LegalEntityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load legal entity: %w", err)
+	}
+
+	doc := &Document{
+		Number:   receipt.Number,
+		IssuedAt: receipt.IssuedAt,
+		Entity:   entity,
+		Booking:  booking,
+		Items:    booking.FinalLineItems,
+		Total:    booking.QuotedAmount,
+	}
+	if len(doc.Items) == 0 {
+		doc.Items = booking.LineItems
+	}
+	if booking.FinalAmount != nil {
+		doc.Total = *booking.FinalAmount
+	}
+	doc.Tax = entity.IncludedTax(doc.Total)
+	doc.Net = doc.Total - doc.Tax
+
+	payments, err := s.payments.GetByBookingID(ctx, booking.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payments: %w", err)
+	}
+	for _, payment := range payments {
+		doc.Paid += payment.CapturedAmount - payment.RefundedAmount
+		doc.Refunded += payment.RefundedAmount
+		if payment.Purpose == models.PaymentPurposeFare && doc.PaymentMethod == "" && payment.CapturedAmount > 0 {
+			doc.PaymentMethod = describePaymentMethod(payment)
+		}
+	}
+
+	if issued {
+		s.logger.Info(fmt.Sprintf("Receipt %s issued for booking %d by %s", receipt.Number, booking.ID, entity.Code))
+	}
+	return doc, nil
+}
+
+// describePaymentMethod names the provider and the last characters of the stored method
+func describePaymentMethod(payment *models.Payment) string {
+	method := payment.PaymentMethod
+	if len(method) < 4 {
+		return payment.Provider
+	}
+	return fmt.Sprintf("%s (ending %s)", payment.Provider, method[len(method)-4:])
+}
@@ -0,0 +1,153 @@
+package recurrence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/payments"
+	"github.com/diagnosis/luxsuv-v4/internal/pricing"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/diagnosis/luxsuv-v4/internal/validation"
+	"github.com/diagnosis/luxsuv-v4/internal/zones"
+)
+
+// SkippedOccurrence is an occurrence that could not be booked
+type SkippedOccurrence struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"`
+}
+
+// Generator books the occurrences of active series a fixed number of days ahead,
+// so each occurrence can be priced, authorized and dispatched like any booking
+type Generator struct {
+	series      repository.BookingSeriesRepository
+	bookings    repository.BookRideRepository
+	zones       *zones.Resolver
+	pricing     *pricing.Calculator
+	payments    *payments.Service
+	logger      *logger.Logger
+	horizonDays int
+}
+
+func NewGenerator(series repository.BookingSeriesRepository, bookings repository.BookRideRepository, zones *zones.Resolver, pricing *pricing.Calculator, payments *payments.Service, logger *logger.Logger, horizonDays int) *Generator {
+	return &Generator{
+		series:      series,
+		bookings:    bookings,
+		zones:       zones,
+		pricing:     pricing,
+		payments:    payments,
+		logger:      logger,
+		horizonDays: horizonDays,
+	}
+}
+
+// GenerateAll generates the upcoming occurrences of every active series; run as a scheduled job
+func (g *Generator) GenerateAll(ctx context.Context) error {
+	list, err := g.series.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list active booking series: %w", err)
+	}
+	for _, series := range list {
+		if _, _, err := g.Generate(ctx, series); err != nil {
+			g.logger.Err(fmt.Sprintf("Failed to generate occurrences of series %d: %s", series.ID, err.Error()))
+		}
+	}
+	return nil
+}
+
+// Generate books the occurrences of a series from where generation last stopped up to the horizon.
+// Occurrences that break a schedule rule or fail payment authorization are skipped, not retried.
+func (g *Generator) Generate(ctx context.Context, series *models.BookingSeries) ([]*models.BookRide, []SkippedOccurrence, error) {
+	if series.Status != models.SeriesStatusActive {
+		return nil, nil, nil
+	}
+
+	rule, err := Parse(series.Recurrence)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	today := now.UTC().Format(dateLayout)
+	from := series.StartDate
+	if series.GeneratedUntil != nil {
+		next, err := time.Parse(dateLayout, *series.GeneratedUntil)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = next.AddDate(0, 0, 1).Format(dateLayout)
+	}
+	if from < today {
+		from = today
+	}
+	to := now.UTC().AddDate(0, 0, g.horizonDays).Format(dateLayout)
+	if from > to {
+		return nil, nil, nil
+	}
+
+	dates, finished, err := rule.Dates(series.StartDate, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created []*models.BookRide
+	var skipped []SkippedOccurrence
+	for _, date := range dates {
+		booking, err := g.book(ctx, series, date, now)
+		if err != nil {
+			g.logger.Warn(fmt.Sprintf("Skipped occurrence %s of series %d: %s", date, series.ID, err.Error()))
+			skipped = append(skipped, SkippedOccurrence{Date: date, Reason: err.Error()})
+			continue
+		}
+		created = append(created, booking)
+	}
+
+	series.GeneratedUntil = &to
+	if finished {
+		series.Status = models.SeriesStatusCompleted
+	}
+	if err := g.series.Update(ctx, series); err != nil {
+		return created, skipped, err
+	}
+
+	if len(created) > 0 {
+		g.logger.Info(fmt.Sprintf("Generated %d occurrences of series %d up to %s", len(created), series.ID, to))
+	}
+	return created, skipped, nil
+}
+
+// book creates, prices and authorizes a single occurrence
+func (g *Generator) book(ctx context.Context, series *models.BookingSeries, date string, now time.Time) (*models.BookRide, error) {
+	br := series.Occurrence(date)
+
+	trip, err := g.zones.ResolveTrip(ctx, br.PickupLocation, br.Pickup, br.Dropoff)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateBookingDateTime(trip.PickupZone, br.Date, br.Time, now); err != nil {
+		return nil, err
+	}
+
+	br.BookStatus = models.BookStatusPending
+	br.RideStatus = models.RideStatusPending
+	trip.Apply(br)
+	g.pricing.PriceBooking(br, trip.PickupZone)
+
+	if err := g.bookings.Create(ctx, br); err != nil {
+		return nil, fmt.Errorf("failed to create booking: %w", err)
+	}
+
+	if g.payments != nil {
+		if _, err := g.payments.AuthorizeBooking(ctx, br, br.PaymentMethod); err != nil {
+			if cancelErr := g.bookings.Cancel(ctx, br.ID, "payment authorization failed"); cancelErr != nil {
+				g.logger.Err(fmt.Sprintf("Failed to cancel occurrence %d after payment failure: %s", br.ID, cancelErr.Error()))
+			}
+			return nil, fmt.Errorf("payment authorization failed: %w", err)
+		}
+	}
+	br.PaymentMethod = ""
+	return br, nil
+}
@@ -0,0 +1,208 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported frequencies
+const (
+	FreqDaily  = "DAILY"
+	FreqWeekly = "WEEKLY"
+)
+
+// MaxOccurrences bounds the length of a series
+const MaxOccurrences = 366
+
+const dateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Rule is the supported subset of an RFC 5545 RRULE: FREQ=DAILY or WEEKLY,
+// INTERVAL, BYDAY (weekly only) and exactly one of UNTIL or COUNT
+type Rule struct {
+	Freq     string
+	Interval int
+	ByDay    []time.Weekday
+	Until    *time.Time
+	Count    int
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;UNTIL=20251231".
+// An optional "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	if s == "" {
+		return nil, errors.New("recurrence rule is required")
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		switch key {
+		case "FREQ":
+			if value != FreqDaily && value != FreqWeekly {
+				return nil, errors.New("FREQ must be DAILY or WEEKLY")
+			}
+			rule.Freq = value
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > 52 {
+				return nil, errors.New("INTERVAL must be between 1 and 52")
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := weekdays[code]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY value %q", code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 || count > MaxOccurrences {
+				return nil, fmt.Errorf("COUNT must be between 1 and %d", MaxOccurrences)
+			}
+			rule.Count = count
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %s", key)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != FreqWeekly {
+		return nil, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if (rule.Until == nil) == (rule.Count == 0) {
+		return nil, errors.New("exactly one of UNTIL or COUNT is required")
+	}
+	return rule, nil
+}
+
+// parseUntil accepts UNTIL as YYYYMMDD, YYYYMMDDTHHMMSSZ or YYYY-MM-DD; only the date is used
+func parseUntil(value string) (time.Time, error) {
+	if len(value) >= 8 && !strings.Contains(value, "-") {
+		if until, err := time.Parse("20060102", value[:8]); err == nil {
+			return until, nil
+		}
+	}
+	if until, err := time.Parse(dateLayout, value); err == nil {
+		return until, nil
+	}
+	return time.Time{}, errors.New("UNTIL must be a date such as 20251231")
+}
+
+// String renders the rule in canonical form
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			codes[i] = weekdayCodes[day]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Dates returns the occurrence dates (YYYY-MM-DD) of a series starting on start that fall
+// between from and to inclusive. The start date itself only occurs if it matches the rule.
+// The second result reports whether the series has no occurrences after to.
+func (r *Rule) Dates(start, from, to string) ([]string, bool, error) {
+	startDay, err := time.Parse(dateLayout, start)
+	if err != nil {
+		return nil, false, errors.New("invalid start date")
+	}
+	fromDay, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return nil, false, errors.New("invalid from date")
+	}
+	toDay, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return nil, false, errors.New("invalid to date")
+	}
+
+	last := startDay.AddDate(1, 0, 0)
+	if r.Until != nil && r.Until.Before(last) {
+		last = *r.Until
+	}
+
+	var dates []string
+	count := 0
+	for day := startDay; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !r.matches(startDay, day) {
+			continue
+		}
+		count++
+		if r.Count > 0 && count > r.Count {
+			return dates, true, nil
+		}
+		if day.After(toDay) {
+			return dates, false, nil
+		}
+		if !day.Before(fromDay) {
+			dates = append(dates, day.Format(dateLayout))
+		}
+	}
+	return dates, true, nil
+}
+
+// matches reports whether day is an occurrence of a series starting on start
+func (r *Rule) matches(start, day time.Time) bool {
+	days := int(day.Sub(start).Hours() / 24)
+	switch r.Freq {
+	case FreqDaily:
+		return days%r.Interval == 0
+	case FreqWeekly:
+		// Weeks are counted from the Monday of the start week
+		weekStart := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		week := int(day.Sub(weekStart).Hours()/24) / 7
+		if week%r.Interval != 0 {
+			return false
+		}
+		if len(r.ByDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+		for _, weekday := range r.ByDay {
+			if day.Weekday() == weekday {
+				return true
+			}
+		}
+	}
+	return false
+}
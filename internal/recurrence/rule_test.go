@@ -0,0 +1,138 @@
+package recurrence
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "FREQ=DAILY;COUNT=5", want: "FREQ=DAILY;COUNT=5"},
+		{rule: "FREQ=DAILY;INTERVAL=1;COUNT=5", want: "FREQ=DAILY;COUNT=5"},
+		{rule: "rrule:freq=weekly;byday=mo,fr;until=20251231T000000Z", want: "FREQ=WEEKLY;BYDAY=MO,FR;UNTIL=20251231"},
+		{rule: "FREQ=WEEKLY;INTERVAL=2;UNTIL=2025-12-31", want: "FREQ=WEEKLY;INTERVAL=2;UNTIL=20251231"},
+		{rule: "", wantErr: true},
+		{rule: "FREQ=MONTHLY;COUNT=1", wantErr: true},
+		{rule: "FREQ=DAILY", wantErr: true},
+		{rule: "COUNT=3", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=1;UNTIL=20251231", wantErr: true},
+		{rule: "FREQ=DAILY;BYDAY=MO;COUNT=1", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=XX;COUNT=1", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0;COUNT=1", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=367", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT", wantErr: true},
+		{rule: "FREQ=DAILY;UNTIL=31/12/2025", wantErr: true},
+		{rule: "FREQ=DAILY;BYMONTH=1;COUNT=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %s, want an error", tt.rule, rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDates(t *testing.T) {
+	// 2025-10-20 is a Monday
+	tests := []struct {
+		name     string
+		rule     string
+		start    string
+		from     string
+		to       string
+		want     []string
+		wantDone bool
+	}{
+		{
+			name: "daily count", rule: "FREQ=DAILY;COUNT=3",
+			start: "2025-10-20", from: "2025-10-20", to: "2025-12-31",
+			want: []string{"2025-10-20", "2025-10-21", "2025-10-22"}, wantDone: true,
+		},
+		{
+			name: "every other day until", rule: "FREQ=DAILY;INTERVAL=2;UNTIL=20251026",
+			start: "2025-10-20", from: "2025-10-20", to: "2025-12-31",
+			want: []string{"2025-10-20", "2025-10-22", "2025-10-24", "2025-10-26"}, wantDone: true,
+		},
+		{
+			name: "weekdays by day", rule: "FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=4",
+			start: "2025-10-20", from: "2025-10-20", to: "2025-12-31",
+			want: []string{"2025-10-20", "2025-10-22", "2025-10-24", "2025-10-27"}, wantDone: true,
+		},
+		{
+			name: "weekly on the start weekday", rule: "FREQ=WEEKLY;COUNT=3",
+			start: "2025-10-22", from: "2025-10-22", to: "2025-12-31",
+			want: []string{"2025-10-22", "2025-10-29", "2025-11-05"}, wantDone: true,
+		},
+		{
+			name: "fortnightly counts weeks from monday", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20251116",
+			start: "2025-10-20", from: "2025-10-20", to: "2025-12-31",
+			want: []string{"2025-10-21", "2025-10-23", "2025-11-04", "2025-11-06"}, wantDone: true,
+		},
+		{
+			name: "start date not matching the rule", rule: "FREQ=WEEKLY;BYDAY=TU;COUNT=2",
+			start: "2025-10-20", from: "2025-10-20", to: "2025-12-31",
+			want: []string{"2025-10-21", "2025-10-28"}, wantDone: true,
+		},
+		{
+			name: "window inside the series", rule: "FREQ=DAILY;COUNT=10",
+			start: "2025-10-20", from: "2025-10-22", to: "2025-10-24",
+			want: []string{"2025-10-22", "2025-10-23", "2025-10-24"}, wantDone: false,
+		},
+		{
+			name: "window ends on the last occurrence", rule: "FREQ=DAILY;COUNT=3",
+			start: "2025-10-20", from: "2025-10-20", to: "2025-10-22",
+			want: []string{"2025-10-20", "2025-10-21", "2025-10-22"}, wantDone: true,
+		},
+		{
+			name: "series longer than a year is cut off", rule: "FREQ=WEEKLY;UNTIL=20301231",
+			start: "2025-10-20", from: "2026-10-01", to: "2026-12-31",
+			want: []string{"2026-10-05", "2026-10-12", "2026-10-19"}, wantDone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rule, err)
+			}
+			got, done, err := rule.Dates(tt.start, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Dates: %v", err)
+			}
+			if !slices.Equal(got, tt.want) || done != tt.wantDone {
+				t.Errorf("Dates = %v, %v; want %v, %v", got, done, tt.want, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestDatesInvalidInput(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dates := range [][3]string{
+		{"2025/10/20", "2025-10-20", "2025-10-31"},
+		{"2025-10-20", "", "2025-10-31"},
+		{"2025-10-20", "2025-10-20", "2025-13-01"},
+	} {
+		if _, _, err := rule.Dates(dates[0], dates[1], dates[2]); err == nil {
+			t.Errorf("Dates%v: expected an error", dates)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package reminders
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+const dateLayout = "2006-01-02"
+
+// Config holds when reminders and alerts are sent
+type Config struct {
+	// ReminderOffsets are how long before pickup riders are reminded
+	ReminderOffsets []time.Duration
+	// UnassignedWindow is how close to pickup a booking without a driver is reported to dispatch
+	UnassignedWindow time.Duration
+	// DispatchEmail receives unassigned booking alerts
+	DispatchEmail string
+	// ManifestLocation is the time zone in which "tomorrow" is determined for driver manifests
+	ManifestLocation *time.Location
+}
+
+// Service sends the scheduled messages about upcoming rides: rider reminders, driver manifests
+// and dispatch alerts. Pickup times are read in the time zone of the booking's pickup zone.
+type Service struct {
+	bookings repository.BookRideRepository
+	zones    repository.ServiceZoneRepository
+	notices  repository.BookingNoticeRepository
+	users    repository.UserRepository
+	notifier *notify.Dispatcher
+	logger   *logger.Logger
+	config   Config
+}
+
+func NewService(bookings repository.BookRideRepository, zones repository.ServiceZoneRepository, notices repository.BookingNoticeRepository, users repository.UserRepository, notifier *notify.Dispatcher, logger *logger.Logger, config Config) *Service {
+	offsets := append([]time.Duration(nil), config.ReminderOffsets...)
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	config.ReminderOffsets = offsets
+
+	return &Service{
+		bookings: bookings,
+		zones:    zones,
+		notices:  notices,
+		users:    users,
+		notifier: notifier,
+		logger:   logger,
+		config:   config,
+	}
+}
+
+// SendRiderReminders reminds riders whose pickup is within a reminder offset. Only the closest
+// offset is sent, so a ride booked three hours ahead gets the two-hour reminder but not the
+// day-before one.
+func (s *Service) SendRiderReminders(ctx context.Context) error {
+	if len(s.config.ReminderOffsets) == 0 {
+		return nil
+	}
+	now := time.Now()
+	bookings, pickups, err := s.upcoming(ctx, now, s.config.ReminderOffsets[len(s.config.ReminderOffsets)-1])
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for i, booking := range bookings {
+		until := pickups[i].Sub(now)
+		var due []string
+		for _, offset := range s.config.ReminderOffsets {
+			if until <= offset {
+				due = append(due, models.ReminderNotice(offset))
+			}
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		claimed, err := s.notices.Claim(ctx, booking.ID, due...)
+		if err != nil {
+			s.logger.Err(fmt.Sprintf("Failed to record reminder for booking %d: %s", booking.ID, err.Error()))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.notifier.RideReminder(ctx, booking)
+		sent++
+	}
+
+	if sent > 0 {
+		s.logger.Info(fmt.Sprintf("Sent %d ride reminders", sent))
+	}
+	return nil
+}
+
+// SendDriverManifests emails every driver the rides they are assigned tomorrow
+func (s *Service) SendDriverManifests(ctx context.Context) error {
+	tomorrow := time.Now().In(s.config.ManifestLocation).AddDate(0, 0, 1).Format(dateLayout)
+	bookings, err := s.bookings.GetUpcoming(ctx, tomorrow, tomorrow)
+	if err != nil {
+		return fmt.Errorf("failed to load rides of %s: %w", tomorrow, err)
+	}
+
+	byDriver := make(map[int64][]*models.BookRide)
+	var drivers []int64
+	for _, booking := range bookings {
+		if booking.DriverID == nil {
+			continue
+		}
+		if _, ok := byDriver[*booking.DriverID]; !ok {
+			drivers = append(drivers, *booking.DriverID)
+		}
+		byDriver[*booking.DriverID] = append(byDriver[*booking.DriverID], booking)
+	}
+	if !s.notifier.Emails() {
+		s.logger.Warn(fmt.Sprintf("Email disabled - %d driver manifests for %s not sent", len(drivers), tomorrow))
+		return nil
+	}
+
+	for _, driverID := range drivers {
+		driver, err := s.users.GetByID(ctx, driverID)
+		if err != nil {
+			s.logger.Err(fmt.Sprintf("Failed to load driver %d for manifest: %s", driverID, err.Error()))
+			continue
+		}
+		s.notifier.DriverManifest(ctx, driver, tomorrow, byDriver[driverID])
+	}
+	s.logger.Info(fmt.Sprintf("Sent %s manifests to %d drivers", tomorrow, len(drivers)))
+	return nil
+}
+
+// AlertUnassigned reports bookings that no driver has accepted within the unassigned window
+// of their pickup. Each booking is reported once, and only while its pickup is still ahead.
+func (s *Service) AlertUnassigned(ctx context.Context) error {
+	now := time.Now()
+	bookings, pickups, err := s.upcoming(ctx, now, s.config.UnassignedWindow)
+	if err != nil {
+		return err
+	}
+
+	var alerts []*models.BookRide
+	for i, booking := range bookings {
+		if booking.DriverID != nil || pickups[i].Sub(now) > s.config.UnassignedWindow {
+			continue
+		}
+		claimed, err := s.notices.Claim(ctx, booking.ID, models.NoticeUnassignedAlert)
+		if err != nil {
+			s.logger.Err(fmt.Sprintf("Failed to record unassigned alert for booking %d: %s", booking.ID, err.Error()))
+			continue
+		}
+		if claimed {
+			s.logger.Warn(fmt.Sprintf("Booking %d is unassigned with pickup at %s", booking.ID, pickups[i].Format(time.RFC3339)))
+			alerts = append(alerts, booking)
+		}
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	if !s.notifier.Emails() || s.config.DispatchEmail == "" {
+		s.logger.Warn(fmt.Sprintf("No dispatch email configured - %d unassigned alerts only logged", len(alerts)))
+		return nil
+	}
+	if err := s.notifier.UnassignedAlert(ctx, s.config.DispatchEmail, alerts); err != nil {
+		return fmt.Errorf("failed to email unassigned alert: %w", err)
+	}
+	return nil
+}
+
+// upcoming returns open bookings whose pickup is still ahead and at most within from now, with
+// their pickup times
+func (s *Service) upcoming(ctx context.Context, now time.Time, within time.Duration) ([]*models.BookRide, []time.Time, error) {
+	// Dates are local to each zone, so the range is widened by a day on both sides
+	from := now.AddDate(0, 0, -1).Format(dateLayout)
+	to := now.Add(within).AddDate(0, 0, 1).Format(dateLayout)
+	bookings, err := s.bookings.GetUpcoming(ctx, from, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load upcoming bookings: %w", err)
+	}
+	locations, err := s.zoneLocations(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matched []*models.BookRide
+	var pickups []time.Time
+	for _, booking := range bookings {
+		loc := locations.fallback
+		if booking.PickupZoneID != nil {
+			if zoneLoc, ok := locations.byID[*booking.PickupZoneID]; ok {
+				loc = zoneLoc
+			}
+		}
+		pickupAt, err := booking.PickupAt(loc)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("Skipping booking %d with invalid pickup time: %s", booking.ID, err.Error()))
+			continue
+		}
+		if !pickupAt.After(now) || pickupAt.After(now.Add(within)) {
+			continue
+		}
+		matched = append(matched, booking)
+		pickups = append(pickups, pickupAt)
+	}
+	return matched, pickups, nil
+}
+
+// zoneTimes are the time zones of the service zones, with the default zone's for unzoned bookings
+type zoneTimes struct {
+	byID     map[int64]*time.Location
+	fallback *time.Location
+}
+
+func (s *Service) zoneLocations(ctx context.Context) (*zoneTimes, error) {
+	zones, err := s.zones.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load service zones: %w", err)
+	}
+	locations := &zoneTimes{byID: make(map[int64]*time.Location), fallback: time.UTC}
+	for _, zone := range zones {
+		locations.byID[zone.ID] = zone.Location()
+		if zone.Code == models.DefaultZoneCode {
+			locations.fallback = zone.Location()
+		}
+	}
+	return locations, nil
+}
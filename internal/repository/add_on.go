@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type AddOnRepository interface {
+	List(ctx context.Context) ([]*models.AddOn, error)
+	ListActive(ctx context.Context) ([]*models.AddOn, error)
+	GetByCode(ctx context.Context, code string) (*models.AddOn, error)
+	Upsert(ctx context.Context, addOn *models.AddOn) error
+	Delete(ctx context.Context, code string) error
+}
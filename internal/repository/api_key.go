@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	GetByID(ctx context.Context, id int64) (*models.APIKey, error)
+	GetPrincipal(ctx context.Context, prefix string) (*models.APIKeyPrincipal, error)
+	List(ctx context.Context, orgID int64) ([]*models.APIKey, error)
+	Update(ctx context.Context, key *models.APIKey) error
+	Rotate(ctx context.Context, oldID int64, replacement *models.APIKey, graceUntil time.Time) error
+	Revoke(ctx context.Context, id int64) error
+	TouchLastUsed(ctx context.Context, id int64) error
+}
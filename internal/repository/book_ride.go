@@ -9,9 +9,26 @@ type BookRideRepository interface {
 	Create(ctx context.Context, br *models.BookRide) error
 	GetByID(ctx context.Context, id int64) (*models.BookRide, error)
 	GetByUserID(ctx context.Context, userID int64) ([]*models.BookRide, error)
+	GetByDriverID(ctx context.Context, driverID int64) ([]*models.BookRide, error)
 	GetByEmail(ctx context.Context, email string) ([]*models.BookRide, error)
 	Accept(ctx context.Context, id int64, driverID int64) error
 	Update(ctx context.Context, id int64, updates *models.UpdateBookRideRequest) error
 	Cancel(ctx context.Context, id int64, reason string) error
+	CancelWithFee(ctx context.Context, id int64, reason string, fee models.Money) error
 	GetByIDAndEmail(ctx context.Context, id int64, email string) (*models.BookRide, error)
+	UpdateQuote(ctx context.Context, id int64, items models.LineItems) error
+	Start(ctx context.Context, id int64, driverID int64) error
+	Complete(ctx context.Context, id int64, driverID int64, finalItems models.LineItems, actualMinutes *int) error
+	AdjustFare(ctx context.Context, adj *models.FareAdjustment) error
+	GetFareAdjustments(ctx context.Context, bookingID int64) ([]*models.FareAdjustment, error)
+	UpdateZones(ctx context.Context, id int64, pickupZoneID, dropoffZoneID *int64, requiresApproval bool, reason string) error
+	Approve(ctx context.Context, id int64, adminID int64) error
+	GetPendingApproval(ctx context.Context) ([]*models.BookRide, error)
+	LinkReturn(ctx context.Context, outboundID, returnID int64) error
+	GetTrackedFlights(ctx context.Context) ([]*models.BookRide, error)
+	UpdateFlight(ctx context.Context, id int64, flight *models.FlightDetails, date, time string) error
+	GetBySeriesID(ctx context.Context, seriesID int64) ([]*models.BookRide, error)
+	GetUpcoming(ctx context.Context, fromDate, toDate string) ([]*models.BookRide, error)
+	SetPaymentIssue(ctx context.Context, id int64, issue string) error
+	GetPaymentIssues(ctx context.Context) ([]*models.BookRide, error)
 }
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"time"
+)
+
+type BookingEventRepository interface {
+	Create(ctx context.Context, event *models.BookingEvent) error
+	GetSince(ctx context.Context, afterID int64, userID, driverID *int64, limit int) ([]*models.BookingEvent, error)
+	GetLatestID(ctx context.Context) (int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
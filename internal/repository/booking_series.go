@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type BookingSeriesRepository interface {
+	Create(ctx context.Context, series *models.BookingSeries) error
+	GetByID(ctx context.Context, id int64) (*models.BookingSeries, error)
+	GetByUserID(ctx context.Context, userID int64) ([]*models.BookingSeries, error)
+	ListActive(ctx context.Context) ([]*models.BookingSeries, error)
+	Update(ctx context.Context, series *models.BookingSeries) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type CancellationPolicyRepository interface {
+	// GetForRideType returns the policy of a ride type, falling back to the default policy
+	GetForRideType(ctx context.Context, rideType string) (*models.CancellationPolicy, error)
+	List(ctx context.Context) ([]*models.CancellationPolicy, error)
+	Upsert(ctx context.Context, policy *models.CancellationPolicy) error
+	Delete(ctx context.Context, rideType string) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"time"
+)
+
+type DriverLocationRepository interface {
+	Insert(ctx context.Context, locations []models.DriverLocation) error
+	GetRecent(ctx context.Context, bookingID int64, since time.Time) ([]*models.DriverLocation, error)
+	GetLatest(ctx context.Context, bookingID int64) (*models.DriverLocation, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
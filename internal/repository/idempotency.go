@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"time"
+)
+
+type IdempotencyKeyRepository interface {
+	Claim(ctx context.Context, record *models.IdempotencyKey, staleAfter time.Duration) (bool, error)
+	Get(ctx context.Context, principal, key string) (*models.IdempotencyKey, error)
+	SaveResponse(ctx context.Context, record *models.IdempotencyKey) error
+	Release(ctx context.Context, id int64) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"time"
+)
+
+type JobLeaseRepository interface {
+	Register(ctx context.Context, name, schedule string, firstRunAt time.Time) error
+	Acquire(ctx context.Context, name, holder string, leasedUntil time.Time) (bool, error)
+	Release(ctx context.Context, name, holder string, nextRunAt time.Time, lastError string) error
+	List(ctx context.Context) ([]*models.JobLease, error)
+}
+
+type BookingNoticeRepository interface {
+	Claim(ctx context.Context, bookingID int64, kinds ...string) (bool, error)
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"time"
+)
+
+type LedgerRepository interface {
+	Post(ctx context.Context, txn *models.LedgerTransaction) error
+	GetDriverEntries(ctx context.Context, driverID int64, from, to time.Time) ([]*models.LedgerEntry, error)
+	GetDriverBalance(ctx context.Context, driverID int64) (models.Money, error)
+	GetPayableBalances(ctx context.Context, before time.Time) (map[int64]models.Money, error)
+
+	CreatePayoutBatch(ctx context.Context, batch *models.PayoutBatch, postings []*models.LedgerTransaction) error
+	GetPayoutBatches(ctx context.Context) ([]*models.PayoutBatch, error)
+	GetPayoutBatchByID(ctx context.Context, id int64) (*models.PayoutBatch, error)
+	GetPayoutBatchByPeriod(ctx context.Context, periodStart time.Time) (*models.PayoutBatch, error)
+	GetLatestPayoutBatch(ctx context.Context) (*models.PayoutBatch, error)
+	GetPayoutsByBatchID(ctx context.Context, batchID int64) ([]*models.Payout, error)
+	GetPayoutsByDriverID(ctx context.Context, driverID int64) ([]*models.Payout, error)
+	GetPayoutByID(ctx context.Context, id int64) (*models.Payout, error)
+	SettlePayout(ctx context.Context, payout *models.Payout, posting *models.LedgerTransaction) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type LegalEntityRepository interface {
+	List(ctx context.Context) ([]*models.LegalEntity, error)
+	GetByID(ctx context.Context, id int64) (*models.LegalEntity, error)
+	GetByCode(ctx context.Context, code string) (*models.LegalEntity, error)
+	Upsert(ctx context.Context, entity *models.LegalEntity) error
+}
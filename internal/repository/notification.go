@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type NotificationPreferenceRepository interface {
+	Get(ctx context.Context, userID int64) (*models.NotificationPreferences, error)
+	GetByEmail(ctx context.Context, email string) (*models.NotificationPreferences, error)
+	Upsert(ctx context.Context, prefs *models.NotificationPreferences) error
+}
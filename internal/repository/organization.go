@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"time"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *models.Organization) error
+	Update(ctx context.Context, org *models.Organization) error
+	GetByID(ctx context.Context, id int64) (*models.Organization, error)
+	List(ctx context.Context) ([]*models.Organization, error)
+	ListActive(ctx context.Context) ([]*models.Organization, error)
+
+	GetMembers(ctx context.Context, orgID int64) ([]*models.OrganizationMember, error)
+	GetMemberByUserID(ctx context.Context, userID int64) (*models.OrganizationMember, error)
+	AddMember(ctx context.Context, member *models.OrganizationMember) error
+	UpdateMember(ctx context.Context, member *models.OrganizationMember) error
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+
+	GetCostCenters(ctx context.Context, orgID int64) ([]*models.CostCenter, error)
+	GetCostCenterByCode(ctx context.Context, orgID int64, code string) (*models.CostCenter, error)
+	UpsertCostCenter(ctx context.Context, costCenter *models.CostCenter) error
+
+	GetBookings(ctx context.Context, orgID int64, approvalStatus string) ([]*models.BookRide, error)
+	DecideBooking(ctx context.Context, orgID, bookingID int64, status string, decidedBy int64) error
+	RequireBookingApproval(ctx context.Context, bookingID int64) error
+
+	GetUninvoicedBookings(ctx context.Context, orgID int64, before time.Time) ([]*models.BookRide, error)
+	CreateInvoice(ctx context.Context, invoice *models.OrganizationInvoice) error
+	GetInvoices(ctx context.Context, orgID int64) ([]*models.OrganizationInvoice, error)
+	GetInvoiceByID(ctx context.Context, orgID, id int64) (*models.OrganizationInvoice, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, p *models.Payment) error
+	GetByID(ctx context.Context, id int64) (*models.Payment, error)
+	GetByProviderRef(ctx context.Context, provider, providerRef string) (*models.Payment, error)
+	GetActiveByBookingID(ctx context.Context, bookingID int64) (*models.Payment, error)
+	GetByBookingID(ctx context.Context, bookingID int64) ([]*models.Payment, error)
+	Update(ctx context.Context, p *models.Payment) error
+	ApplyEvent(ctx context.Context, event *models.PaymentEvent, p *models.Payment, fromStatus string) (bool, error)
+	RecordRefund(ctx context.Context, p *models.Payment, refund *models.PaymentRefund) error
+	GetRefundsByBookingID(ctx context.Context, bookingID int64) ([]*models.PaymentRefund, error)
+}
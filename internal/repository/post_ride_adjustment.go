@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type PostRideAdjustmentRepository interface {
+	Create(ctx context.Context, adj *models.PostRideAdjustment) error
+	GetByID(ctx context.Context, id int64) (*models.PostRideAdjustment, error)
+	GetByBookingID(ctx context.Context, bookingID int64) ([]*models.PostRideAdjustment, error)
+	GetPending(ctx context.Context) ([]*models.PostRideAdjustment, error)
+	Reject(ctx context.Context, id int64, decidedBy *int64) error
+	Claim(ctx context.Context, id int64, decidedBy *int64) error
+	MarkFailed(ctx context.Context, id int64, decidedBy *int64, paymentID *int64, reason string) error
+	Apply(ctx context.Context, adj *models.PostRideAdjustment, decidedBy *int64, paymentID *int64, item models.LineItem) error
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type addOnRepository struct {
+	db *sqlx.DB
+}
+
+func NewAddOnRepository(db *sqlx.DB) repository.AddOnRepository {
+	return &addOnRepository{db: db}
+}
+
+func (r *addOnRepository) List(ctx context.Context) ([]*models.AddOn, error) {
+	var addOns []*models.AddOn
+	query := `SELECT * FROM add_ons ORDER BY code`
+	if err := r.db.SelectContext(ctx, &addOns, query); err != nil {
+		return nil, err
+	}
+	return addOns, nil
+}
+
+func (r *addOnRepository) ListActive(ctx context.Context) ([]*models.AddOn, error) {
+	var addOns []*models.AddOn
+	query := `SELECT * FROM add_ons WHERE active = TRUE ORDER BY code`
+	if err := r.db.SelectContext(ctx, &addOns, query); err != nil {
+		return nil, err
+	}
+	return addOns, nil
+}
+
+func (r *addOnRepository) GetByCode(ctx context.Context, code string) (*models.AddOn, error) {
+	addOn := &models.AddOn{}
+	query := `SELECT * FROM add_ons WHERE code = $1`
+	if err := r.db.GetContext(ctx, addOn, query, code); err != nil {
+		return nil, err
+	}
+	return addOn, nil
+}
+
+func (r *addOnRepository) Upsert(ctx context.Context, addOn *models.AddOn) error {
+	query := `
+        INSERT INTO add_ons (code, name, description, price, max_quantity, ride_types, active, created_at, updated_at)
+        VALUES (:code, :name, :description, :price, :max_quantity, :ride_types, :active, NOW(), NOW())
+        ON CONFLICT (code) DO UPDATE
+        SET name = EXCLUDED.name,
+            description = EXCLUDED.description,
+            price = EXCLUDED.price,
+            max_quantity = EXCLUDED.max_quantity,
+            ride_types = EXCLUDED.ride_types,
+            active = EXCLUDED.active,
+            updated_at = NOW()
+        RETURNING id, created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, addOn)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&addOn.ID, &addOn.CreatedAt, &addOn.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+func (r *addOnRepository) Delete(ctx context.Context, code string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM add_ons WHERE code = $1`, code)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type apiKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) repository.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return createAPIKey(ctx, r.db, key)
+}
+
+// createAPIKey inserts a key through a connection or a transaction
+func createAPIKey(ctx context.Context, q sqlx.QueryerContext, key *models.APIKey) error {
+	query := `
+        INSERT INTO api_keys (organization_id, user_id, name, prefix, key_hash, scopes, rate_limit_per_minute,
+                              expires_at, created_by, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	return q.QueryRowxContext(ctx, query, key.OrganizationID, key.UserID, key.Name, key.Prefix, key.KeyHash,
+		key.Scopes, key.RateLimitPerMinute, key.ExpiresAt, key.CreatedBy).Scan(&key.ID, &key.CreatedAt, &key.UpdatedAt)
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id int64) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	if err := r.db.GetContext(ctx, key, `SELECT * FROM api_keys WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetPrincipal returns the key with a prefix together with the user it acts as and that user's
+// current organization
+func (r *apiKeyRepository) GetPrincipal(ctx context.Context, prefix string) (*models.APIKeyPrincipal, error) {
+	principal := &models.APIKeyPrincipal{}
+	query := `
+        SELECT k.*, u.username, u.email, m.organization_id AS member_organization_id
+        FROM api_keys k
+        JOIN users u ON u.id = k.user_id
+        LEFT JOIN organization_members m ON m.user_id = k.user_id
+        WHERE k.prefix = $1
+    `
+	if err := r.db.GetContext(ctx, principal, query, prefix); err != nil {
+		return nil, err
+	}
+	return principal, nil
+}
+
+// List returns the keys of an organization, or of every organization when orgID is 0, newest first
+func (r *apiKeyRepository) List(ctx context.Context, orgID int64) ([]*models.APIKey, error) {
+	var keys []*models.APIKey
+	query := `SELECT * FROM api_keys WHERE ($1 = 0 OR organization_id = $1) ORDER BY id DESC`
+	if err := r.db.SelectContext(ctx, &keys, query, orgID); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Update(ctx context.Context, key *models.APIKey) error {
+	query := `
+        UPDATE api_keys
+        SET name = $1, scopes = $2, rate_limit_per_minute = $3, expires_at = $4, updated_at = NOW()
+        WHERE id = $5
+        RETURNING updated_at
+    `
+	return r.db.QueryRowxContext(ctx, query, key.Name, key.Scopes, key.RateLimitPerMinute, key.ExpiresAt,
+		key.ID).Scan(&key.UpdatedAt)
+}
+
+// Rotate issues a replacement key and makes the old one expire at graceUntil, unless it expires sooner
+func (r *apiKeyRepository) Rotate(ctx context.Context, oldID int64, replacement *models.APIKey, graceUntil time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := createAPIKey(ctx, tx, replacement); err != nil {
+		return err
+	}
+	query := `
+        UPDATE api_keys
+        SET expires_at = LEAST(COALESCE(expires_at, $1), $1), replaced_by = $2, updated_at = NOW()
+        WHERE id = $3 AND revoked_at IS NULL AND replaced_by IS NULL
+    `
+	result, err := tx.ExecContext(ctx, query, graceUntil, replacement.ID, oldID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id int64) error {
+	query := `UPDATE api_keys SET revoked_at = NOW(), updated_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TouchLastUsed records that a key was used, at most once a minute to spare writes on busy keys
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id int64) error {
+	query := `
+        UPDATE api_keys SET last_used_at = NOW()
+        WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+    `
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/diagnosis/luxsuv-v4/internal/models"
 	"github.com/diagnosis/luxsuv-v4/internal/repository"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"strings"
 )
 
@@ -21,21 +22,87 @@ func NewBookRideRepository(db *sqlx.DB) repository.BookRideRepository {
 
 func (r *bookRideRepository) Create(ctx context.Context, br *models.BookRide) error {
 	query := `
-        INSERT INTO book_rides (user_id, driver_id, your_name, email, phone_number, ride_type, pickup_location, dropoff_location, 
-                                date, time, number_of_passengers, number_of_luggage, additional_notes, book_status, ride_status, created_at, updated_at)
-        VALUES (:user_id, :driver_id, :your_name, :email, :phone_number, :ride_type, :pickup_location, :dropoff_location, 
-                :date, :time, :number_of_passengers, :number_of_luggage, :additional_notes, :book_status, :ride_status, NOW(), NOW())
+        INSERT INTO book_rides (user_id, driver_id, your_name, email, phone_number, ride_type, booking_mode, duration_hours, pickup_location, dropoff_location, 
+                                pickup_place, dropoff_place, flight, add_ons, date, time, number_of_passengers, number_of_luggage, additional_notes, book_status, ride_status,
+                                quoted_amount, currency, line_items, pickup_zone_id, dropoff_zone_id, requires_approval, approval_reason,
+                                outbound_booking_id, series_id, occurrence_date, organization_id, cost_center_id, org_approval_status,
+                                passenger_name, passenger_email, passenger_phone, notifications, created_at, updated_at)
+        VALUES (:user_id, :driver_id, :your_name, :email, :phone_number, :ride_type, :booking_mode, :duration_hours, :pickup_location, :dropoff_location, 
+                :pickup_place, :dropoff_place, :flight, :add_ons, :date, :time, :number_of_passengers, :number_of_luggage, :additional_notes, :book_status, :ride_status,
+                :quoted_amount, :currency, :line_items, :pickup_zone_id, :dropoff_zone_id, :requires_approval, :approval_reason,
+                :outbound_booking_id, :series_id, :occurrence_date, :organization_id, :cost_center_id, COALESCE(NULLIF(:org_approval_status, ''), 'not_required'),
+                :passenger_name, :passenger_email, :passenger_phone, :notifications, NOW(), NOW())
         RETURNING id
     `
-	rows, err := r.db.NamedQueryContext(ctx, query, br)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
-	if rows.Next() {
-		return rows.Scan(&br.ID)
+	defer tx.Rollback()
+
+	rows, err := sqlx.NamedQueryContext(ctx, tx, query, br)
+	if err != nil {
+		return err
+	}
+	if !rows.Next() {
+		rows.Close()
+		return sql.ErrNoRows
+	}
+	if err := rows.Scan(&br.ID); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	if err := replaceStops(ctx, tx, br.ID, br.Stops); err != nil {
+		return err
 	}
-	return sql.ErrNoRows
+	return tx.Commit()
+}
+
+// replaceStops replaces the intermediate stops of a booking within a transaction
+func replaceStops(ctx context.Context, tx *sqlx.Tx, bookingID int64, stops []models.BookingStop) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM booking_stops WHERE booking_id = $1`, bookingID); err != nil {
+		return err
+	}
+	insert := `
+        INSERT INTO booking_stops (booking_id, position, address, place)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at
+    `
+	for i := range stops {
+		stop := &stops[i]
+		stop.BookingID = bookingID
+		stop.Position = i + 1
+		if err := tx.QueryRowxContext(ctx, insert, bookingID, stop.Position, stop.Address, stop.Place).Scan(&stop.ID, &stop.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachStops loads the intermediate stops of the given bookings
+func (r *bookRideRepository) attachStops(ctx context.Context, bookings ...*models.BookRide) error {
+	if len(bookings) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(bookings))
+	byID := make(map[int64]*models.BookRide, len(bookings))
+	for i, br := range bookings {
+		ids[i] = br.ID
+		byID[br.ID] = br
+	}
+
+	var stops []models.BookingStop
+	query := `SELECT * FROM booking_stops WHERE booking_id = ANY($1) ORDER BY booking_id, position`
+	if err := r.db.SelectContext(ctx, &stops, query, pq.Array(ids)); err != nil {
+		return err
+	}
+	for _, stop := range stops {
+		br := byID[stop.BookingID]
+		br.Stops = append(br.Stops, stop)
+	}
+	return nil
 }
 
 func (r *bookRideRepository) GetByID(ctx context.Context, id int64) (*models.BookRide, error) {
@@ -45,6 +112,9 @@ func (r *bookRideRepository) GetByID(ctx context.Context, id int64) (*models.Boo
 	if err != nil {
 		return nil, err
 	}
+	if err := r.attachStops(ctx, br); err != nil {
+		return nil, err
+	}
 	return br, nil
 }
 
@@ -55,6 +125,22 @@ func (r *bookRideRepository) GetByUserID(ctx context.Context, userID int64) ([]*
 	if err != nil {
 		return nil, err
 	}
+	if err := r.attachStops(ctx, bookings...); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+func (r *bookRideRepository) GetByDriverID(ctx context.Context, driverID int64) ([]*models.BookRide, error) {
+	var bookings []*models.BookRide
+	query := `SELECT * FROM book_rides WHERE driver_id = $1 ORDER BY date DESC, time DESC`
+	err := r.db.SelectContext(ctx, &bookings, query, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachStops(ctx, bookings...); err != nil {
+		return nil, err
+	}
 	return bookings, nil
 }
 
@@ -65,6 +151,9 @@ func (r *bookRideRepository) GetByEmail(ctx context.Context, email string) ([]*m
 	if err != nil {
 		return nil, err
 	}
+	if err := r.attachStops(ctx, bookings...); err != nil {
+		return nil, err
+	}
 	return bookings, nil
 }
 
@@ -73,6 +162,8 @@ func (r *bookRideRepository) Accept(ctx context.Context, id int64, driverID int6
         UPDATE book_rides 
         SET driver_id = $1, book_status = 'Accepted', ride_status = 'Assigned', updated_at = NOW()
         WHERE id = $2 AND book_status = 'Pending' AND driver_id IS NULL
+          AND (requires_approval = FALSE OR approved_at IS NOT NULL)
+          AND org_approval_status IN ('not_required', 'approved')
     `
 	result, err := r.db.ExecContext(ctx, query, driverID, id)
 	if err != nil {
@@ -80,7 +171,7 @@ func (r *bookRideRepository) Accept(ctx context.Context, id int64, driverID int6
 	}
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return errors.New("booking not found, already assigned, or not pending")
+		return errors.New("booking not found, already assigned, not pending, or awaiting approval")
 	}
 	return nil
 }
@@ -102,6 +193,18 @@ func (r *bookRideRepository) Update(ctx context.Context, id int64, updates *mode
 		argIndex++
 	}
 
+	if updates.PassengerName != nil {
+		setParts = append(setParts, fmt.Sprintf("passenger_name = $%d, passenger_email = $%d, passenger_phone = $%d", argIndex, argIndex+1, argIndex+2))
+		args = append(args, *updates.PassengerName, *updates.PassengerEmail, *updates.PassengerPhone)
+		argIndex += 3
+	}
+
+	if updates.Notifications != nil {
+		setParts = append(setParts, fmt.Sprintf("notifications = $%d", argIndex))
+		args = append(args, updates.Notifications)
+		argIndex++
+	}
+
 	if updates.RideType != "" {
 		setParts = append(setParts, fmt.Sprintf("ride_type = $%d", argIndex))
 		args = append(args, updates.RideType)
@@ -120,6 +223,30 @@ func (r *bookRideRepository) Update(ctx context.Context, id int64, updates *mode
 		argIndex++
 	}
 
+	if updates.Pickup != nil {
+		setParts = append(setParts, fmt.Sprintf("pickup_place = $%d", argIndex))
+		args = append(args, updates.Pickup)
+		argIndex++
+	}
+
+	if updates.Dropoff != nil {
+		setParts = append(setParts, fmt.Sprintf("dropoff_place = $%d", argIndex))
+		args = append(args, updates.Dropoff)
+		argIndex++
+	}
+
+	if updates.Flight != nil {
+		setParts = append(setParts, fmt.Sprintf("flight = $%d", argIndex))
+		args = append(args, updates.Flight)
+		argIndex++
+	}
+
+	if updates.AddOns != nil {
+		setParts = append(setParts, fmt.Sprintf("add_ons = $%d", argIndex))
+		args = append(args, *updates.AddOns)
+		argIndex++
+	}
+
 	if updates.Date != "" {
 		setParts = append(setParts, fmt.Sprintf("date = $%d", argIndex))
 		args = append(args, updates.Date)
@@ -138,6 +265,12 @@ func (r *bookRideRepository) Update(ctx context.Context, id int64, updates *mode
 		argIndex++
 	}
 
+	if updates.DurationHours != nil {
+		setParts = append(setParts, fmt.Sprintf("duration_hours = $%d", argIndex))
+		args = append(args, *updates.DurationHours)
+		argIndex++
+	}
+
 	if updates.NumberOfLuggage != nil {
 		setParts = append(setParts, fmt.Sprintf("number_of_luggage = $%d", argIndex))
 		args = append(args, *updates.NumberOfLuggage)
@@ -164,7 +297,13 @@ func (r *bookRideRepository) Update(ctx context.Context, id int64, updates *mode
 
 	args = append(args, id)
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, args...)
 	if err != nil {
 		return err
 	}
@@ -174,7 +313,13 @@ func (r *bookRideRepository) Update(ctx context.Context, id int64, updates *mode
 		return errors.New("booking not found or cannot be updated (may be cancelled or completed)")
 	}
 
-	return nil
+	if updates.Stops != nil {
+		if err := replaceStops(ctx, tx, id, *updates.Stops); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (r *bookRideRepository) Cancel(ctx context.Context, id int64, reason string) error {
@@ -198,6 +343,29 @@ func (r *bookRideRepository) Cancel(ctx context.Context, id int64, reason string
 	return nil
 }
 
+// CancelWithFee cancels a booking and records the cancellation fee charged under the policy
+func (r *bookRideRepository) CancelWithFee(ctx context.Context, id int64, reason string, fee models.Money) error {
+	query := `
+        UPDATE book_rides 
+        SET book_status = 'Cancelled', ride_status = 'Cancelled', additional_notes = COALESCE(additional_notes, '') || $1,
+            cancellation_fee = $2, updated_at = NOW()
+        WHERE id = $3 AND book_status NOT IN ('Cancelled', 'Completed')
+    `
+
+	cancelNote := fmt.Sprintf("\n[CANCELLED: %s]", reason)
+	result, err := r.db.ExecContext(ctx, query, cancelNote, fee, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found or cannot be cancelled (may already be cancelled or completed)")
+	}
+
+	return nil
+}
+
 func (r *bookRideRepository) GetByIDAndEmail(ctx context.Context, id int64, email string) (*models.BookRide, error) {
 	br := &models.BookRide{}
 	query := `SELECT * FROM book_rides WHERE id = $1 AND email = $2`
@@ -205,5 +373,308 @@ func (r *bookRideRepository) GetByIDAndEmail(ctx context.Context, id int64, emai
 	if err != nil {
 		return nil, err
 	}
+	if err := r.attachStops(ctx, br); err != nil {
+		return nil, err
+	}
 	return br, nil
-}
\ No newline at end of file
+}
+
+func (r *bookRideRepository) UpdateQuote(ctx context.Context, id int64, items models.LineItems) error {
+	query := `
+        UPDATE book_rides 
+        SET quoted_amount = $1, line_items = $2, updated_at = NOW()
+        WHERE id = $3 AND book_status NOT IN ('Cancelled', 'Completed')
+    `
+	result, err := r.db.ExecContext(ctx, query, items.Total(), items, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found or quote can no longer be changed")
+	}
+	return nil
+}
+
+// Start marks an assigned ride as in progress once the driver has picked up the passenger
+func (r *bookRideRepository) Start(ctx context.Context, id int64, driverID int64) error {
+	query := `
+        UPDATE book_rides
+        SET ride_status = 'In Progress', updated_at = NOW()
+        WHERE id = $1 AND driver_id = $2 AND book_status = 'Accepted' AND ride_status = 'Assigned'
+    `
+	result, err := r.db.ExecContext(ctx, query, id, driverID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found, not assigned to this driver, or already started")
+	}
+	return nil
+}
+
+func (r *bookRideRepository) Complete(ctx context.Context, id int64, driverID int64, finalItems models.LineItems, actualMinutes *int) error {
+	query := `
+        UPDATE book_rides 
+        SET book_status = 'Completed', ride_status = 'Completed', final_amount = $1, final_line_items = $2,
+            actual_minutes = $3, updated_at = NOW()
+        WHERE id = $4 AND driver_id = $5 AND book_status = 'Accepted'
+    `
+	result, err := r.db.ExecContext(ctx, query, finalItems.Total(), finalItems, actualMinutes, id, driverID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found, not assigned to this driver, or not in progress")
+	}
+	return nil
+}
+
+// AdjustFare overrides the final fare (or the quote, if the ride is not completed yet)
+// and records the change in booking_fare_adjustments within a single transaction
+func (r *bookRideRepository) AdjustFare(ctx context.Context, adj *models.FareAdjustment) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current struct {
+		QuotedAmount   models.Money     `db:"quoted_amount"`
+		FinalAmount    *models.Money    `db:"final_amount"`
+		Currency       string           `db:"currency"`
+		LineItems      models.LineItems `db:"line_items"`
+		FinalLineItems models.LineItems `db:"final_line_items"`
+	}
+	query := `SELECT quoted_amount, final_amount, currency, line_items, final_line_items FROM book_rides WHERE id = $1 FOR UPDATE`
+	if err := tx.GetContext(ctx, &current, query, adj.BookingID); err != nil {
+		return err
+	}
+
+	adj.Currency = current.Currency
+	items := current.LineItems
+	if current.FinalAmount != nil {
+		adj.Field = "final_amount"
+		adj.PreviousAmount = *current.FinalAmount
+		items = current.FinalLineItems
+	} else {
+		adj.Field = "quoted_amount"
+		adj.PreviousAmount = current.QuotedAmount
+	}
+
+	// Keep the line items summing to the stored amount
+	items = append(items, models.LineItem{
+		Code:        models.LineItemAdjustment,
+		Description: adj.Reason,
+		Quantity:    1,
+		UnitAmount:  adj.NewAmount - adj.PreviousAmount,
+		Amount:      adj.NewAmount - adj.PreviousAmount,
+	})
+
+	itemsColumn := "line_items"
+	if adj.Field == "final_amount" {
+		itemsColumn = "final_line_items"
+	}
+	update := fmt.Sprintf(`UPDATE book_rides SET %s = $1, %s = $2, updated_at = NOW() WHERE id = $3`, adj.Field, itemsColumn)
+	if _, err := tx.ExecContext(ctx, update, adj.NewAmount, items, adj.BookingID); err != nil {
+		return err
+	}
+
+	insert := `
+        INSERT INTO booking_fare_adjustments (booking_id, admin_id, field, previous_amount, new_amount, currency, reason)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        RETURNING id, created_at
+    `
+	if err := tx.QueryRowxContext(ctx, insert, adj.BookingID, adj.AdminID, adj.Field, adj.PreviousAmount,
+		adj.NewAmount, adj.Currency, adj.Reason).Scan(&adj.ID, &adj.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *bookRideRepository) GetFareAdjustments(ctx context.Context, bookingID int64) ([]*models.FareAdjustment, error) {
+	var adjustments []*models.FareAdjustment
+	query := `SELECT * FROM booking_fare_adjustments WHERE booking_id = $1 ORDER BY created_at ASC`
+	err := r.db.SelectContext(ctx, &adjustments, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+// UpdateZones records the zones a booking resolved to after its route changed.
+// A route that needs approval must be approved again.
+func (r *bookRideRepository) UpdateZones(ctx context.Context, id int64, pickupZoneID, dropoffZoneID *int64, requiresApproval bool, reason string) error {
+	query := `
+        UPDATE book_rides 
+        SET pickup_zone_id = $1, dropoff_zone_id = $2, requires_approval = $3, approval_reason = $4,
+            approved_by = NULL, approved_at = NULL, updated_at = NOW()
+        WHERE id = $5 AND book_status NOT IN ('Cancelled', 'Completed')
+    `
+	result, err := r.db.ExecContext(ctx, query, pickupZoneID, dropoffZoneID, requiresApproval, reason, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found or cannot be updated (may be cancelled or completed)")
+	}
+	return nil
+}
+
+func (r *bookRideRepository) Approve(ctx context.Context, id int64, adminID int64) error {
+	query := `
+        UPDATE book_rides 
+        SET approved_by = $1, approved_at = NOW(), updated_at = NOW()
+        WHERE id = $2 AND requires_approval = TRUE AND approved_at IS NULL AND book_status NOT IN ('Cancelled', 'Completed')
+    `
+	result, err := r.db.ExecContext(ctx, query, adminID, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found, already approved, or not awaiting approval")
+	}
+	return nil
+}
+
+func (r *bookRideRepository) GetPendingApproval(ctx context.Context) ([]*models.BookRide, error) {
+	var bookings []*models.BookRide
+	query := `
+        SELECT * FROM book_rides 
+        WHERE requires_approval = TRUE AND approved_at IS NULL AND book_status NOT IN ('Cancelled', 'Completed')
+        ORDER BY date ASC, time ASC
+    `
+	err := r.db.SelectContext(ctx, &bookings, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachStops(ctx, bookings...); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// LinkReturn records the return leg of an outbound booking
+func (r *bookRideRepository) LinkReturn(ctx context.Context, outboundID, returnID int64) error {
+	query := `
+        UPDATE book_rides 
+        SET return_booking_id = $1, updated_at = NOW()
+        WHERE id = $2
+    `
+	result, err := r.db.ExecContext(ctx, query, returnID, outboundID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("outbound booking not found")
+	}
+	return nil
+}
+
+// GetTrackedFlights returns open bookings whose inbound flight has not landed or been cancelled
+func (r *bookRideRepository) GetTrackedFlights(ctx context.Context) ([]*models.BookRide, error) {
+	var bookings []*models.BookRide
+	query := `
+        SELECT * FROM book_rides 
+        WHERE flight IS NOT NULL AND book_status IN ('Pending', 'Accepted')
+          AND COALESCE(flight->>'status', '') NOT IN ('landed', 'cancelled')
+        ORDER BY date ASC, time ASC
+    `
+	err := r.db.SelectContext(ctx, &bookings, query)
+	if err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// UpdateFlight records the tracked status of a booking's flight and its (possibly shifted) pickup time
+func (r *bookRideRepository) UpdateFlight(ctx context.Context, id int64, flight *models.FlightDetails, date, time string) error {
+	query := `
+        UPDATE book_rides 
+        SET flight = $1, date = $2, time = $3, updated_at = NOW()
+        WHERE id = $4 AND book_status IN ('Pending', 'Accepted')
+    `
+	result, err := r.db.ExecContext(ctx, query, flight, date, time, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found or no longer open")
+	}
+	return nil
+}
+
+func (r *bookRideRepository) GetBySeriesID(ctx context.Context, seriesID int64) ([]*models.BookRide, error) {
+	var bookings []*models.BookRide
+	query := `SELECT * FROM book_rides WHERE series_id = $1 ORDER BY occurrence_date ASC`
+	err := r.db.SelectContext(ctx, &bookings, query, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachStops(ctx, bookings...); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// GetUpcoming returns open bookings with a pickup date between fromDate and toDate (YYYY-MM-DD, inclusive)
+func (r *bookRideRepository) GetUpcoming(ctx context.Context, fromDate, toDate string) ([]*models.BookRide, error) {
+	var bookings []*models.BookRide
+	query := `
+        SELECT * FROM book_rides
+        WHERE book_status IN ('Pending', 'Accepted') AND ride_status IN ('Pending', 'Assigned')
+          AND date BETWEEN $1 AND $2
+        ORDER BY date ASC, time ASC
+    `
+	err := r.db.SelectContext(ctx, &bookings, query, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachStops(ctx, bookings...); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// SetPaymentIssue flags a booking whose payment needs admin follow-up; an empty issue clears the flag
+func (r *bookRideRepository) SetPaymentIssue(ctx context.Context, id int64, issue string) error {
+	query := `
+        UPDATE book_rides
+        SET payment_issue = $1, updated_at = NOW()
+        WHERE id = $2
+    `
+	result, err := r.db.ExecContext(ctx, query, issue, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetPaymentIssues returns the bookings flagged with a payment issue, oldest change first
+func (r *bookRideRepository) GetPaymentIssues(ctx context.Context) ([]*models.BookRide, error) {
+	var bookings []*models.BookRide
+	query := `
+        SELECT * FROM book_rides
+        WHERE payment_issue <> ''
+        ORDER BY updated_at ASC
+    `
+	err := r.db.SelectContext(ctx, &bookings, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachStops(ctx, bookings...); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
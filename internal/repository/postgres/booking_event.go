@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type bookingEventRepository struct {
+	db *sqlx.DB
+}
+
+func NewBookingEventRepository(db *sqlx.DB) repository.BookingEventRepository {
+	return &bookingEventRepository{db: db}
+}
+
+// Create stores an event and notifies listening server instances once it is committed
+func (r *bookingEventRepository) Create(ctx context.Context, event *models.BookingEvent) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO booking_events (booking_id, type, user_id, driver_id, book_status, ride_status, data, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+        RETURNING id, created_at
+    `
+	if err := tx.QueryRowxContext(ctx, query, event.BookingID, event.Type, event.UserID, event.DriverID,
+		event.BookStatus, event.RideStatus, []byte(event.Booking)).Scan(&event.ID, &event.CreatedAt); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, models.BookingEventChannel, strconv.FormatInt(event.ID, 10)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetSince returns up to limit events after afterID, oldest first. A user or driver ID limits the
+// events to that rider's bookings or that driver's assignments; with neither, all events are returned.
+func (r *bookingEventRepository) GetSince(ctx context.Context, afterID int64, userID, driverID *int64, limit int) ([]*models.BookingEvent, error) {
+	var events []*models.BookingEvent
+	query := `
+        SELECT * FROM booking_events
+        WHERE id > $1
+          AND ($2::BIGINT IS NULL OR user_id = $2)
+          AND ($3::BIGINT IS NULL OR driver_id = $3)
+        ORDER BY id
+        LIMIT $4
+    `
+	err := r.db.SelectContext(ctx, &events, query, afterID, userID, driverID, limit)
+	return events, err
+}
+
+// GetLatestID returns the ID of the newest event, or 0 when there is none
+func (r *bookingEventRepository) GetLatestID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.db.GetContext(ctx, &id, `SELECT COALESCE(MAX(id), 0) FROM booking_events`)
+	return id, err
+}
+
+// DeleteBefore removes events created before the given time and returns how many were removed
+func (r *bookingEventRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM booking_events WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
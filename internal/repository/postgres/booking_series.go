@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type bookingSeriesRepository struct {
+	db *sqlx.DB
+}
+
+func NewBookingSeriesRepository(db *sqlx.DB) repository.BookingSeriesRepository {
+	return &bookingSeriesRepository{db: db}
+}
+
+func (r *bookingSeriesRepository) Create(ctx context.Context, series *models.BookingSeries) error {
+	query := `
+        INSERT INTO booking_series (user_id, recurrence, start_date, time, template, payment_method, status, created_at, updated_at)
+        VALUES (:user_id, :recurrence, :start_date, :time, :template, :payment_method, :status, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, series)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&series.ID, &series.CreatedAt, &series.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+func (r *bookingSeriesRepository) GetByID(ctx context.Context, id int64) (*models.BookingSeries, error) {
+	series := &models.BookingSeries{}
+	query := `SELECT * FROM booking_series WHERE id = $1`
+	if err := r.db.GetContext(ctx, series, query, id); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+func (r *bookingSeriesRepository) GetByUserID(ctx context.Context, userID int64) ([]*models.BookingSeries, error) {
+	var list []*models.BookingSeries
+	query := `SELECT * FROM booking_series WHERE user_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &list, query, userID); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *bookingSeriesRepository) ListActive(ctx context.Context) ([]*models.BookingSeries, error) {
+	var list []*models.BookingSeries
+	query := `SELECT * FROM booking_series WHERE status = 'active' ORDER BY id`
+	if err := r.db.SelectContext(ctx, &list, query); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *bookingSeriesRepository) Update(ctx context.Context, series *models.BookingSeries) error {
+	query := `
+        UPDATE booking_series 
+        SET time = $1, template = $2, status = $3, generated_until = $4, updated_at = NOW()
+        WHERE id = $5
+    `
+	result, err := r.db.ExecContext(ctx, query, series.Time, series.Template, series.Status, series.GeneratedUntil, series.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking series not found")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type cancellationPolicyRepository struct {
+	db *sqlx.DB
+}
+
+func NewCancellationPolicyRepository(db *sqlx.DB) repository.CancellationPolicyRepository {
+	return &cancellationPolicyRepository{db: db}
+}
+
+func (r *cancellationPolicyRepository) GetForRideType(ctx context.Context, rideType string) (*models.CancellationPolicy, error) {
+	policy := &models.CancellationPolicy{}
+	query := `
+        SELECT * FROM cancellation_policies
+        WHERE ride_type IN ($1, $2)
+        ORDER BY (ride_type = $2) ASC
+        LIMIT 1
+    `
+	err := r.db.GetContext(ctx, policy, query, strings.ToLower(strings.TrimSpace(rideType)), models.DefaultPolicyRideType)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (r *cancellationPolicyRepository) List(ctx context.Context) ([]*models.CancellationPolicy, error) {
+	var policies []*models.CancellationPolicy
+	query := `SELECT * FROM cancellation_policies ORDER BY ride_type`
+	if err := r.db.SelectContext(ctx, &policies, query); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (r *cancellationPolicyRepository) Upsert(ctx context.Context, policy *models.CancellationPolicy) error {
+	query := `
+        INSERT INTO cancellation_policies (ride_type, free_until_hours, late_until_hours, late_fee_percent,
+                                           no_show_fee_percent, updated_by, created_at, updated_at)
+        VALUES (:ride_type, :free_until_hours, :late_until_hours, :late_fee_percent,
+                :no_show_fee_percent, :updated_by, NOW(), NOW())
+        ON CONFLICT (ride_type) DO UPDATE
+        SET free_until_hours = EXCLUDED.free_until_hours,
+            late_until_hours = EXCLUDED.late_until_hours,
+            late_fee_percent = EXCLUDED.late_fee_percent,
+            no_show_fee_percent = EXCLUDED.no_show_fee_percent,
+            updated_by = EXCLUDED.updated_by,
+            updated_at = NOW()
+        RETURNING created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, policy)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&policy.CreatedAt, &policy.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+func (r *cancellationPolicyRepository) Delete(ctx context.Context, rideType string) error {
+	if rideType == models.DefaultPolicyRideType {
+		return errors.New("the default cancellation policy cannot be deleted")
+	}
+	result, err := r.db.ExecContext(ctx, `DELETE FROM cancellation_policies WHERE ride_type = $1`, rideType)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type driverLocationRepository struct {
+	db *sqlx.DB
+}
+
+func NewDriverLocationRepository(db *sqlx.DB) repository.DriverLocationRepository {
+	return &driverLocationRepository{db: db}
+}
+
+// Insert stores a batch of pings in one transaction
+func (r *driverLocationRepository) Insert(ctx context.Context, locations []models.DriverLocation) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO driver_locations (driver_id, booking_id, lat, lng, speed_kmh, heading, accuracy_m, recorded_at, received_at)
+        VALUES (:driver_id, :booking_id, :lat, :lng, :speed_kmh, :heading, :accuracy_m, :recorded_at, NOW())
+    `
+	for _, location := range locations {
+		if _, err := tx.NamedExecContext(ctx, query, location); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetRecent returns the pings of a ride recorded since the given time, oldest first
+func (r *driverLocationRepository) GetRecent(ctx context.Context, bookingID int64, since time.Time) ([]*models.DriverLocation, error) {
+	var locations []*models.DriverLocation
+	query := `
+        SELECT * FROM driver_locations
+        WHERE booking_id = $1 AND recorded_at >= $2
+        ORDER BY recorded_at
+    `
+	err := r.db.SelectContext(ctx, &locations, query, bookingID, since)
+	return locations, err
+}
+
+// GetLatest returns the most recently recorded ping of a ride
+func (r *driverLocationRepository) GetLatest(ctx context.Context, bookingID int64) (*models.DriverLocation, error) {
+	location := &models.DriverLocation{}
+	query := `SELECT * FROM driver_locations WHERE booking_id = $1 ORDER BY recorded_at DESC LIMIT 1`
+	if err := r.db.GetContext(ctx, location, query, bookingID); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// DeleteBefore removes pings received before the given time and returns how many were removed
+func (r *driverLocationRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM driver_locations WHERE received_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
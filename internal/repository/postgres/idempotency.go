@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type idempotencyKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewIdempotencyKeyRepository(db *sqlx.DB) repository.IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// Claim records a key as in progress and reports whether this request owns it. A key already held
+// is only taken over once it has expired, or when its request has been in progress longer than
+// staleAfter and is assumed lost.
+func (r *idempotencyKeyRepository) Claim(ctx context.Context, record *models.IdempotencyKey, staleAfter time.Duration) (bool, error) {
+	query := `
+        INSERT INTO idempotency_keys (principal, key, request_hash, created_at, expires_at)
+        VALUES ($1, $2, $3, NOW(), $4)
+        ON CONFLICT (principal, key) DO UPDATE
+        SET request_hash = EXCLUDED.request_hash, response_status = NULL, response_content_type = '',
+            response_body = NULL, created_at = NOW(), expires_at = EXCLUDED.expires_at
+        WHERE idempotency_keys.expires_at < NOW()
+           OR (idempotency_keys.response_status IS NULL AND idempotency_keys.created_at < $5)
+        RETURNING id, created_at
+    `
+	rows, err := r.db.QueryxContext(ctx, query, record.Principal, record.Key, record.RequestHash, record.ExpiresAt,
+		time.Now().Add(-staleAfter))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	return true, rows.Scan(&record.ID, &record.CreatedAt)
+}
+
+func (r *idempotencyKeyRepository) Get(ctx context.Context, principal, key string) (*models.IdempotencyKey, error) {
+	record := &models.IdempotencyKey{}
+	query := `SELECT * FROM idempotency_keys WHERE principal = $1 AND key = $2`
+	if err := r.db.GetContext(ctx, record, query, principal, key); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// SaveResponse stores the response to replay for a claimed key
+func (r *idempotencyKeyRepository) SaveResponse(ctx context.Context, record *models.IdempotencyKey) error {
+	query := `
+        UPDATE idempotency_keys
+        SET response_status = $1, response_content_type = $2, response_body = $3
+        WHERE id = $4
+    `
+	_, err := r.db.ExecContext(ctx, query, record.ResponseStatus, record.ResponseContentType, record.ResponseBody, record.ID)
+	return err
+}
+
+// Release forgets a claimed key whose request failed, so it can be retried
+func (r *idempotencyKeyRepository) Release(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE id = $1`, id)
+	return err
+}
+
+// DeleteExpired removes keys past their expiry and returns how many were removed
+func (r *idempotencyKeyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type jobLeaseRepository struct {
+	db *sqlx.DB
+}
+
+func NewJobLeaseRepository(db *sqlx.DB) repository.JobLeaseRepository {
+	return &jobLeaseRepository{db: db}
+}
+
+// Register adds a job the first time it is scheduled. A known job keeps its next run unless its
+// schedule changed, in which case it is moved to the new schedule's first run.
+func (r *jobLeaseRepository) Register(ctx context.Context, name, schedule string, firstRunAt time.Time) error {
+	query := `
+        INSERT INTO job_leases (name, schedule, next_run_at, updated_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (name) DO UPDATE
+        SET schedule = EXCLUDED.schedule, next_run_at = EXCLUDED.next_run_at, updated_at = NOW()
+        WHERE job_leases.schedule <> EXCLUDED.schedule
+    `
+	_, err := r.db.ExecContext(ctx, query, name, schedule, firstRunAt)
+	return err
+}
+
+// Acquire takes the lease of a due job that no other instance holds. The row lock makes
+// concurrent attempts wait, and only the first sees the job as due and unleased.
+func (r *jobLeaseRepository) Acquire(ctx context.Context, name, holder string, leasedUntil time.Time) (bool, error) {
+	query := `
+        UPDATE job_leases
+        SET holder = $2, leased_until = $3, updated_at = NOW()
+        WHERE name = $1 AND next_run_at <= NOW() AND leased_until < NOW()
+    `
+	result, err := r.db.ExecContext(ctx, query, name, holder, leasedUntil)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// Release records a finished run and gives up the lease
+func (r *jobLeaseRepository) Release(ctx context.Context, name, holder string, nextRunAt time.Time, lastError string) error {
+	query := `
+        UPDATE job_leases
+        SET leased_until = NOW(), next_run_at = $3, last_run_at = NOW(), last_error = $4, updated_at = NOW()
+        WHERE name = $1 AND holder = $2
+    `
+	_, err := r.db.ExecContext(ctx, query, name, holder, nextRunAt, lastError)
+	return err
+}
+
+func (r *jobLeaseRepository) List(ctx context.Context) ([]*models.JobLease, error) {
+	var leases []*models.JobLease
+	query := `SELECT * FROM job_leases ORDER BY name`
+	if err := r.db.SelectContext(ctx, &leases, query); err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
+
+type bookingNoticeRepository struct {
+	db *sqlx.DB
+}
+
+func NewBookingNoticeRepository(db *sqlx.DB) repository.BookingNoticeRepository {
+	return &bookingNoticeRepository{db: db}
+}
+
+// Claim records notices about a booking. It reports whether the first kind had not been
+// claimed before; the other kinds are recorded as superseded either way.
+func (r *bookingNoticeRepository) Claim(ctx context.Context, bookingID int64, kinds ...string) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO booking_notices (booking_id, kind, sent_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (booking_id, kind) DO NOTHING
+    `
+	claimed := false
+	for i, kind := range kinds {
+		result, err := tx.ExecContext(ctx, query, bookingID, kind)
+		if err != nil {
+			return false, err
+		}
+		if i == 0 {
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return false, err
+			}
+			claimed = rows == 1
+		}
+	}
+	return claimed, tx.Commit()
+}
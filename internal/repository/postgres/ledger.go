@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type ledgerRepository struct {
+	db *sqlx.DB
+}
+
+func NewLedgerRepository(db *sqlx.DB) repository.LedgerRepository {
+	return &ledgerRepository{db: db}
+}
+
+// Post records a balanced transaction; a transaction whose reference was already posted
+// is left alone and sql.ErrNoRows is returned
+func (r *ledgerRepository) Post(ctx context.Context, txn *models.LedgerTransaction) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := postTx(ctx, tx, txn); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// postTx inserts a transaction and its entries within an open database transaction
+func postTx(ctx context.Context, tx *sqlx.Tx, txn *models.LedgerTransaction) error {
+	var sum models.Money
+	for _, entry := range txn.Entries {
+		sum += entry.Amount
+	}
+	if len(txn.Entries) < 2 || sum != 0 {
+		return fmt.Errorf("unbalanced ledger transaction %s", txn.Reference)
+	}
+
+	insert := `
+        INSERT INTO ledger_transactions (reference, kind, booking_id, payout_id, description, occurred_at)
+        VALUES ($1, $2, $3, $4, $5, NOW())
+        ON CONFLICT (reference) DO NOTHING
+        RETURNING id, occurred_at
+    `
+	if err := tx.QueryRowxContext(ctx, insert, txn.Reference, txn.Kind, txn.BookingID, txn.PayoutID, txn.Description).
+		Scan(&txn.ID, &txn.OccurredAt); err != nil {
+		return err
+	}
+
+	for i := range txn.Entries {
+		entry := &txn.Entries[i]
+		entry.TransactionID = txn.ID
+		query := `
+            INSERT INTO ledger_entries (transaction_id, account, driver_id, category, amount, currency)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id
+        `
+		if err := tx.QueryRowxContext(ctx, query, entry.TransactionID, entry.Account, entry.DriverID, entry.Category,
+			entry.Amount, entry.Currency).Scan(&entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDriverEntries returns the entries attributed to a driver in [from, to), oldest first
+func (r *ledgerRepository) GetDriverEntries(ctx context.Context, driverID int64, from, to time.Time) ([]*models.LedgerEntry, error) {
+	var entries []*models.LedgerEntry
+	query := `
+        SELECT e.*, t.booking_id, t.description, t.occurred_at
+        FROM ledger_entries e
+        JOIN ledger_transactions t ON t.id = e.transaction_id
+        WHERE e.driver_id = $1 AND t.occurred_at >= $2 AND t.occurred_at < $3
+        ORDER BY t.occurred_at, e.id
+    `
+	if err := r.db.SelectContext(ctx, &entries, query, driverID, from, to); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetDriverBalance returns what the platform currently owes a driver
+func (r *ledgerRepository) GetDriverBalance(ctx context.Context, driverID int64) (models.Money, error) {
+	var balance models.Money
+	query := `SELECT COALESCE(-SUM(amount), 0) FROM ledger_entries WHERE driver_id = $1 AND account = $2`
+	if err := r.db.GetContext(ctx, &balance, query, driverID, models.LedgerAccountDriverPayable); err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+// GetPayableBalances returns the positive balances owed to drivers from earnings posted before a time.
+// Payouts and failed payouts count whenever they were posted, so earnings are never paid twice.
+func (r *ledgerRepository) GetPayableBalances(ctx context.Context, before time.Time) (map[int64]models.Money, error) {
+	var rows []struct {
+		DriverID int64        `db:"driver_id"`
+		Balance  models.Money `db:"balance"`
+	}
+	query := `
+        SELECT e.driver_id, -SUM(e.amount) AS balance
+        FROM ledger_entries e
+        JOIN ledger_transactions t ON t.id = e.transaction_id
+        WHERE e.account = $1 AND e.driver_id IS NOT NULL AND (t.occurred_at < $2 OR e.category = $3)
+        GROUP BY e.driver_id
+        HAVING -SUM(e.amount) > 0
+    `
+	if err := r.db.SelectContext(ctx, &rows, query, models.LedgerAccountDriverPayable, before, models.LedgerCategoryPayout); err != nil {
+		return nil, err
+	}
+	balances := make(map[int64]models.Money, len(rows))
+	for _, row := range rows {
+		balances[row.DriverID] = row.Balance
+	}
+	return balances, nil
+}
+
+// CreatePayoutBatch stores a batch with its payouts and posts postings[i] for batch.Payouts[i],
+// all in one transaction. A batch already generated for the period is reported as sql.ErrNoRows.
+func (r *ledgerRepository) CreatePayoutBatch(ctx context.Context, batch *models.PayoutBatch, postings []*models.LedgerTransaction) error {
+	if len(postings) != len(batch.Payouts) {
+		return errors.New("every payout needs exactly one ledger posting")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insert := `
+        INSERT INTO payout_batches (period_start, period_end, currency, total_amount, payout_count, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW())
+        ON CONFLICT (period_start) DO NOTHING
+        RETURNING id, created_at
+    `
+	if err := tx.QueryRowxContext(ctx, insert, batch.PeriodStart, batch.PeriodEnd, batch.Currency, batch.TotalAmount,
+		batch.PayoutCount, batch.CreatedBy).Scan(&batch.ID, &batch.CreatedAt); err != nil {
+		return err
+	}
+
+	for i, payout := range batch.Payouts {
+		payout.BatchID = batch.ID
+		query := `
+            INSERT INTO payouts (batch_id, driver_id, amount, currency, status, created_at, updated_at)
+            VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+            RETURNING id, created_at, updated_at
+        `
+		if err := tx.QueryRowxContext(ctx, query, payout.BatchID, payout.DriverID, payout.Amount, payout.Currency,
+			payout.Status).Scan(&payout.ID, &payout.CreatedAt, &payout.UpdatedAt); err != nil {
+			return err
+		}
+		postings[i].PayoutID = &payout.ID
+		if err := postTx(ctx, tx, postings[i]); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *ledgerRepository) GetPayoutBatches(ctx context.Context) ([]*models.PayoutBatch, error) {
+	var batches []*models.PayoutBatch
+	query := `SELECT * FROM payout_batches ORDER BY period_start DESC`
+	if err := r.db.SelectContext(ctx, &batches, query); err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+func (r *ledgerRepository) GetPayoutBatchByID(ctx context.Context, id int64) (*models.PayoutBatch, error) {
+	batch := &models.PayoutBatch{}
+	query := `SELECT * FROM payout_batches WHERE id = $1`
+	if err := r.db.GetContext(ctx, batch, query, id); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+func (r *ledgerRepository) GetPayoutBatchByPeriod(ctx context.Context, periodStart time.Time) (*models.PayoutBatch, error) {
+	batch := &models.PayoutBatch{}
+	query := `SELECT * FROM payout_batches WHERE period_start = $1`
+	if err := r.db.GetContext(ctx, batch, query, periodStart); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// GetLatestPayoutBatch returns the batch of the most recent week paid out, or sql.ErrNoRows
+func (r *ledgerRepository) GetLatestPayoutBatch(ctx context.Context) (*models.PayoutBatch, error) {
+	batch := &models.PayoutBatch{}
+	query := `SELECT * FROM payout_batches ORDER BY period_start DESC LIMIT 1`
+	if err := r.db.GetContext(ctx, batch, query); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// payoutColumns selects payouts with the username and email of their driver
+const payoutColumns = `
+        SELECT p.*, u.username AS driver_username, u.email AS driver_email
+        FROM payouts p
+        JOIN users u ON u.id = p.driver_id
+    `
+
+func (r *ledgerRepository) GetPayoutsByBatchID(ctx context.Context, batchID int64) ([]*models.Payout, error) {
+	var payouts []*models.Payout
+	query := payoutColumns + `WHERE p.batch_id = $1 ORDER BY p.id`
+	if err := r.db.SelectContext(ctx, &payouts, query, batchID); err != nil {
+		return nil, err
+	}
+	return payouts, nil
+}
+
+func (r *ledgerRepository) GetPayoutsByDriverID(ctx context.Context, driverID int64) ([]*models.Payout, error) {
+	var payouts []*models.Payout
+	query := payoutColumns + `WHERE p.driver_id = $1 ORDER BY p.created_at DESC`
+	if err := r.db.SelectContext(ctx, &payouts, query, driverID); err != nil {
+		return nil, err
+	}
+	return payouts, nil
+}
+
+func (r *ledgerRepository) GetPayoutByID(ctx context.Context, id int64) (*models.Payout, error) {
+	payout := &models.Payout{}
+	query := payoutColumns + `WHERE p.id = $1`
+	if err := r.db.GetContext(ctx, payout, query, id); err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+// SettlePayout moves a pending payout to paid or failed and posts the matching ledger transaction
+func (r *ledgerRepository) SettlePayout(ctx context.Context, payout *models.Payout, posting *models.LedgerTransaction) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE payouts
+        SET status = $1, reference = $2, failure_reason = $3,
+            paid_at = CASE WHEN $1 = 'paid' THEN NOW() ELSE NULL END, updated_at = NOW()
+        WHERE id = $4 AND status = 'pending'
+        RETURNING paid_at, updated_at
+    `
+	err = tx.QueryRowxContext(ctx, query, payout.Status, payout.Reference, payout.FailureReason, payout.ID).
+		Scan(&payout.PaidAt, &payout.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.New("payout not found or not pending")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := postTx(ctx, tx, posting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type legalEntityRepository struct {
+	db *sqlx.DB
+}
+
+func NewLegalEntityRepository(db *sqlx.DB) repository.LegalEntityRepository {
+	return &legalEntityRepository{db: db}
+}
+
+func (r *legalEntityRepository) List(ctx context.Context) ([]*models.LegalEntity, error) {
+	var entities []*models.LegalEntity
+	query := `SELECT * FROM legal_entities ORDER BY code`
+	if err := r.db.SelectContext(ctx, &entities, query); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *legalEntityRepository) GetByID(ctx context.Context, id int64) (*models.LegalEntity, error) {
+	entity := &models.LegalEntity{}
+	query := `SELECT * FROM legal_entities WHERE id = $1`
+	if err := r.db.GetContext(ctx, entity, query, id); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (r *legalEntityRepository) GetByCode(ctx context.Context, code string) (*models.LegalEntity, error) {
+	entity := &models.LegalEntity{}
+	query := `SELECT * FROM legal_entities WHERE code = $1`
+	if err := r.db.GetContext(ctx, entity, query, code); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// Upsert creates or updates a legal entity; the receipt sequence of an existing entity is never reset
+func (r *legalEntityRepository) Upsert(ctx context.Context, entity *models.LegalEntity) error {
+	query := `
+        INSERT INTO legal_entities (code, name, address, tax_id, tax_label, tax_rate_bp, email, phone,
+                                    receipt_prefix, created_at, updated_at)
+        VALUES (:code, :name, :address, :tax_id, :tax_label, :tax_rate_bp, :email, :phone,
+                :receipt_prefix, NOW(), NOW())
+        ON CONFLICT (code) DO UPDATE
+        SET name = EXCLUDED.name,
+            address = EXCLUDED.address,
+            tax_id = EXCLUDED.tax_id,
+            tax_label = EXCLUDED.tax_label,
+            tax_rate_bp = EXCLUDED.tax_rate_bp,
+            email = EXCLUDED.email,
+            phone = EXCLUDED.phone,
+            receipt_prefix = EXCLUDED.receipt_prefix,
+            updated_at = NOW()
+        RETURNING id, next_receipt_number, created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, entity)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&entity.ID, &entity.NextReceiptNumber, &entity.CreatedAt, &entity.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
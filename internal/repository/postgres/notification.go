@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type notificationPreferenceRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationPreferenceRepository(db *sqlx.DB) repository.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) Get(ctx context.Context, userID int64) (*models.NotificationPreferences, error) {
+	var prefs models.NotificationPreferences
+	query := `SELECT * FROM notification_preferences WHERE user_id = $1`
+	if err := r.db.GetContext(ctx, &prefs, query, userID); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+// GetByEmail returns the preferences of the account registered with an email address, which is
+// how recipients named on a booking are matched to users
+func (r *notificationPreferenceRepository) GetByEmail(ctx context.Context, email string) (*models.NotificationPreferences, error) {
+	var prefs models.NotificationPreferences
+	query := `
+        SELECT np.*
+        FROM notification_preferences np
+        JOIN users u ON u.id = np.user_id
+        WHERE LOWER(u.email) = LOWER($1)
+    `
+	if err := r.db.GetContext(ctx, &prefs, query, email); err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, prefs *models.NotificationPreferences) error {
+	query := `
+        INSERT INTO notification_preferences (user_id, channels, quiet_hours_start, quiet_hours_end, timezone, language, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW())
+        ON CONFLICT (user_id) DO UPDATE
+        SET channels = EXCLUDED.channels, quiet_hours_start = EXCLUDED.quiet_hours_start,
+            quiet_hours_end = EXCLUDED.quiet_hours_end, timezone = EXCLUDED.timezone,
+            language = EXCLUDED.language, updated_at = NOW()
+        RETURNING updated_at
+    `
+	return r.db.QueryRowxContext(ctx, query, prefs.UserID, prefs.Channels, prefs.QuietHoursStart,
+		prefs.QuietHoursEnd, prefs.Timezone, prefs.Language).Scan(&prefs.UpdatedAt)
+}
@@ -0,0 +1,302 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type organizationRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrganizationRepository(db *sqlx.DB) repository.OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	query := `
+        INSERT INTO organizations (name, billing_email, billing_address, tax_id, currency, approval_threshold,
+                                   require_cost_center, active, created_at, updated_at)
+        VALUES (:name, :billing_email, :billing_address, :tax_id, :currency, :approval_threshold,
+                :require_cost_center, :active, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, org)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&org.ID, &org.CreatedAt, &org.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+func (r *organizationRepository) Update(ctx context.Context, org *models.Organization) error {
+	query := `
+        UPDATE organizations
+        SET name = $1, billing_email = $2, billing_address = $3, tax_id = $4, currency = $5,
+            approval_threshold = $6, require_cost_center = $7, active = $8, updated_at = NOW()
+        WHERE id = $9
+        RETURNING updated_at
+    `
+	return r.db.QueryRowxContext(ctx, query, org.Name, org.BillingEmail, org.BillingAddress, org.TaxID, org.Currency,
+		org.ApprovalThreshold, org.RequireCostCenter, org.Active, org.ID).Scan(&org.UpdatedAt)
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id int64) (*models.Organization, error) {
+	org := &models.Organization{}
+	query := `SELECT * FROM organizations WHERE id = $1`
+	if err := r.db.GetContext(ctx, org, query, id); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (r *organizationRepository) List(ctx context.Context) ([]*models.Organization, error) {
+	var orgs []*models.Organization
+	query := `SELECT * FROM organizations ORDER BY name`
+	if err := r.db.SelectContext(ctx, &orgs, query); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (r *organizationRepository) ListActive(ctx context.Context) ([]*models.Organization, error) {
+	var orgs []*models.Organization
+	query := `SELECT * FROM organizations WHERE active = TRUE ORDER BY id`
+	if err := r.db.SelectContext(ctx, &orgs, query); err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// memberColumns selects members with the username and email of their user
+const memberColumns = `
+        SELECT m.*, u.username, u.email
+        FROM organization_members m
+        JOIN users u ON u.id = m.user_id
+    `
+
+func (r *organizationRepository) GetMembers(ctx context.Context, orgID int64) ([]*models.OrganizationMember, error) {
+	var members []*models.OrganizationMember
+	query := memberColumns + `WHERE m.organization_id = $1 ORDER BY u.username`
+	if err := r.db.SelectContext(ctx, &members, query, orgID); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *organizationRepository) GetMemberByUserID(ctx context.Context, userID int64) (*models.OrganizationMember, error) {
+	member := &models.OrganizationMember{}
+	query := memberColumns + `WHERE m.user_id = $1`
+	if err := r.db.GetContext(ctx, member, query, userID); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// AddMember adds a user to an organization; a user who already belongs to one is reported as sql.ErrNoRows
+func (r *organizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	query := `
+        INSERT INTO organization_members (organization_id, user_id, role, default_cost_center_id, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, NOW(), NOW())
+        ON CONFLICT (user_id) DO NOTHING
+        RETURNING id, created_at, updated_at
+    `
+	return r.db.QueryRowxContext(ctx, query, member.OrganizationID, member.UserID, member.Role, member.DefaultCostCenterID).
+		Scan(&member.ID, &member.CreatedAt, &member.UpdatedAt)
+}
+
+func (r *organizationRepository) UpdateMember(ctx context.Context, member *models.OrganizationMember) error {
+	query := `
+        UPDATE organization_members
+        SET role = $1, default_cost_center_id = $2, updated_at = NOW()
+        WHERE organization_id = $3 AND user_id = $4
+        RETURNING updated_at
+    `
+	return r.db.QueryRowxContext(ctx, query, member.Role, member.DefaultCostCenterID, member.OrganizationID, member.UserID).
+		Scan(&member.UpdatedAt)
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`, orgID, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *organizationRepository) GetCostCenters(ctx context.Context, orgID int64) ([]*models.CostCenter, error) {
+	var costCenters []*models.CostCenter
+	query := `SELECT * FROM cost_centers WHERE organization_id = $1 ORDER BY code`
+	if err := r.db.SelectContext(ctx, &costCenters, query, orgID); err != nil {
+		return nil, err
+	}
+	return costCenters, nil
+}
+
+func (r *organizationRepository) GetCostCenterByCode(ctx context.Context, orgID int64, code string) (*models.CostCenter, error) {
+	costCenter := &models.CostCenter{}
+	query := `SELECT * FROM cost_centers WHERE organization_id = $1 AND code = $2`
+	if err := r.db.GetContext(ctx, costCenter, query, orgID, code); err != nil {
+		return nil, err
+	}
+	return costCenter, nil
+}
+
+func (r *organizationRepository) UpsertCostCenter(ctx context.Context, costCenter *models.CostCenter) error {
+	query := `
+        INSERT INTO cost_centers (organization_id, code, name, active, created_at, updated_at)
+        VALUES (:organization_id, :code, :name, :active, NOW(), NOW())
+        ON CONFLICT (organization_id, code) DO UPDATE
+        SET name = EXCLUDED.name,
+            active = EXCLUDED.active,
+            updated_at = NOW()
+        RETURNING id, created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, costCenter)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&costCenter.ID, &costCenter.CreatedAt, &costCenter.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+// GetBookings returns an organization's bookings, optionally only those with the given approval status
+func (r *organizationRepository) GetBookings(ctx context.Context, orgID int64, approvalStatus string) ([]*models.BookRide, error) {
+	var bookings []*models.BookRide
+	query := `
+        SELECT * FROM book_rides
+        WHERE organization_id = $1 AND ($2 = '' OR org_approval_status = $2)
+        ORDER BY date DESC, time DESC
+    `
+	if err := r.db.SelectContext(ctx, &bookings, query, orgID, approvalStatus); err != nil {
+		return nil, err
+	}
+	bookingRepo := &bookRideRepository{db: r.db}
+	if err := bookingRepo.attachStops(ctx, bookings...); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// DecideBooking approves or rejects a booking awaiting an org admin's approval
+func (r *organizationRepository) DecideBooking(ctx context.Context, orgID, bookingID int64, status string, decidedBy int64) error {
+	query := `
+        UPDATE book_rides
+        SET org_approval_status = $1, org_approved_by = $2, org_approved_at = NOW(), updated_at = NOW()
+        WHERE id = $3 AND organization_id = $4 AND org_approval_status = 'pending' AND book_status = 'Pending'
+    `
+	result, err := r.db.ExecContext(ctx, query, status, decidedBy, bookingID, orgID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found or not awaiting approval")
+	}
+	return nil
+}
+
+// RequireBookingApproval sends an unassigned booking back for approval after its fare changed
+func (r *organizationRepository) RequireBookingApproval(ctx context.Context, bookingID int64) error {
+	query := `
+        UPDATE book_rides
+        SET org_approval_status = 'pending', org_approved_by = NULL, org_approved_at = NULL, updated_at = NOW()
+        WHERE id = $1 AND organization_id IS NOT NULL AND book_status = 'Pending' AND driver_id IS NULL
+    `
+	_, err := r.db.ExecContext(ctx, query, bookingID)
+	return err
+}
+
+// GetUninvoicedBookings returns the completed bookings, and cancelled bookings that incurred a fee,
+// with a pickup date before the given day that are not on any invoice yet
+func (r *organizationRepository) GetUninvoicedBookings(ctx context.Context, orgID int64, before time.Time) ([]*models.BookRide, error) {
+	var bookings []*models.BookRide
+	query := `
+        SELECT b.* FROM book_rides b
+        WHERE b.organization_id = $1 AND b.date < $2
+          AND (b.book_status = 'Completed' OR (b.book_status = 'Cancelled' AND b.cancellation_fee > 0))
+          AND NOT EXISTS (SELECT 1 FROM organization_invoice_lines l WHERE l.booking_id = b.id)
+        ORDER BY b.date, b.time, b.id
+    `
+	if err := r.db.SelectContext(ctx, &bookings, query, orgID, before.Format("2006-01-02")); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// CreateInvoice stores an invoice with its lines. An invoice already issued for the period is reported as sql.ErrNoRows.
+func (r *organizationRepository) CreateInvoice(ctx context.Context, invoice *models.OrganizationInvoice) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insert := `
+        INSERT INTO organization_invoices (organization_id, number, period_start, period_end, currency, total_amount,
+                                           booking_count, issued_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+        ON CONFLICT (organization_id, period_start) DO NOTHING
+        RETURNING id, issued_at
+    `
+	if err := tx.QueryRowxContext(ctx, insert, invoice.OrganizationID, invoice.Number, invoice.PeriodStart, invoice.PeriodEnd,
+		invoice.Currency, invoice.TotalAmount, invoice.BookingCount).Scan(&invoice.ID, &invoice.IssuedAt); err != nil {
+		return err
+	}
+
+	for _, line := range invoice.Lines {
+		line.InvoiceID = invoice.ID
+		query := `
+            INSERT INTO organization_invoice_lines (invoice_id, booking_id, cost_center_code, rider_name, ride_date,
+                                                    description, amount)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)
+            RETURNING id
+        `
+		if err := tx.QueryRowxContext(ctx, query, line.InvoiceID, line.BookingID, line.CostCenterCode, line.RiderName,
+			line.RideDate, line.Description, line.Amount).Scan(&line.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *organizationRepository) GetInvoices(ctx context.Context, orgID int64) ([]*models.OrganizationInvoice, error) {
+	var invoices []*models.OrganizationInvoice
+	query := `SELECT * FROM organization_invoices WHERE organization_id = $1 ORDER BY period_start DESC`
+	if err := r.db.SelectContext(ctx, &invoices, query, orgID); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// GetInvoiceByID returns an invoice of an organization with its lines
+func (r *organizationRepository) GetInvoiceByID(ctx context.Context, orgID, id int64) (*models.OrganizationInvoice, error) {
+	invoice := &models.OrganizationInvoice{}
+	query := `SELECT * FROM organization_invoices WHERE id = $1 AND organization_id = $2`
+	if err := r.db.GetContext(ctx, invoice, query, id, orgID); err != nil {
+		return nil, err
+	}
+
+	lines := `SELECT * FROM organization_invoice_lines WHERE invoice_id = $1 ORDER BY ride_date, id`
+	if err := r.db.SelectContext(ctx, &invoice.Lines, lines, id); err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type paymentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentRepository(db *sqlx.DB) repository.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Create(ctx context.Context, p *models.Payment) error {
+	query := `
+        INSERT INTO payments (booking_id, provider, provider_ref, purpose, payment_method, status, authorized_amount,
+                              captured_amount, refunded_amount, currency, failure_reason, created_at, updated_at)
+        VALUES (:booking_id, :provider, :provider_ref, :purpose, :payment_method, :status, :authorized_amount,
+                :captured_amount, :refunded_amount, :currency, :failure_reason, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, p)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+func (r *paymentRepository) GetByID(ctx context.Context, id int64) (*models.Payment, error) {
+	p := &models.Payment{}
+	query := `SELECT * FROM payments WHERE id = $1`
+	if err := r.db.GetContext(ctx, p, query, id); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *paymentRepository) GetByProviderRef(ctx context.Context, provider, providerRef string) (*models.Payment, error) {
+	p := &models.Payment{}
+	query := `SELECT * FROM payments WHERE provider = $1 AND provider_ref = $2`
+	if err := r.db.GetContext(ctx, p, query, provider, providerRef); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetActiveByBookingID returns the most recent fare payment of a booking that still holds or has moved money
+func (r *paymentRepository) GetActiveByBookingID(ctx context.Context, bookingID int64) (*models.Payment, error) {
+	p := &models.Payment{}
+	query := `
+        SELECT * FROM payments
+        WHERE booking_id = $1 AND purpose = 'fare' AND status IN ('authorized', 'captured', 'partially_refunded')
+        ORDER BY created_at DESC
+        LIMIT 1
+    `
+	if err := r.db.GetContext(ctx, p, query, bookingID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *paymentRepository) GetByBookingID(ctx context.Context, bookingID int64) ([]*models.Payment, error) {
+	var payments []*models.Payment
+	query := `SELECT * FROM payments WHERE booking_id = $1 ORDER BY created_at ASC`
+	if err := r.db.SelectContext(ctx, &payments, query, bookingID); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *paymentRepository) Update(ctx context.Context, p *models.Payment) error {
+	query := `
+        UPDATE payments
+        SET status = :status, captured_amount = :captured_amount, refunded_amount = :refunded_amount,
+            failure_reason = :failure_reason, updated_at = NOW()
+        WHERE id = :id
+    `
+	result, err := r.db.NamedExecContext(ctx, query, p)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("payment not found")
+	}
+	return nil
+}
+
+// ApplyEvent records a provider event and, when p is set, stores the payment state it leads to, in one
+// transaction. The payment is only updated while it is still in fromStatus and has not refunded more
+// than p, so a concurrent change wins. It reports false, recording nothing, when the event was already
+// handled or the payment changed in the meantime.
+func (r *paymentRepository) ApplyEvent(ctx context.Context, event *models.PaymentEvent, p *models.Payment, fromStatus string) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO payment_events (provider, event_id, payment_id, type, applied, received_at)
+        VALUES ($1, $2, $3, $4, $5, NOW())
+        ON CONFLICT (provider, event_id) DO NOTHING
+        RETURNING received_at
+    `
+	err = tx.QueryRowxContext(ctx, query, event.Provider, event.EventID, event.PaymentID, event.Type,
+		event.Applied).Scan(&event.ReceivedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if p != nil {
+		query = `
+            UPDATE payments
+            SET status = $1, captured_amount = $2, refunded_amount = $3, failure_reason = $4,
+                last_event_at = $5, updated_at = NOW()
+            WHERE id = $6 AND status = $7 AND refunded_amount <= $3
+        `
+		result, err := tx.ExecContext(ctx, query, p.Status, p.CapturedAmount, p.RefundedAmount, p.FailureReason,
+			p.LastEventAt, p.ID, fromStatus)
+		if err != nil {
+			return false, err
+		}
+		rowsAffected, _ := result.RowsAffected()
+		if rowsAffected == 0 {
+			return false, nil
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// RecordRefund stores the refunded total of a payment together with the refund's audit record
+func (r *paymentRepository) RecordRefund(ctx context.Context, p *models.Payment, refund *models.PaymentRefund) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE payments
+        SET status = $1, refunded_amount = $2, updated_at = NOW()
+        WHERE id = $3
+    `
+	result, err := tx.ExecContext(ctx, query, p.Status, p.RefundedAmount, p.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("payment not found")
+	}
+
+	query = `
+        INSERT INTO payment_refunds (payment_id, booking_id, admin_id, amount, currency, reason, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW())
+        RETURNING id, created_at
+    `
+	if err := tx.QueryRowxContext(ctx, query, refund.PaymentID, refund.BookingID, refund.AdminID, refund.Amount,
+		refund.Currency, refund.Reason).Scan(&refund.ID, &refund.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetRefundsByBookingID returns the refund audit trail of a booking, oldest first
+func (r *paymentRepository) GetRefundsByBookingID(ctx context.Context, bookingID int64) ([]*models.PaymentRefund, error) {
+	var refunds []*models.PaymentRefund
+	query := `SELECT * FROM payment_refunds WHERE booking_id = $1 ORDER BY created_at ASC`
+	if err := r.db.SelectContext(ctx, &refunds, query, bookingID); err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type postRideAdjustmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPostRideAdjustmentRepository(db *sqlx.DB) repository.PostRideAdjustmentRepository {
+	return &postRideAdjustmentRepository{db: db}
+}
+
+// Create inserts an adjustment. A tip on a booking that already has one is not inserted and
+// sql.ErrNoRows is returned.
+func (r *postRideAdjustmentRepository) Create(ctx context.Context, adj *models.PostRideAdjustment) error {
+	query := `
+        INSERT INTO post_ride_adjustments (booking_id, kind, amount, currency, minutes, description, submitted_by,
+                                           status, requires_approval, created_at, updated_at)
+        VALUES (:booking_id, :kind, :amount, :currency, :minutes, :description, :submitted_by,
+                :status, :requires_approval, NOW(), NOW())
+        ON CONFLICT (booking_id) WHERE kind = 'tip' AND status IN ('pending', 'charging', 'charged') DO NOTHING
+        RETURNING id, created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, adj)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&adj.ID, &adj.CreatedAt, &adj.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+func (r *postRideAdjustmentRepository) GetByID(ctx context.Context, id int64) (*models.PostRideAdjustment, error) {
+	adj := &models.PostRideAdjustment{}
+	query := `SELECT * FROM post_ride_adjustments WHERE id = $1`
+	if err := r.db.GetContext(ctx, adj, query, id); err != nil {
+		return nil, err
+	}
+	return adj, nil
+}
+
+func (r *postRideAdjustmentRepository) GetByBookingID(ctx context.Context, bookingID int64) ([]*models.PostRideAdjustment, error) {
+	var adjustments []*models.PostRideAdjustment
+	query := `SELECT * FROM post_ride_adjustments WHERE booking_id = $1 ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &adjustments, query, bookingID); err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+func (r *postRideAdjustmentRepository) GetPending(ctx context.Context) ([]*models.PostRideAdjustment, error) {
+	var adjustments []*models.PostRideAdjustment
+	query := `SELECT * FROM post_ride_adjustments WHERE status = 'pending' ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &adjustments, query); err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+func (r *postRideAdjustmentRepository) Reject(ctx context.Context, id int64, decidedBy *int64) error {
+	query := `
+        UPDATE post_ride_adjustments
+        SET status = 'rejected', decided_by = $1, decided_at = NOW(), updated_at = NOW()
+        WHERE id = $2 AND status = 'pending'
+    `
+	result, err := r.db.ExecContext(ctx, query, decidedBy, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("adjustment not found or not pending")
+	}
+	return nil
+}
+
+// Claim moves a pending adjustment to charging for the approver about to charge it. It returns
+// sql.ErrNoRows when the adjustment is no longer pending, e.g. because another approval claimed it.
+func (r *postRideAdjustmentRepository) Claim(ctx context.Context, id int64, decidedBy *int64) error {
+	query := `
+        UPDATE post_ride_adjustments
+        SET status = 'charging', decided_by = $1, decided_at = NOW(), updated_at = NOW()
+        WHERE id = $2 AND status = 'pending'
+    `
+	result, err := r.db.ExecContext(ctx, query, decidedBy, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MarkFailed records that the charge of a charging adjustment failed
+func (r *postRideAdjustmentRepository) MarkFailed(ctx context.Context, id int64, decidedBy *int64, paymentID *int64, reason string) error {
+	query := `
+        UPDATE post_ride_adjustments
+        SET status = 'failed', decided_by = $1, decided_at = NOW(), payment_id = $2, failure_reason = $3, updated_at = NOW()
+        WHERE id = $4 AND status = 'charging'
+    `
+	result, err := r.db.ExecContext(ctx, query, decidedBy, paymentID, reason, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("adjustment not found or not charging")
+	}
+	return nil
+}
+
+// Apply marks a charging adjustment as charged and adds its line item to the final fare of the booking
+// within a single transaction
+func (r *postRideAdjustmentRepository) Apply(ctx context.Context, adj *models.PostRideAdjustment, decidedBy *int64, paymentID *int64, item models.LineItem) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE post_ride_adjustments
+        SET status = 'charged', decided_by = $1, decided_at = NOW(), payment_id = $2, updated_at = NOW()
+        WHERE id = $3 AND status = 'charging'
+    `
+	result, err := tx.ExecContext(ctx, query, decidedBy, paymentID, adj.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("adjustment not found or not charging")
+	}
+
+	var items models.LineItems
+	if err := tx.GetContext(ctx, &items, `SELECT final_line_items FROM book_rides WHERE id = $1 FOR UPDATE`, adj.BookingID); err != nil {
+		return err
+	}
+	items = append(items, item)
+
+	update := `UPDATE book_rides SET final_line_items = $1, final_amount = $2, updated_at = NOW() WHERE id = $3 AND book_status = 'Completed'`
+	result, err = tx.ExecContext(ctx, update, items, items.Total(), adj.BookingID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ = result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("booking not found or not completed")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	adj.Status = models.AdjustmentStatusCharged
+	adj.DecidedBy = decidedBy
+	adj.PaymentID = paymentID
+	return nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type ratingRepository struct {
+	db *sqlx.DB
+}
+
+func NewRatingRepository(db *sqlx.DB) repository.RatingRepository {
+	return &ratingRepository{db: db}
+}
+
+// Create stores a rating. It returns sql.ErrNoRows if the booking was already rated in that direction.
+func (r *ratingRepository) Create(ctx context.Context, rating *models.Rating) error {
+	query := `
+        INSERT INTO ratings (booking_id, direction, rater_id, ratee_id, score, tags, comment, flagged, created_at)
+        VALUES (:booking_id, :direction, :rater_id, :ratee_id, :score, :tags, :comment, :flagged, NOW())
+        ON CONFLICT (booking_id, direction) DO NOTHING
+        RETURNING id, created_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, rating)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&rating.ID, &rating.CreatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+func (r *ratingRepository) GetByBookingID(ctx context.Context, bookingID int64) ([]*models.Rating, error) {
+	var ratings []*models.Rating
+	query := `SELECT * FROM ratings WHERE booking_id = $1 ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &ratings, query, bookingID); err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+func (r *ratingRepository) GetByRateeID(ctx context.Context, rateeID int64) ([]*models.Rating, error) {
+	var ratings []*models.Rating
+	query := `SELECT * FROM ratings WHERE ratee_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &ratings, query, rateeID); err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+func (r *ratingRepository) GetSummary(ctx context.Context, rateeID int64) (*models.RatingSummary, error) {
+	summary := &models.RatingSummary{}
+	query := `
+        SELECT COUNT(*) AS count,
+               COALESCE(ROUND(AVG(score)::numeric, 2), 0)::float8 AS average,
+               COUNT(*) FILTER (WHERE flagged) AS flagged_count
+        FROM ratings
+        WHERE ratee_id = $1
+    `
+	if err := r.db.GetContext(ctx, summary, query, rateeID); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func (r *ratingRepository) GetFlagged(ctx context.Context) ([]*models.Rating, error) {
+	var ratings []*models.Rating
+	query := `SELECT * FROM ratings WHERE flagged = TRUE AND reviewed_at IS NULL ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &ratings, query); err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+func (r *ratingRepository) Review(ctx context.Context, id int64, adminID int64, note string) error {
+	query := `
+        UPDATE ratings
+        SET reviewed_by = $1, reviewed_at = NOW(), review_note = $2
+        WHERE id = $3 AND flagged = TRUE AND reviewed_at IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, adminID, note, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return errors.New("rating not found, not flagged, or already reviewed")
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type receiptRepository struct {
+	db *sqlx.DB
+}
+
+func NewReceiptRepository(db *sqlx.DB) repository.ReceiptRepository {
+	return &receiptRepository{db: db}
+}
+
+func (r *receiptRepository) GetByBookingID(ctx context.Context, bookingID int64) (*models.Receipt, error) {
+	receipt := &models.Receipt{}
+	query := `SELECT * FROM receipts WHERE booking_id = $1`
+	if err := r.db.GetContext(ctx, receipt, query, bookingID); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Issue returns the receipt of a booking, numbering a new one on first use. Numbers come from the
+// legal entity of the booking's pickup zone (or the default entity) and have no gaps.
+// The flag reports whether a new number was issued.
+func (r *receiptRepository) Issue(ctx context.Context, bookingID int64) (*models.Receipt, bool, error) {
+	receipt, err := r.GetByBookingID(ctx, bookingID)
+	if err == nil {
+		return receipt, false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// Lock the entity row so concurrent receipts take consecutive numbers
+	entity := &models.LegalEntity{}
+	query := `
+        SELECT le.* FROM legal_entities le
+        WHERE le.code = COALESCE(
+            (SELECT z.legal_entity_code FROM book_rides b
+             JOIN service_zones z ON z.id = b.pickup_zone_id
+             WHERE b.id = $1),
+            $2)
+        FOR UPDATE
+    `
+	if err := tx.GetContext(ctx, entity, query, bookingID, models.DefaultLegalEntityCode); err != nil {
+		return nil, false, err
+	}
+
+	receipt = &models.Receipt{
+		BookingID:      bookingID,
+		LegalEntityID:  entity.ID,
+		SequenceNumber: entity.NextReceiptNumber,
+		Number:         entity.ReceiptNumber(entity.NextReceiptNumber),
+	}
+	insert := `
+        INSERT INTO receipts (booking_id, legal_entity_id, sequence_number, number, issued_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (booking_id) DO NOTHING
+        RETURNING id, issued_at
+    `
+	err = tx.QueryRowxContext(ctx, insert, receipt.BookingID, receipt.LegalEntityID, receipt.SequenceNumber, receipt.Number).
+		Scan(&receipt.ID, &receipt.IssuedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Issued concurrently; keep the existing number and leave the sequence untouched
+		tx.Rollback()
+		receipt, err = r.GetByBookingID(ctx, bookingID)
+		return receipt, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	update := `UPDATE legal_entities SET next_receipt_number = next_receipt_number + 1, updated_at = NOW() WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, update, entity.ID); err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	return receipt, true, nil
+}
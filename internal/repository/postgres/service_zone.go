@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type serviceZoneRepository struct {
+	db *sqlx.DB
+}
+
+func NewServiceZoneRepository(db *sqlx.DB) repository.ServiceZoneRepository {
+	return &serviceZoneRepository{db: db}
+}
+
+func (r *serviceZoneRepository) List(ctx context.Context) ([]*models.ServiceZone, error) {
+	var zones []*models.ServiceZone
+	query := `SELECT * FROM service_zones ORDER BY code`
+	if err := r.db.SelectContext(ctx, &zones, query); err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+func (r *serviceZoneRepository) GetByCode(ctx context.Context, code string) (*models.ServiceZone, error) {
+	zone := &models.ServiceZone{}
+	query := `SELECT * FROM service_zones WHERE code = $1`
+	if err := r.db.GetContext(ctx, zone, query, code); err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+func (r *serviceZoneRepository) Upsert(ctx context.Context, zone *models.ServiceZone) error {
+	query := `
+        INSERT INTO service_zones (code, name, timezone, min_lead_minutes, max_advance_days, opens_at, closes_at,
+                                   match_keywords, boundary, blackout_dates, holidays, legal_entity_code, created_at, updated_at)
+        VALUES (:code, :name, :timezone, :min_lead_minutes, :max_advance_days, :opens_at, :closes_at,
+                :match_keywords, :boundary, :blackout_dates, :holidays, :legal_entity_code, NOW(), NOW())
+        ON CONFLICT (code) DO UPDATE
+        SET name = EXCLUDED.name,
+            timezone = EXCLUDED.timezone,
+            min_lead_minutes = EXCLUDED.min_lead_minutes,
+            max_advance_days = EXCLUDED.max_advance_days,
+            opens_at = EXCLUDED.opens_at,
+            closes_at = EXCLUDED.closes_at,
+            match_keywords = EXCLUDED.match_keywords,
+            boundary = EXCLUDED.boundary,
+            blackout_dates = EXCLUDED.blackout_dates,
+            holidays = EXCLUDED.holidays,
+            legal_entity_code = EXCLUDED.legal_entity_code,
+            updated_at = NOW()
+        RETURNING id, created_at, updated_at
+    `
+	rows, err := r.db.NamedQueryContext(ctx, query, zone)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		return rows.Scan(&zone.ID, &zone.CreatedAt, &zone.UpdatedAt)
+	}
+	return sql.ErrNoRows
+}
+
+func (r *serviceZoneRepository) Delete(ctx context.Context, code string) error {
+	if code == models.DefaultZoneCode {
+		return errors.New("the default service zone cannot be deleted")
+	}
+	result, err := r.db.ExecContext(ctx, `DELETE FROM service_zones WHERE code = $1`, code)
+	if err != nil {
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
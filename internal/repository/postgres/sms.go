@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type smsOptOutRepository struct {
+	db *sqlx.DB
+}
+
+func NewSMSOptOutRepository(db *sqlx.DB) repository.SMSOptOutRepository {
+	return &smsOptOutRepository{db: db}
+}
+
+func (r *smsOptOutRepository) IsOptedOut(ctx context.Context, phone string) (bool, error) {
+	var optedOut bool
+	query := `SELECT EXISTS (SELECT 1 FROM sms_opt_outs WHERE phone = $1)`
+	if err := r.db.GetContext(ctx, &optedOut, query, phone); err != nil {
+		return false, err
+	}
+	return optedOut, nil
+}
+
+func (r *smsOptOutRepository) List(ctx context.Context) ([]*models.SMSOptOut, error) {
+	var optOuts []*models.SMSOptOut
+	query := `SELECT * FROM sms_opt_outs ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &optOuts, query); err != nil {
+		return nil, err
+	}
+	return optOuts, nil
+}
+
+// Add opts a phone number out; opting out twice keeps the original opt-out
+func (r *smsOptOutRepository) Add(ctx context.Context, phone, source string) error {
+	query := `
+        INSERT INTO sms_opt_outs (phone, source, created_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (phone) DO NOTHING
+    `
+	_, err := r.db.ExecContext(ctx, query, phone, source)
+	return err
+}
+
+func (r *smsOptOutRepository) Remove(ctx context.Context, phone string) error {
+	query := `DELETE FROM sms_opt_outs WHERE phone = $1`
+	_, err := r.db.ExecContext(ctx, query, phone)
+	return err
+}
@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+type webhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) repository.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	query := `
+        INSERT INTO webhook_endpoints (organization_id, url, description, event_types, secret, active, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, TRUE, NOW(), NOW())
+        RETURNING id, active, created_at, updated_at
+    `
+	return r.db.QueryRowxContext(ctx, query, endpoint.OrganizationID, endpoint.URL, endpoint.Description,
+		endpoint.EventTypes, endpoint.Secret).Scan(&endpoint.ID, &endpoint.Active, &endpoint.CreatedAt, &endpoint.UpdatedAt)
+}
+
+func (r *webhookRepository) GetEndpoint(ctx context.Context, orgID, id int64) (*models.WebhookEndpoint, error) {
+	endpoint := &models.WebhookEndpoint{}
+	query := `SELECT * FROM webhook_endpoints WHERE id = $1 AND organization_id = $2`
+	if err := r.db.GetContext(ctx, endpoint, query, id, orgID); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+func (r *webhookRepository) ListEndpoints(ctx context.Context, orgID int64) ([]*models.WebhookEndpoint, error) {
+	var endpoints []*models.WebhookEndpoint
+	query := `SELECT * FROM webhook_endpoints WHERE organization_id = $1 ORDER BY id`
+	if err := r.db.SelectContext(ctx, &endpoints, query, orgID); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// UpdateEndpoint replaces an endpoint's URL, subscriptions and status. Re-enabling an endpoint
+// clears its failures.
+func (r *webhookRepository) UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	query := `
+        UPDATE webhook_endpoints
+        SET url = $1, description = $2, event_types = $3, active = $4,
+            consecutive_failures = CASE WHEN $4 AND NOT active THEN 0 ELSE consecutive_failures END,
+            disabled_at = CASE WHEN $4 THEN NULL ELSE disabled_at END,
+            updated_at = NOW()
+        WHERE id = $5 AND organization_id = $6
+        RETURNING consecutive_failures, disabled_at, updated_at
+    `
+	return r.db.QueryRowxContext(ctx, query, endpoint.URL, endpoint.Description, endpoint.EventTypes, endpoint.Active,
+		endpoint.ID, endpoint.OrganizationID).Scan(&endpoint.ConsecutiveFailures, &endpoint.DisabledAt, &endpoint.UpdatedAt)
+}
+
+func (r *webhookRepository) DeleteEndpoint(ctx context.Context, orgID, id int64) error {
+	query := `DELETE FROM webhook_endpoints WHERE id = $1 AND organization_id = $2`
+	_, err := r.db.ExecContext(ctx, query, id, orgID)
+	return err
+}
+
+// RecordEndpointResult counts an endpoint's failed attempts in a row, disabling it once they reach
+// disableAfter, and resets the count on success. It reports whether this result disabled the endpoint.
+func (r *webhookRepository) RecordEndpointResult(ctx context.Context, id int64, success bool, disableAfter int) (bool, error) {
+	query := `
+        UPDATE webhook_endpoints e
+        SET consecutive_failures = CASE WHEN $2 THEN 0 ELSE e.consecutive_failures + 1 END,
+            active = e.active AND ($2 OR e.consecutive_failures + 1 < $3),
+            disabled_at = CASE WHEN e.active AND NOT $2 AND e.consecutive_failures + 1 >= $3 THEN NOW() ELSE e.disabled_at END,
+            updated_at = NOW()
+        FROM (SELECT id, active FROM webhook_endpoints WHERE id = $1 FOR UPDATE) previous
+        WHERE e.id = previous.id
+        RETURNING previous.active AND NOT e.active
+    `
+	var disabled bool
+	if err := r.db.QueryRowxContext(ctx, query, id, success, disableAfter).Scan(&disabled); err != nil {
+		return false, err
+	}
+	return disabled, nil
+}
+
+// Enqueue queues an event for every active endpoint of an organization subscribed to its type and
+// returns how many deliveries were queued
+func (r *webhookRepository) Enqueue(ctx context.Context, orgID int64, eventID int64, eventType string, payload []byte) (int64, error) {
+	query := `
+        INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, next_attempt_at, created_at, updated_at)
+        SELECT id, $2, $3, $4, NOW(), NOW(), NOW()
+        FROM webhook_endpoints
+        WHERE organization_id = $1 AND active = TRUE AND $3 = ANY(event_types)
+    `
+	result, err := r.db.ExecContext(ctx, query, orgID, eventID, eventType, payload)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ClaimDue leases up to limit due deliveries of active endpoints to this instance and counts the
+// attempt. Rows locked by another instance's claim are skipped.
+func (r *webhookRepository) ClaimDue(ctx context.Context, lockedUntil time.Time, limit int) ([]*models.DueWebhookDelivery, error) {
+	var deliveries []*models.DueWebhookDelivery
+	query := `
+        UPDATE webhook_deliveries d
+        SET locked_until = $1, attempts = d.attempts + 1, updated_at = NOW()
+        FROM webhook_endpoints e
+        WHERE e.id = d.endpoint_id AND d.id IN (
+            SELECT wd.id
+            FROM webhook_deliveries wd
+            JOIN webhook_endpoints we ON we.id = wd.endpoint_id
+            WHERE wd.status = 'pending' AND wd.next_attempt_at <= NOW() AND wd.locked_until < NOW() AND we.active = TRUE
+            ORDER BY wd.next_attempt_at, wd.id
+            LIMIT $2
+            FOR UPDATE OF wd SKIP LOCKED
+        )
+        RETURNING d.*, e.url, e.secret
+    `
+	if err := r.db.SelectContext(ctx, &deliveries, query, lockedUntil, limit); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of an attempt and releases the delivery's lease
+func (r *webhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+        UPDATE webhook_deliveries
+        SET status = $1, next_attempt_at = $2, last_status_code = $3, last_error = $4, delivered_at = $5,
+            locked_until = NOW(), updated_at = NOW()
+        WHERE id = $6
+    `
+	_, err := r.db.ExecContext(ctx, query, delivery.Status, delivery.NextAttemptAt, delivery.LastStatusCode,
+		delivery.LastError, delivery.DeliveredAt, delivery.ID)
+	return err
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, endpointID, id int64) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	query := `SELECT * FROM webhook_deliveries WHERE id = $1 AND endpoint_id = $2`
+	if err := r.db.GetContext(ctx, delivery, query, id, endpointID); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ListDeliveries returns an endpoint's newest deliveries, optionally with one status
+func (r *webhookRepository) ListDeliveries(ctx context.Context, endpointID int64, status string, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	query := `
+        SELECT * FROM webhook_deliveries
+        WHERE endpoint_id = $1 AND ($2 = '' OR status = $2)
+        ORDER BY id DESC
+        LIMIT $3
+    `
+	if err := r.db.SelectContext(ctx, &deliveries, query, endpointID, status, limit); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Replay queues a delivery's payload again as a new delivery, keeping the original in the log
+func (r *webhookRepository) Replay(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	replay := &models.WebhookDelivery{}
+	query := `
+        INSERT INTO webhook_deliveries (endpoint_id, event_id, event_type, payload, replay_of, next_attempt_at, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NOW(), NOW(), NOW())
+        RETURNING *
+    `
+	if err := r.db.GetContext(ctx, replay, query, delivery.EndpointID, delivery.EventID, delivery.EventType,
+		[]byte(delivery.Payload), delivery.ID); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+// DeleteDeliveriesBefore removes finished deliveries created before the given time and returns how many were removed
+func (r *webhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE created_at < $1 AND status <> 'pending'`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
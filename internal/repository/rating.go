@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type RatingRepository interface {
+	Create(ctx context.Context, rating *models.Rating) error
+	GetByBookingID(ctx context.Context, bookingID int64) ([]*models.Rating, error)
+	GetByRateeID(ctx context.Context, rateeID int64) ([]*models.Rating, error)
+	GetSummary(ctx context.Context, rateeID int64) (*models.RatingSummary, error)
+	GetFlagged(ctx context.Context) ([]*models.Rating, error)
+	Review(ctx context.Context, id int64, adminID int64, note string) error
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type ReceiptRepository interface {
+	GetByBookingID(ctx context.Context, bookingID int64) (*models.Receipt, error)
+	Issue(ctx context.Context, bookingID int64) (*models.Receipt, bool, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type ServiceZoneRepository interface {
+	List(ctx context.Context) ([]*models.ServiceZone, error)
+	GetByCode(ctx context.Context, code string) (*models.ServiceZone, error)
+	Upsert(ctx context.Context, zone *models.ServiceZone) error
+	Delete(ctx context.Context, code string) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+type SMSOptOutRepository interface {
+	IsOptedOut(ctx context.Context, phone string) (bool, error)
+	List(ctx context.Context) ([]*models.SMSOptOut, error)
+	Add(ctx context.Context, phone, source string) error
+	Remove(ctx context.Context, phone string) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"time"
+)
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	GetEndpoint(ctx context.Context, orgID, id int64) (*models.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context, orgID int64) ([]*models.WebhookEndpoint, error)
+	UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	DeleteEndpoint(ctx context.Context, orgID, id int64) error
+	RecordEndpointResult(ctx context.Context, id int64, success bool, disableAfter int) (bool, error)
+
+	Enqueue(ctx context.Context, orgID int64, eventID int64, eventType string, payload []byte) (int64, error)
+	ClaimDue(ctx context.Context, lockedUntil time.Time, limit int) ([]*models.DueWebhookDelivery, error)
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, endpointID, id int64) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, endpointID int64, status string, limit int) ([]*models.WebhookDelivery, error)
+	Replay(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error)
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -7,7 +7,7 @@ import (
 )
 
 // SetupAdminRoutes configures all admin-related routes
-func SetupAdminRoutes(e *echo.Echo, authHandler *handlers.AuthHandler, userHandler *handlers.UserHandler, authMiddleware *middleware.AuthMiddleware) {
+func SetupAdminRoutes(e *echo.Echo, authHandler *handlers.AuthHandler, userHandler *handlers.UserHandler, bookRideHandler *handlers.BookRideHandler, paymentHandler *handlers.PaymentHandler, policyHandler *handlers.CancellationPolicyHandler, zoneHandler *handlers.ServiceZoneHandler, addOnHandler *handlers.AddOnHandler, authMiddleware *middleware.AuthMiddleware, idempotency echo.MiddlewareFunc) {
 	// Admin routes - require authentication and admin role
 	adminGroup := e.Group("/admin")
 	adminGroup.Use(authMiddleware.RequireAuth())
@@ -19,4 +19,35 @@ func SetupAdminRoutes(e *echo.Echo, authHandler *handlers.AuthHandler, userHandl
 	adminGroup.GET("/users/:id", userHandler.GetUserByID)
 	adminGroup.PUT("/users/:id/role", userHandler.UpdateUserRole)
 	adminGroup.DELETE("/users/:id", authHandler.DeleteUser)
+
+	// Booking fare management endpoints
+	adminGroup.PUT("/bookings/:id/fare", bookRideHandler.AdjustFare, idempotency)
+	adminGroup.GET("/bookings/:id/fare-adjustments", bookRideHandler.GetFareAdjustments)
+
+	// Booking approval endpoints
+	adminGroup.GET("/bookings/pending-approval", bookRideHandler.GetPendingApproval)
+	adminGroup.PUT("/bookings/:id/approve", bookRideHandler.Approve)
+
+	// Payment management endpoints
+	adminGroup.GET("/bookings/:id/payments", paymentHandler.GetBookingPayments)
+	adminGroup.POST("/bookings/:id/refund", paymentHandler.Refund, idempotency)
+	adminGroup.GET("/bookings/:id/refunds", paymentHandler.GetBookingRefunds)
+	adminGroup.GET("/bookings/payment-issues", bookRideHandler.GetPaymentIssues)
+	adminGroup.PUT("/bookings/:id/payment-issue/resolve", bookRideHandler.ResolvePaymentIssue)
+
+	// Cancellation policy endpoints
+	adminGroup.GET("/cancellation-policies", policyHandler.List)
+	adminGroup.PUT("/cancellation-policies/:ride_type", policyHandler.Upsert)
+	adminGroup.DELETE("/cancellation-policies/:ride_type", policyHandler.Delete)
+
+	// Service zone endpoints
+	adminGroup.GET("/service-zones", zoneHandler.List)
+	adminGroup.GET("/service-zones/:code", zoneHandler.Get)
+	adminGroup.PUT("/service-zones/:code", zoneHandler.Upsert)
+	adminGroup.DELETE("/service-zones/:code", zoneHandler.Delete)
+
+	// Add-on catalog endpoints
+	adminGroup.GET("/add-ons", addOnHandler.List)
+	adminGroup.PUT("/add-ons/:code", addOnHandler.Upsert)
+	adminGroup.DELETE("/add-ons/:code", addOnHandler.Delete)
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupAPIKeyRoutes configures partner API key management routes
+func SetupAPIKeyRoutes(e *echo.Echo, apiKeyHandler *handlers.APIKeyHandler, authMiddleware *middleware.AuthMiddleware) {
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(authMiddleware.RequireAuth())
+	adminGroup.Use(authMiddleware.RequireAdmin())
+	adminGroup.GET("/api-keys", apiKeyHandler.List)
+	adminGroup.POST("/api-keys", apiKeyHandler.Create)
+	adminGroup.PUT("/api-keys/:id", apiKeyHandler.Update)
+	adminGroup.POST("/api-keys/:id/rotate", apiKeyHandler.Rotate)
+	adminGroup.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
+}
@@ -7,20 +7,23 @@ import (
 )
 
 // SetupBookingRoutes configures all booking-related routes
-func SetupBookingRoutes(e *echo.Echo, bookRideHandler *handlers.BookRideHandler, authMiddleware *middleware.AuthMiddleware) {
+func SetupBookingRoutes(e *echo.Echo, bookRideHandler *handlers.BookRideHandler, seriesHandler *handlers.BookingSeriesHandler, addOnHandler *handlers.AddOnHandler, authMiddleware *middleware.AuthMiddleware, idempotency echo.MiddlewareFunc) {
 	// Public booking routes (no authentication required)
 	publicBookingGroup := e.Group("/bookings")
 	
 	// Create booking (supports both authenticated and guest users)
-	e.POST("/book-ride", bookRideHandler.Create, authMiddleware.OptionalAuth())
+	e.POST("/book-ride", bookRideHandler.Create, authMiddleware.OptionalAuth(), idempotency)
+
+	// Add-on catalog shown while booking
+	e.GET("/add-ons", addOnHandler.Catalog)
 	
 	// Guest booking management
 	publicBookingGroup.GET("/email/:email", bookRideHandler.GetByEmail)
 	publicBookingGroup.POST("/:id/update-link", bookRideHandler.GenerateUpdateLink)
 	
 	// Public update/cancel with secure token (for guest users)
-	publicBookingGroup.PUT("/:id/update", bookRideHandler.Update)
-	publicBookingGroup.DELETE("/:id/cancel", bookRideHandler.Cancel)
+	publicBookingGroup.PUT("/:id/update", bookRideHandler.Update, idempotency)
+	publicBookingGroup.DELETE("/:id/cancel", bookRideHandler.Cancel, idempotency)
 
 	// Protected booking routes (require authentication)
 	protectedBookingGroup := e.Group("/bookings")
@@ -31,13 +34,24 @@ func SetupBookingRoutes(e *echo.Echo, bookRideHandler *handlers.BookRideHandler,
 	
 	// Note: These routes are also handled by the public routes above with token validation
 	// but we keep them here for authenticated users who don't need tokens
-	protectedBookingGroup.PUT("/:id", bookRideHandler.Update)
-	protectedBookingGroup.DELETE("/:id/cancel", bookRideHandler.Cancel)
+	protectedBookingGroup.PUT("/:id", bookRideHandler.Update, idempotency)
+	protectedBookingGroup.DELETE("/:id/cancel", bookRideHandler.Cancel, idempotency)
+
+	// Recurring booking series; single occurrences use the booking routes above
+	protectedBookingGroup.POST("/series", seriesHandler.Create, idempotency)
+	protectedBookingGroup.GET("/series", seriesHandler.GetByUserID)
+	protectedBookingGroup.GET("/series/:id", seriesHandler.GetByID)
+	protectedBookingGroup.PUT("/series/:id", seriesHandler.Update, idempotency)
+	protectedBookingGroup.DELETE("/series/:id/cancel", seriesHandler.Cancel, idempotency)
 
 	// Driver-specific routes
 	driverGroup := e.Group("/driver")
 	driverGroup.Use(authMiddleware.RequireAuth())
 	driverGroup.Use(authMiddleware.RequireDriver())
 	
+	driverGroup.GET("/bookings", bookRideHandler.GetDriverRides)
+	driverGroup.GET("/bookings/:id", bookRideHandler.GetDriverRide)
 	driverGroup.PUT("/bookings/:id/accept", bookRideHandler.Accept)
+	driverGroup.PUT("/bookings/:id/start", bookRideHandler.StartRide)
+	driverGroup.PUT("/bookings/:id/complete", bookRideHandler.Complete, idempotency)
 }
\ No newline at end of file
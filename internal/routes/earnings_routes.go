@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupEarningsRoutes configures driver earnings and payout batch routes
+func SetupEarningsRoutes(e *echo.Echo, earningsHandler *handlers.EarningsHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Drivers see their earnings and payouts
+	driverGroup := e.Group("/driver")
+	driverGroup.Use(authMiddleware.RequireAuth())
+	driverGroup.Use(authMiddleware.RequireDriver())
+	driverGroup.GET("/earnings", earningsHandler.GetEarnings)
+	driverGroup.GET("/payouts", earningsHandler.GetDriverPayouts)
+
+	// Admins generate weekly payout batches and track each payout
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(authMiddleware.RequireAuth())
+	adminGroup.Use(authMiddleware.RequireAdmin())
+	adminGroup.POST("/payouts/batches", earningsHandler.GeneratePayoutBatch)
+	adminGroup.GET("/payouts/batches", earningsHandler.GetPayoutBatches)
+	adminGroup.GET("/payouts/batches/:id", earningsHandler.GetPayoutBatch)
+	adminGroup.GET("/payouts/batches/:id/export", earningsHandler.ExportPayoutBatch)
+	adminGroup.PUT("/payouts/:id/paid", earningsHandler.MarkPayoutPaid)
+	adminGroup.PUT("/payouts/:id/failed", earningsHandler.MarkPayoutFailed)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupEventRoutes configures the real-time booking event streams
+func SetupEventRoutes(e *echo.Echo, eventHandler *handlers.EventHandler, authMiddleware *middleware.AuthMiddleware) {
+	// EventSource and WebSocket clients may pass the token as ?access_token
+	eventGroup := e.Group("/events")
+	eventGroup.Use(authMiddleware.QueryToken())
+	eventGroup.Use(authMiddleware.RequireAuth())
+	eventGroup.GET("", eventHandler.Stream)
+	eventGroup.GET("/ws", eventHandler.WebSocket)
+}
@@ -31,15 +31,71 @@ func SetupHealthRoutes(e *echo.Echo) {
 					"POST /auth/reset-password",
 					"GET /users/me",
 					"PUT /users/me/password",
+					"GET /users/me/notification-preferences",
+					"PUT /users/me/notification-preferences",
 				},
 				"bookings": []string{
 					"POST /book-ride",
+					"GET /add-ons",
 					"GET /bookings/email/:email",
 					"POST /bookings/:id/update-link",
 					"GET /bookings/my",
 					"PUT /bookings/:id",
 					"DELETE /bookings/:id/cancel",
+					"POST /bookings/series",
+					"GET /bookings/series",
+					"GET /bookings/series/:id",
+					"PUT /bookings/series/:id",
+					"DELETE /bookings/series/:id/cancel",
+					"POST /bookings/:id/rating",
+					"POST /bookings/:id/tip",
+					"GET /bookings/:id/adjustments",
+					"PUT /bookings/:id/adjustments/:adjustmentId/approve",
+					"PUT /bookings/:id/adjustments/:adjustmentId/reject",
+					"GET /bookings/:id/receipt",
+					"GET /bookings/:id/tracking",
+					"GET /drivers/:id/profile",
+					"POST /driver/bookings/:id/rating",
+					"GET /driver/bookings",
+					"GET /driver/bookings/:id",
 					"PUT /driver/bookings/:id/accept",
+					"PUT /driver/bookings/:id/start",
+					"POST /driver/bookings/:id/locations",
+					"PUT /driver/bookings/:id/arriving",
+					"PUT /driver/bookings/:id/complete",
+					"POST /driver/bookings/:id/extras",
+					"GET /driver/earnings",
+					"GET /driver/payouts",
+					"GET /organizations/mine",
+					"GET /organizations/:id",
+					"PUT /organizations/:id/approval-rules",
+					"GET /organizations/:id/members",
+					"POST /organizations/:id/members",
+					"PUT /organizations/:id/members/:userId",
+					"DELETE /organizations/:id/members/:userId",
+					"GET /organizations/:id/cost-centers",
+					"PUT /organizations/:id/cost-centers/:code",
+					"GET /organizations/:id/bookings",
+					"PUT /organizations/:id/bookings/:bookingId/approve",
+					"PUT /organizations/:id/bookings/:bookingId/reject",
+					"GET /organizations/:id/invoices",
+					"GET /organizations/:id/invoices/:invoiceId",
+					"GET /organizations/:id/webhooks",
+					"POST /organizations/:id/webhooks",
+					"PUT /organizations/:id/webhooks/:webhookId",
+					"DELETE /organizations/:id/webhooks/:webhookId",
+					"GET /organizations/:id/webhooks/:webhookId/deliveries",
+					"POST /organizations/:id/webhooks/:webhookId/deliveries/:deliveryId/replay",
+				},
+				"events": []string{
+					"GET /events",
+					"GET /events/ws",
+				},
+				"payments": []string{
+					"POST /payments/webhook",
+				},
+				"sms": []string{
+					"POST /sms/inbound",
 				},
 				"admin": []string{
 					"GET /admin/users",
@@ -47,6 +103,52 @@ func SetupHealthRoutes(e *echo.Echo) {
 					"GET /admin/users/:id",
 					"PUT /admin/users/:id/role",
 					"DELETE /admin/users/:id",
+					"PUT /admin/bookings/:id/fare",
+					"GET /admin/bookings/:id/fare-adjustments",
+					"GET /admin/bookings/pending-approval",
+					"PUT /admin/bookings/:id/approve",
+					"GET /admin/bookings/:id/payments",
+					"POST /admin/bookings/:id/refund",
+					"GET /admin/bookings/:id/refunds",
+					"GET /admin/bookings/payment-issues",
+					"PUT /admin/bookings/:id/payment-issue/resolve",
+					"GET /admin/cancellation-policies",
+					"PUT /admin/cancellation-policies/:ride_type",
+					"DELETE /admin/cancellation-policies/:ride_type",
+					"GET /admin/service-zones",
+					"GET /admin/service-zones/:code",
+					"PUT /admin/service-zones/:code",
+					"DELETE /admin/service-zones/:code",
+					"GET /admin/ratings/flagged",
+					"PUT /admin/ratings/:id/review",
+					"GET /admin/users/:id/ratings",
+					"GET /admin/bookings/:id/adjustments",
+					"PUT /admin/bookings/:id/adjustments/:adjustmentId/approve",
+					"PUT /admin/bookings/:id/adjustments/:adjustmentId/reject",
+					"GET /admin/legal-entities",
+					"PUT /admin/legal-entities/:code",
+					"POST /admin/payouts/batches",
+					"GET /admin/payouts/batches",
+					"GET /admin/payouts/batches/:id",
+					"GET /admin/payouts/batches/:id/export",
+					"PUT /admin/payouts/:id/paid",
+					"PUT /admin/payouts/:id/failed",
+					"POST /admin/organizations",
+					"GET /admin/organizations",
+					"PUT /admin/organizations/:id",
+					"POST /admin/organizations/:id/invoices",
+					"GET /admin/add-ons",
+					"PUT /admin/add-ons/:code",
+					"DELETE /admin/add-ons/:code",
+					"GET /admin/sms/opt-outs",
+					"POST /admin/sms/opt-outs",
+					"DELETE /admin/sms/opt-outs/:phone",
+					"GET /admin/jobs",
+					"GET /admin/api-keys",
+					"POST /admin/api-keys",
+					"PUT /admin/api-keys/:id",
+					"POST /admin/api-keys/:id/rotate",
+					"DELETE /admin/api-keys/:id",
 				},
 			},
 		})
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupJobRoutes configures scheduled job monitoring routes
+func SetupJobRoutes(e *echo.Echo, jobHandler *handlers.JobHandler, authMiddleware *middleware.AuthMiddleware) {
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(authMiddleware.RequireAuth())
+	adminGroup.Use(authMiddleware.RequireAdmin())
+	adminGroup.GET("/jobs", jobHandler.ListJobs)
+}
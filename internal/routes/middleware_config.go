@@ -12,6 +12,8 @@ import (
 type MiddlewareConfig struct {
 	GeneralRateLimiter echomiddleware.RateLimiterConfig
 	AuthRateLimiter    echomiddleware.RateLimiterConfig
+	// Idempotency handles Idempotency-Key retries; add it to mutating routes after authentication
+	Idempotency echo.MiddlewareFunc
 }
 
 // SetupGlobalMiddleware configures global middleware for the Echo instance
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupNotificationRoutes configures notification preference routes
+func SetupNotificationRoutes(e *echo.Echo, notificationHandler *handlers.NotificationHandler, authMiddleware *middleware.AuthMiddleware) {
+	protectedGroup := e.Group("/users/me")
+	protectedGroup.Use(authMiddleware.RequireAuth())
+	protectedGroup.GET("/notification-preferences", notificationHandler.GetPreferences)
+	protectedGroup.PUT("/notification-preferences", notificationHandler.UpdatePreferences)
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupOrganizationRoutes configures corporate account routes
+func SetupOrganizationRoutes(e *echo.Echo, orgHandler *handlers.OrganizationHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Admins open corporate accounts and issue invoices
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(authMiddleware.RequireAuth())
+	adminGroup.Use(authMiddleware.RequireAdmin())
+	adminGroup.POST("/organizations", orgHandler.Create)
+	adminGroup.GET("/organizations", orgHandler.List)
+	adminGroup.PUT("/organizations/:id", orgHandler.Update)
+	adminGroup.POST("/organizations/:id/invoices", orgHandler.GenerateInvoice)
+
+	// Members see their organization; org admins (and admins) manage it
+	orgGroup := e.Group("/organizations")
+	orgGroup.Use(authMiddleware.RequireAuth())
+	orgGroup.GET("/mine", orgHandler.GetMine)
+	orgGroup.GET("/:id", orgHandler.Get)
+	orgGroup.PUT("/:id/approval-rules", orgHandler.UpdateApprovalRules)
+	orgGroup.GET("/:id/members", orgHandler.GetMembers)
+	orgGroup.POST("/:id/members", orgHandler.AddMember)
+	orgGroup.PUT("/:id/members/:userId", orgHandler.UpdateMember)
+	orgGroup.DELETE("/:id/members/:userId", orgHandler.RemoveMember)
+	orgGroup.GET("/:id/cost-centers", orgHandler.GetCostCenters)
+	orgGroup.PUT("/:id/cost-centers/:code", orgHandler.UpsertCostCenter)
+	orgGroup.GET("/:id/bookings", orgHandler.GetBookings)
+	orgGroup.PUT("/:id/bookings/:bookingId/approve", orgHandler.ApproveBooking)
+	orgGroup.PUT("/:id/bookings/:bookingId/reject", orgHandler.RejectBooking)
+	orgGroup.GET("/:id/invoices", orgHandler.GetInvoices)
+	orgGroup.GET("/:id/invoices/:invoiceId", orgHandler.GetInvoice)
+}
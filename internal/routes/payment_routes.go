@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupPaymentRoutes configures payment provider callback routes
+func SetupPaymentRoutes(e *echo.Echo, paymentHandler *handlers.PaymentHandler) {
+	// Provider webhooks are authenticated by their HMAC signature, not by JWT
+	e.POST("/payments/webhook", paymentHandler.Webhook)
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupPostRideRoutes configures tip and post-ride extra routes
+func SetupPostRideRoutes(e *echo.Echo, postRideHandler *handlers.PostRideHandler, authMiddleware *middleware.AuthMiddleware, idempotency echo.MiddlewareFunc) {
+	// Riders tip and approve extras (authenticated users, or guests with a secure token)
+	e.POST("/bookings/:id/tip", postRideHandler.AddTip, authMiddleware.OptionalAuth(), idempotency)
+	e.GET("/bookings/:id/adjustments", postRideHandler.GetAdjustments, authMiddleware.OptionalAuth())
+	e.PUT("/bookings/:id/adjustments/:adjustmentId/approve", postRideHandler.Approve, authMiddleware.OptionalAuth(), idempotency)
+	e.PUT("/bookings/:id/adjustments/:adjustmentId/reject", postRideHandler.Reject, authMiddleware.OptionalAuth(), idempotency)
+
+	// Drivers record tolls, parking and waiting time
+	driverGroup := e.Group("/driver")
+	driverGroup.Use(authMiddleware.RequireAuth())
+	driverGroup.Use(authMiddleware.RequireDriver())
+	driverGroup.POST("/bookings/:id/extras", postRideHandler.AddExtra, idempotency)
+
+	// Admins review extras on behalf of riders
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(authMiddleware.RequireAuth())
+	adminGroup.Use(authMiddleware.RequireAdmin())
+	adminGroup.GET("/bookings/:id/adjustments", postRideHandler.AdminGetAdjustments)
+	adminGroup.PUT("/bookings/:id/adjustments/:adjustmentId/approve", postRideHandler.AdminApprove, idempotency)
+	adminGroup.PUT("/bookings/:id/adjustments/:adjustmentId/reject", postRideHandler.AdminReject)
+}
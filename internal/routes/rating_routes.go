@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupRatingRoutes configures rating, driver profile and rating review routes
+func SetupRatingRoutes(e *echo.Echo, ratingHandler *handlers.RatingHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Riders rate their driver (authenticated users, or guests with a secure token)
+	e.POST("/bookings/:id/rating", ratingHandler.RateDriver, authMiddleware.OptionalAuth())
+
+	// Public driver profiles with rating summary
+	e.GET("/drivers/:id/profile", ratingHandler.GetDriverProfile)
+
+	// Drivers rate their rider
+	driverGroup := e.Group("/driver")
+	driverGroup.Use(authMiddleware.RequireAuth())
+	driverGroup.Use(authMiddleware.RequireDriver())
+	driverGroup.POST("/bookings/:id/rating", ratingHandler.RateRider)
+
+	// Rating review for admins
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(authMiddleware.RequireAuth())
+	adminGroup.Use(authMiddleware.RequireAdmin())
+	adminGroup.GET("/ratings/flagged", ratingHandler.GetFlagged)
+	adminGroup.PUT("/ratings/:id/review", ratingHandler.Review)
+	adminGroup.GET("/users/:id/ratings", ratingHandler.GetUserRatings)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupReceiptRoutes configures receipt and legal entity routes
+func SetupReceiptRoutes(e *echo.Echo, receiptHandler *handlers.ReceiptHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Receipts of completed bookings (owners and admins, or guests with a secure token)
+	e.GET("/bookings/:id/receipt", receiptHandler.GetReceipt, authMiddleware.OptionalAuth())
+
+	// Companies that issue receipts, managed by admins
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(authMiddleware.RequireAuth())
+	adminGroup.Use(authMiddleware.RequireAdmin())
+	adminGroup.GET("/legal-entities", receiptHandler.ListLegalEntities)
+	adminGroup.PUT("/legal-entities/:code", receiptHandler.UpsertLegalEntity)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupSMSRoutes configures SMS provider callbacks and opt-out management
+func SetupSMSRoutes(e *echo.Echo, smsHandler *handlers.SMSHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Rider replies forwarded by the provider are authenticated by their HMAC signature, not by JWT
+	e.POST("/sms/inbound", smsHandler.Inbound)
+
+	adminGroup := e.Group("/admin")
+	adminGroup.Use(authMiddleware.RequireAuth())
+	adminGroup.Use(authMiddleware.RequireAdmin())
+	adminGroup.GET("/sms/opt-outs", smsHandler.ListOptOuts)
+	adminGroup.POST("/sms/opt-outs", smsHandler.OptOut)
+	adminGroup.DELETE("/sms/opt-outs/:phone", smsHandler.OptIn)
+}
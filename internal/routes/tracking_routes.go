@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupTrackingRoutes configures live driver location routes
+func SetupTrackingRoutes(e *echo.Echo, trackingHandler *handlers.TrackingHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Riders follow their driver (owners and admins, or guests with a secure token)
+	e.GET("/bookings/:id/tracking", trackingHandler.GetTracking, authMiddleware.OptionalAuth())
+
+	// Drivers upload GPS pings while on a ride and tell the rider when they are arriving
+	driverGroup := e.Group("/driver")
+	driverGroup.Use(authMiddleware.RequireAuth())
+	driverGroup.Use(authMiddleware.RequireDriver())
+	driverGroup.POST("/bookings/:id/locations", trackingHandler.PostLocations)
+	driverGroup.PUT("/bookings/:id/arriving", trackingHandler.Arriving)
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/diagnosis/luxsuv-v4/internal/handlers"
+	"github.com/diagnosis/luxsuv-v4/internal/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+// SetupWebhookRoutes configures the outbound webhook routes of corporate accounts
+func SetupWebhookRoutes(e *echo.Echo, webhookHandler *handlers.WebhookHandler, authMiddleware *middleware.AuthMiddleware) {
+	// Org admins (and admins) manage their organization's endpoints
+	orgGroup := e.Group("/organizations")
+	orgGroup.Use(authMiddleware.RequireAuth())
+	orgGroup.GET("/:id/webhooks", webhookHandler.List)
+	orgGroup.POST("/:id/webhooks", webhookHandler.Create)
+	orgGroup.PUT("/:id/webhooks/:webhookId", webhookHandler.Update)
+	orgGroup.DELETE("/:id/webhooks/:webhookId", webhookHandler.Delete)
+	orgGroup.GET("/:id/webhooks/:webhookId/deliveries", webhookHandler.GetDeliveries)
+	orgGroup.POST("/:id/webhooks/:webhookId/deliveries/:deliveryId/replay", webhookHandler.Replay)
+}
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// Schedule decides when a job runs next
+type Schedule interface {
+	// First returns when a job that has never run should first run
+	First(now time.Time) time.Time
+	// Next returns when the job runs after a run that finished at now
+	Next(now time.Time) time.Time
+	// String describes the schedule; a job whose description changes is rescheduled
+	String() string
+}
+
+type every time.Duration
+
+// Every runs a job at a fixed interval, starting immediately
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+func (e every) First(now time.Time) time.Time {
+	return now
+}
+
+func (e every) Next(now time.Time) time.Time {
+	return now.Add(time.Duration(e))
+}
+
+func (e every) String() string {
+	return "every " + time.Duration(e).String()
+}
+
+type daily struct {
+	hour, minute int
+	loc          *time.Location
+}
+
+// Daily runs a job once a day at the given wall clock time
+func Daily(hour, minute int, loc *time.Location) Schedule {
+	return daily{hour: hour, minute: minute, loc: loc}
+}
+
+func (d daily) First(now time.Time) time.Time {
+	return d.Next(now)
+}
+
+func (d daily) Next(now time.Time) time.Time {
+	local := now.In(d.loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), d.hour, d.minute, 0, 0, d.loc)
+	if !next.After(now) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, d.hour, d.minute, 0, 0, d.loc)
+	}
+	return next
+}
+
+func (d daily) String() string {
+	return fmt.Sprintf("daily at %02d:%02d %s", d.hour, d.minute, d.loc)
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+// pollInterval is how often each instance checks for due jobs
+const pollInterval = 30 * time.Second
+
+// Job is a task the scheduler runs on one instance at a time
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Timeout bounds a run and is the length of its lease; another instance may take over
+	// a job whose run outlives it
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Scheduler runs jobs when they are due. Every instance runs a scheduler; the job leases in the
+// database make sure each run happens on one of them. A run that fails is not retried before
+// its next scheduled time.
+type Scheduler struct {
+	leases repository.JobLeaseRepository
+	logger *logger.Logger
+	holder string
+	jobs   []Job
+}
+
+func New(leases repository.JobLeaseRepository, logger *logger.Logger) *Scheduler {
+	return &Scheduler{
+		leases: leases,
+		logger: logger,
+		holder: instanceID(),
+	}
+}
+
+// instanceID names this process in the leases it holds
+func instanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Add schedules a job; jobs must be added before Run
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Run registers the jobs and runs those that are due until the context is cancelled. A job whose
+// schedule changed since it was last registered is moved to its new first run.
+func (s *Scheduler) Run(ctx context.Context) {
+	now := time.Now()
+	for _, job := range s.jobs {
+		if err := s.leases.Register(ctx, job.Name, job.Schedule.String(), job.Schedule.First(now)); err != nil {
+			s.logger.Err(fmt.Sprintf("Failed to register job %s: %s", job.Name, err.Error()))
+		}
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, job := range s.jobs {
+			s.runIfDue(ctx, job)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runIfDue runs a job if it is due and no other instance holds its lease
+func (s *Scheduler) runIfDue(ctx context.Context, job Job) {
+	acquired, err := s.leases.Acquire(ctx, job.Name, s.holder, time.Now().Add(job.Timeout))
+	if err != nil {
+		s.logger.Err(fmt.Sprintf("Failed to acquire lease of job %s: %s", job.Name, err.Error()))
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	started := time.Now()
+	runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	runErr := job.Run(runCtx)
+	cancel()
+
+	lastError := ""
+	if runErr != nil {
+		lastError = runErr.Error()
+		s.logger.Err(fmt.Sprintf("Job %s failed after %s: %s", job.Name, time.Since(started).Round(time.Millisecond), lastError))
+	} else {
+		s.logger.Info(fmt.Sprintf("Job %s finished in %s", job.Name, time.Since(started).Round(time.Millisecond)))
+	}
+
+	if err := s.leases.Release(context.WithoutCancel(ctx), job.Name, s.holder, job.Schedule.Next(time.Now()), lastError); err != nil {
+		s.logger.Err(fmt.Sprintf("Failed to release lease of job %s: %s", job.Name, err.Error()))
+	}
+}
+
+// List returns the schedule and lease of every job
+func (s *Scheduler) List(ctx context.Context) ([]*models.JobLease, error) {
+	return s.leases.List(ctx)
+}
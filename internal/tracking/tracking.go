@@ -0,0 +1,221 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/geo"
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+var (
+	// ErrNotSharing is returned for pings on a ride that is not assigned to the driver or has ended
+	ErrNotSharing = errors.New("location sharing is not active for this ride")
+	// ErrRateLimited is returned when a driver uploads pings more often than allowed
+	ErrRateLimited = errors.New("location uploads are too frequent")
+	// ErrInvalidPing wraps the reasons a batch of pings is rejected
+	ErrInvalidPing = errors.New("invalid location ping")
+)
+
+const (
+	// MaxBatchSize is the most pings accepted in one upload
+	MaxBatchSize = 50
+	// maxPingAge is how old a ping may be when it is uploaded, so queued pings survive short signal loss
+	maxPingAge = time.Hour
+	// clockSkew tolerates device clocks running slightly ahead
+	clockSkew = time.Minute
+	// staleAfter marks the last location as stale when the driver has not reported for this long
+	staleAfter = 5 * time.Minute
+	// speedWindow is how far back pings are averaged to estimate the driver's speed
+	speedWindow = 5 * time.Minute
+	// Speeds are clamped to this range so a parked or speeding car does not skew the ETA
+	minSpeedKmh     = 15.0
+	maxSpeedKmh     = 110.0
+	defaultSpeedKmh = 40.0
+	// roadFactor converts straight-line distance to an estimate of the driving distance
+	roadFactor = 1.3
+	// forgetUploadsAfter is how long a driver's last upload time is kept for rate limiting
+	forgetUploadsAfter = time.Hour
+)
+
+// Service records drivers' GPS pings during a ride and estimates their arrival for riders
+type Service struct {
+	repo        repository.DriverLocationRepository
+	logger      *logger.Logger
+	minInterval time.Duration
+	retention   time.Duration
+
+	mu         sync.Mutex
+	lastUpload map[int64]time.Time // Per driver
+	lastSweep  time.Time
+}
+
+func NewService(repo repository.DriverLocationRepository, logger *logger.Logger, minInterval, retention time.Duration) *Service {
+	return &Service{
+		repo:        repo,
+		logger:      logger,
+		minInterval: minInterval,
+		retention:   retention,
+		lastUpload:  make(map[int64]time.Time),
+	}
+}
+
+// Sharing reports whether the driver's location is shared for a booking: from acceptance until the
+// ride completes or is cancelled
+func Sharing(booking *models.BookRide) bool {
+	return booking.DriverID != nil && booking.BookStatus == models.BookStatusAccepted
+}
+
+// RecordPings stores a batch of pings for a ride assigned to the driver. Uploads are limited to one
+// per minimum interval per driver, and pings are sorted by the time they were recorded.
+func (s *Service) RecordPings(ctx context.Context, driverID int64, booking *models.BookRide, pings []models.DriverLocation) error {
+	if !Sharing(booking) || *booking.DriverID != driverID {
+		return ErrNotSharing
+	}
+	if len(pings) == 0 || len(pings) > MaxBatchSize {
+		return fmt.Errorf("%w: send between 1 and %d pings", ErrInvalidPing, MaxBatchSize)
+	}
+
+	now := time.Now()
+	for i := range pings {
+		ping := &pings[i]
+		if ping.Lat < -90 || ping.Lat > 90 || ping.Lng < -180 || ping.Lng > 180 {
+			return fmt.Errorf("%w: coordinates out of range", ErrInvalidPing)
+		}
+		if ping.SpeedKmh != nil && (*ping.SpeedKmh < 0 || *ping.SpeedKmh > 300) {
+			return fmt.Errorf("%w: speed must be between 0 and 300 km/h", ErrInvalidPing)
+		}
+		if ping.Heading != nil && (*ping.Heading < 0 || *ping.Heading >= 360) {
+			return fmt.Errorf("%w: heading must be between 0 and 360 degrees", ErrInvalidPing)
+		}
+		if ping.RecordedAt.IsZero() || ping.RecordedAt.After(now.Add(clockSkew)) || ping.RecordedAt.Before(now.Add(-maxPingAge)) {
+			return fmt.Errorf("%w: recorded_at must be within the last hour", ErrInvalidPing)
+		}
+		ping.DriverID = driverID
+		ping.BookingID = booking.ID
+	}
+	sort.Slice(pings, func(i, j int) bool { return pings[i].RecordedAt.Before(pings[j].RecordedAt) })
+
+	if !s.allowUpload(driverID, now) {
+		return ErrRateLimited
+	}
+	return s.repo.Insert(ctx, pings)
+}
+
+// allowUpload enforces the minimum interval between a driver's uploads
+func (s *Service) allowUpload(driverID int64, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.lastUpload[driverID]; ok && now.Sub(last) < s.minInterval {
+		return false
+	}
+	s.lastUpload[driverID] = now
+
+	// Forget drivers that have stopped uploading. Each instance limits its own uploads, so this
+	// happens here rather than in the cleanup job, which runs on one instance.
+	if now.Sub(s.lastSweep) > forgetUploadsAfter {
+		for id, last := range s.lastUpload {
+			if now.Sub(last) > forgetUploadsAfter {
+				delete(s.lastUpload, id)
+			}
+		}
+		s.lastSweep = now
+	}
+	return true
+}
+
+// Track returns the rider's view of a ride: the driver's last known location and an ETA to the pickup,
+// or to the drop-off once the ride is in progress. Nothing is shared once the ride has ended.
+func (s *Service) Track(ctx context.Context, booking *models.BookRide) (*models.RideTracking, error) {
+	tracking := &models.RideTracking{
+		BookingID:  booking.ID,
+		RideStatus: booking.RideStatus,
+		Sharing:    Sharing(booking),
+	}
+	if !tracking.Sharing {
+		return tracking, nil
+	}
+
+	location, err := s.repo.GetLatest(ctx, booking.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tracking, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tracking.Location = location
+	tracking.Stale = now.Sub(location.RecordedAt) > staleAfter
+
+	tracking.Target = models.TrackingTargetPickup
+	target := booking.Pickup
+	if booking.RideStatus == models.RideStatusInProgress {
+		tracking.Target = models.TrackingTargetDropoff
+		target = booking.Dropoff
+	}
+	if target == nil || target.Lat == nil || target.Lng == nil {
+		return tracking, nil // Hourly charters and ungeocoded addresses have no ETA
+	}
+
+	recent, err := s.repo.GetRecent(ctx, booking.ID, location.RecordedAt.Add(-speedWindow))
+	if err != nil {
+		return nil, err
+	}
+	distance := geo.DistanceKm(&models.Location{Lat: &location.Lat, Lng: &location.Lng}, target) * roadFactor
+	minutes := int(math.Ceil(distance / estimateSpeed(recent) * 60))
+	eta := now.Add(time.Duration(minutes) * time.Minute)
+	distance = math.Round(distance*10) / 10
+	tracking.DistanceKm = &distance
+	tracking.ETAMinutes = &minutes
+	tracking.ETA = &eta
+	return tracking, nil
+}
+
+// estimateSpeed averages the speeds reported by recent pings, or derives the speed from the distance
+// they cover when the device does not report it
+func estimateSpeed(pings []*models.DriverLocation) float64 {
+	var sum float64
+	var reported int
+	for _, ping := range pings {
+		if ping.SpeedKmh != nil {
+			sum += *ping.SpeedKmh
+			reported++
+		}
+	}
+
+	speed := defaultSpeedKmh
+	if reported > 0 {
+		speed = sum / float64(reported)
+	} else if len(pings) >= 2 {
+		first, last := pings[0], pings[len(pings)-1]
+		if hours := last.RecordedAt.Sub(first.RecordedAt).Hours(); hours > 0 {
+			var km float64
+			for i := 1; i < len(pings); i++ {
+				km += geo.DistanceKm(&models.Location{Lat: &pings[i-1].Lat, Lng: &pings[i-1].Lng},
+					&models.Location{Lat: &pings[i].Lat, Lng: &pings[i].Lng})
+			}
+			speed = km / hours
+		}
+	}
+	return math.Min(math.Max(speed, minSpeedKmh), maxSpeedKmh)
+}
+
+// Cleanup deletes pings past their retention; run as a scheduled job
+func (s *Service) Cleanup(ctx context.Context) error {
+	deleted, err := s.repo.DeleteBefore(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return fmt.Errorf("failed to delete expired driver locations: %w", err)
+	}
+	if deleted > 0 {
+		s.logger.Info(fmt.Sprintf("Deleted %d expired driver locations", deleted))
+	}
+	return nil
+}
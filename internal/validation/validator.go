@@ -2,16 +2,25 @@ package validation
 
 import (
 	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 	"unicode"
 
+	"github.com/diagnosis/luxsuv-v4/internal/geo"
 	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/notify"
+	"github.com/diagnosis/luxsuv-v4/internal/webhooks"
 )
 
 var (
-	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	emailRegex        = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	airlineRegex      = regexp.MustCompile(`^[A-Z0-9]{2,3}$`)
+	flightNumberRegex = regexp.MustCompile(`^([A-Z0-9]{2,3})?[0-9]{1,4}[A-Z]?$`)
 )
 
 // ValidateUserRegistration validates user registration data
@@ -124,8 +133,9 @@ func ValidateLoginInput(email, password string) error {
 	return nil
 }
 
-// ValidateBookRide validates the book ride data
-func ValidateBookRide(br *models.BookRide) error {
+// ValidateBookRide validates the book ride data. Phone numbers without a country code are taken
+// to be in countryCode.
+func ValidateBookRide(br *models.BookRide, countryCode string) error {
 	if br.YourName = strings.TrimSpace(br.YourName); br.YourName == "" {
 		return errors.New("name is required")
 	}
@@ -140,20 +150,72 @@ func ValidateBookRide(br *models.BookRide) error {
 	if br.PhoneNumber = strings.TrimSpace(br.PhoneNumber); br.PhoneNumber == "" {
 		return errors.New("phone number is required")
 	}
-	if len(br.PhoneNumber) < 7 || len(br.PhoneNumber) > 20 {
-		return errors.New("phone number must be between 7 and 20 characters")
+	if err := ValidatePhone("phone number", &br.PhoneNumber, countryCode); err != nil {
+		return err
+	}
+
+	br.PassengerName = strings.TrimSpace(br.PassengerName)
+	br.PassengerEmail = strings.TrimSpace(strings.ToLower(br.PassengerEmail))
+	br.PassengerPhone = strings.TrimSpace(br.PassengerPhone)
+	if err := ValidatePassenger(br.PassengerName, br.PassengerEmail, &br.PassengerPhone, countryCode); err != nil {
+		return err
+	}
+	if err := ValidateNotifications(br.Notifications); err != nil {
+		return err
 	}
 
 	if br.RideType = strings.TrimSpace(br.RideType); br.RideType == "" {
 		return errors.New("ride type is required")
 	}
 
+	// A structured location without raw text uses its formatted address as the text
+	if br.PickupLocation == "" && br.Pickup != nil {
+		br.PickupLocation = br.Pickup.FormattedAddress
+	}
+	if br.DropoffLocation == "" && br.Dropoff != nil {
+		br.DropoffLocation = br.Dropoff.FormattedAddress
+	}
+
 	if br.PickupLocation = strings.TrimSpace(br.PickupLocation); br.PickupLocation == "" {
 		return errors.New("pickup location is required")
 	}
+	if err := ValidateLocation("pickup", br.Pickup); err != nil {
+		return err
+	}
 
-	if br.DropoffLocation = strings.TrimSpace(br.DropoffLocation); br.DropoffLocation == "" {
-		return errors.New("dropoff location is required")
+	// Hourly charters book a duration instead of a drop-off
+	if br.BookingMode = strings.TrimSpace(br.BookingMode); br.BookingMode == "" {
+		br.BookingMode = models.BookingModePointToPoint
+	}
+	switch br.BookingMode {
+	case models.BookingModePointToPoint:
+		if br.DropoffLocation = strings.TrimSpace(br.DropoffLocation); br.DropoffLocation == "" {
+			return errors.New("dropoff location is required")
+		}
+		if br.DurationHours != nil {
+			return errors.New("duration is only allowed for hourly bookings")
+		}
+	case models.BookingModeHourly:
+		br.DropoffLocation = strings.TrimSpace(br.DropoffLocation)
+		if err := ValidateDurationHours(br.DurationHours); err != nil {
+			return err
+		}
+		if len(br.Stops) > 0 || br.ReturnTrip != nil {
+			return errors.New("hourly bookings cannot have stops or a return trip")
+		}
+	default:
+		return errors.New("booking mode must be point_to_point or hourly")
+	}
+	if err := ValidateLocation("dropoff", br.Dropoff); err != nil {
+		return err
+	}
+
+	if err := ValidateStops(br.Stops); err != nil {
+		return err
+	}
+
+	if err := ValidateFlight(br.Flight); err != nil {
+		return err
 	}
 
 	if br.Date = strings.TrimSpace(br.Date); br.Date == "" {
@@ -167,8 +229,8 @@ func ValidateBookRide(br *models.BookRide) error {
 	if br.Time = strings.TrimSpace(br.Time); br.Time == "" {
 		return errors.New("time is required")
 	}
-	// Basic time validation (assuming HH:MM format)
-	if _, err := time.Parse("15:04", br.Time); err != nil {
+	// Times must be zero-padded HH:MM; "9:30" would also parse
+	if _, ok := clockMinutes(br.Time); !ok {
 		return errors.New("invalid time format; use HH:MM")
 	}
 
@@ -184,6 +246,21 @@ func ValidateBookRide(br *models.BookRide) error {
 		return errors.New("additional notes must be no more than 500 characters")
 	}
 
+	if br.ReturnTrip != nil {
+		returnAt, err := time.Parse(models.PickupDateTimeLayout, br.ReturnTrip.Date+" "+br.ReturnTrip.Time)
+		if _, ok := clockMinutes(br.ReturnTrip.Time); err != nil || !ok {
+			return errors.New("invalid return trip date or time; use YYYY-MM-DD and HH:MM")
+		}
+		// Both are in the pickup zone's time zone, so comparing them as UTC is equivalent
+		pickupAt, _ := time.Parse(models.PickupDateTimeLayout, br.Date+" "+br.Time)
+		if !returnAt.After(pickupAt) {
+			return errors.New("return trip must be after the outbound pickup")
+		}
+		if err := ValidateStops(br.ReturnTrip.Stops); err != nil {
+			return fmt.Errorf("return trip: %w", err)
+		}
+	}
+
 	// Status defaults are set in DB, but validate if provided
 	if br.BookStatus != "" && br.BookStatus != "Pending" {
 		return errors.New("initial book status must be Pending")
@@ -195,8 +272,9 @@ func ValidateBookRide(br *models.BookRide) error {
 	return nil
 }
 
-// ValidateUpdateBookRide validates the update book ride data
-func ValidateUpdateBookRide(updates *models.UpdateBookRideRequest) error {
+// ValidateUpdateBookRide validates the update book ride data. Phone numbers without a country code
+// are taken to be in countryCode.
+func ValidateUpdateBookRide(updates *models.UpdateBookRideRequest, countryCode string) error {
 	if updates.YourName != "" {
 		if len(updates.YourName) > 100 {
 			return errors.New("name must be no more than 100 characters long")
@@ -204,11 +282,49 @@ func ValidateUpdateBookRide(updates *models.UpdateBookRideRequest) error {
 	}
 
 	if updates.PhoneNumber != "" {
-		if len(updates.PhoneNumber) < 7 || len(updates.PhoneNumber) > 20 {
-			return errors.New("phone number must be between 7 and 20 characters")
+		if err := ValidatePhone("phone number", &updates.PhoneNumber, countryCode); err != nil {
+			return err
 		}
 	}
 
+	if updates.PassengerName != nil || updates.PassengerEmail != nil || updates.PassengerPhone != nil {
+		if updates.PassengerName == nil || updates.PassengerEmail == nil || updates.PassengerPhone == nil {
+			return errors.New("passenger name, email and phone must be updated together")
+		}
+		*updates.PassengerName = strings.TrimSpace(*updates.PassengerName)
+		*updates.PassengerEmail = strings.TrimSpace(strings.ToLower(*updates.PassengerEmail))
+		*updates.PassengerPhone = strings.TrimSpace(*updates.PassengerPhone)
+		if err := ValidatePassenger(*updates.PassengerName, *updates.PassengerEmail, updates.PassengerPhone, countryCode); err != nil {
+			return err
+		}
+	}
+	if err := ValidateNotifications(updates.Notifications); err != nil {
+		return err
+	}
+
+	if err := ValidateLocation("pickup", updates.Pickup); err != nil {
+		return err
+	}
+	if err := ValidateLocation("dropoff", updates.Dropoff); err != nil {
+		return err
+	}
+
+	if updates.Stops != nil {
+		if err := ValidateStops(*updates.Stops); err != nil {
+			return err
+		}
+	}
+
+	if updates.DurationHours != nil {
+		if err := ValidateDurationHours(updates.DurationHours); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateFlight(updates.Flight); err != nil {
+		return err
+	}
+
 	if updates.Date != "" {
 		if _, err := time.Parse("2006-01-02", updates.Date); err != nil {
 			return errors.New("invalid date format; use YYYY-MM-DD")
@@ -216,7 +332,8 @@ func ValidateUpdateBookRide(updates *models.UpdateBookRideRequest) error {
 	}
 
 	if updates.Time != "" {
-		if _, err := time.Parse("15:04", updates.Time); err != nil {
+		// Times must be zero-padded HH:MM, as for new bookings
+		if _, ok := clockMinutes(updates.Time); !ok {
 			return errors.New("invalid time format; use HH:MM")
 		}
 	}
@@ -236,25 +353,669 @@ func ValidateUpdateBookRide(updates *models.UpdateBookRideRequest) error {
 	return nil
 }
 
-// ValidateBookingDateTime validates that booking is at least 24 hours in the future
-func ValidateBookingDateTime(dateStr, timeStr string) error {
-	if dateStr == "" || timeStr == "" {
-		return errors.New("date and time are required")
+// ValidatePassenger validates the passenger of a booking made for someone else. All fields are
+// empty when the booker rides; otherwise the name is required and the email and phone are optional.
+// The phone number is rewritten in E.164 form.
+func ValidatePassenger(name, email string, phone *string, countryCode string) error {
+	if name == "" {
+		if email != "" || *phone != "" {
+			return errors.New("passenger name is required")
+		}
+		return nil
 	}
+	if len(name) > 100 {
+		return errors.New("passenger name must be no more than 100 characters long")
+	}
+	if email != "" && ValidateEmail(email) != nil {
+		return errors.New("invalid passenger email format")
+	}
+	if *phone != "" {
+		return ValidatePhone("passenger phone number", phone, countryCode)
+	}
+	return nil
+}
 
-	// Parse the booking date and time
-	bookingDateTime, err := time.Parse("2006-01-02 15:04", dateStr+" "+timeStr)
+// ValidatePhone validates a phone number and rewrites it in E.164 form. Numbers without a
+// country code are taken to be in countryCode.
+func ValidatePhone(field string, phone *string, countryCode string) error {
+	normalized, err := notify.NormalizePhone(*phone, countryCode)
 	if err != nil {
-		return errors.New("invalid date or time format")
+		return fmt.Errorf("invalid %s; include the country code, e.g. +1 555 123 4567", field)
+	}
+	*phone = normalized
+	return nil
+}
+
+// ValidateNotifications validates who receives each kind of message about a booking
+func ValidateNotifications(n *models.BookingNotifications) error {
+	if n == nil {
+		return nil
 	}
+	for field, choice := range map[string]string{"trip_updates": n.TripUpdates, "receipts": n.Receipts, "sms": n.SMS} {
+		switch choice {
+		case "", models.NotifyBooker, models.NotifyPassenger, models.NotifyBoth:
+		default:
+			return fmt.Errorf("notifications %s must be booker, passenger or both", field)
+		}
+	}
+	return nil
+}
 
-	// Check if booking is at least 24 hours in the future
-	now := time.Now()
-	minBookingTime := now.Add(24 * time.Hour)
+// ValidateNotificationPreferences validates a user's notification preferences. Critical events
+// cannot be turned off, and quiet hours need both a start and an end.
+func ValidateNotificationPreferences(req *models.UpdateNotificationPreferencesRequest) error {
+	for eventType, channels := range req.Channels {
+		event, ok := models.LookupNotificationEvent(eventType)
+		if !ok {
+			return fmt.Errorf("unknown notification event: %s", eventType)
+		}
+		for channel, enabled := range channels {
+			if !slices.Contains(models.NotificationChannelNames, channel) {
+				return fmt.Errorf("channel must be one of: %s", strings.Join(models.NotificationChannelNames, ", "))
+			}
+			if event.Critical && !enabled {
+				return fmt.Errorf("%s notifications are always sent and cannot be turned off", eventType)
+			}
+		}
+	}
 
+	if (req.QuietHoursStart == nil) != (req.QuietHoursEnd == nil) {
+		return errors.New("quiet hours need both a start and an end")
+	}
+	for _, clock := range []*string{req.QuietHoursStart, req.QuietHoursEnd} {
+		if clock == nil {
+			continue
+		}
+		if _, err := time.Parse("15:04", *clock); err != nil || len(*clock) != 5 {
+			return errors.New("quiet hours must be in HH:MM format")
+		}
+	}
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return fmt.Errorf("unknown time zone: %s", req.Timezone)
+		}
+	}
+	if req.Language != "" && !slices.Contains(models.NotificationLanguages, req.Language) {
+		return fmt.Errorf("language must be one of: %s", strings.Join(models.NotificationLanguages, ", "))
+	}
+	return nil
+}
+
+// ValidateLocation validates an optional structured location; field names the location in errors
+func ValidateLocation(field string, loc *models.Location) error {
+	if loc == nil {
+		return nil
+	}
+	if (loc.Lat == nil) != (loc.Lng == nil) {
+		return fmt.Errorf("%s latitude and longitude must be provided together", field)
+	}
+	if loc.Lat != nil && (*loc.Lat < -90 || *loc.Lat > 90) {
+		return fmt.Errorf("%s latitude must be between -90 and 90", field)
+	}
+	if loc.Lng != nil && (*loc.Lng < -180 || *loc.Lng > 180) {
+		return fmt.Errorf("%s longitude must be between -180 and 180", field)
+	}
+	if len(loc.FormattedAddress) > 500 {
+		return fmt.Errorf("%s address must be no more than 500 characters", field)
+	}
+	if len(loc.DriverNotes) > 500 {
+		return fmt.Errorf("%s driver notes must be no more than 500 characters", field)
+	}
+	return nil
+}
+
+// ValidateFlight validates optional inbound flight details and normalizes the codes to upper case
+func ValidateFlight(flight *models.FlightDetails) error {
+	if flight == nil {
+		return nil
+	}
+	flight.Airline = strings.ToUpper(strings.TrimSpace(flight.Airline))
+	flight.FlightNumber = strings.ToUpper(strings.ReplaceAll(flight.FlightNumber, " ", ""))
+	flight.Terminal = strings.TrimSpace(flight.Terminal)
+
+	if !airlineRegex.MatchString(flight.Airline) {
+		return errors.New("airline must be a 2 or 3 character IATA/ICAO code")
+	}
+	if !flightNumberRegex.MatchString(flight.FlightNumber) {
+		return errors.New("invalid flight number")
+	}
+	if flight.ScheduledArrival.IsZero() {
+		return errors.New("scheduled arrival is required for flights")
+	}
+	if len(flight.Terminal) > 20 {
+		return errors.New("terminal must be no more than 20 characters")
+	}
+	return nil
+}
+
+// MaxCharterHours is the longest hourly charter that can be booked
+const MaxCharterHours = 24
+
+// ValidateDurationHours validates the booked duration of an hourly charter
+func ValidateDurationHours(hours *int) error {
+	if hours == nil {
+		return errors.New("duration in hours is required for hourly bookings")
+	}
+	if *hours < 1 || *hours > MaxCharterHours {
+		return fmt.Errorf("duration must be between 1 and %d hours", MaxCharterHours)
+	}
+	return nil
+}
+
+// MaxStops is the largest number of intermediate stops on a booking
+const MaxStops = 5
+
+// ValidateStops validates intermediate stops and numbers them in the order given
+func ValidateStops(stops []models.BookingStop) error {
+	if len(stops) > MaxStops {
+		return fmt.Errorf("a booking can have at most %d stops", MaxStops)
+	}
+	for i := range stops {
+		stop := &stops[i]
+		if stop.Address == "" && stop.Place != nil {
+			stop.Address = stop.Place.FormattedAddress
+		}
+		if stop.Address = strings.TrimSpace(stop.Address); stop.Address == "" {
+			return fmt.Errorf("stop %d address is required", i+1)
+		}
+		if len(stop.Address) > 500 {
+			return fmt.Errorf("stop %d address must be no more than 500 characters", i+1)
+		}
+		if err := ValidateLocation(fmt.Sprintf("stop %d", i+1), stop.Place); err != nil {
+			return err
+		}
+		stop.Position = i + 1
+	}
+	return nil
+}
+
+// ValidateAddOns checks the add-ons selected on a booking against the active catalog and the
+// booking's ride type, filling in the catalog name and price of each selection
+func ValidateAddOns(selected models.BookingAddOns, catalog []*models.AddOn, rideType string) error {
+	byCode := make(map[string]*models.AddOn, len(catalog))
+	for _, addOn := range catalog {
+		byCode[addOn.Code] = addOn
+	}
+
+	seen := make(map[string]bool, len(selected))
+	for i := range selected {
+		sel := &selected[i]
+		sel.Code = strings.ToLower(strings.TrimSpace(sel.Code))
+		addOn, ok := byCode[sel.Code]
+		if !ok {
+			return fmt.Errorf("unknown add-on: %s", sel.Code)
+		}
+		if seen[sel.Code] {
+			return fmt.Errorf("add-on %s is selected more than once", sel.Code)
+		}
+		seen[sel.Code] = true
+
+		if !addOn.AvailableFor(rideType) {
+			return fmt.Errorf("add-on %s is not available for ride type %s", sel.Code, rideType)
+		}
+		if sel.Quantity == 0 {
+			sel.Quantity = 1
+		}
+		if sel.Quantity < 1 || sel.Quantity > addOn.MaxQuantity {
+			return fmt.Errorf("quantity of add-on %s must be between 1 and %d", sel.Code, addOn.MaxQuantity)
+		}
+		sel.Name = addOn.Name
+		sel.UnitPrice = addOn.Price
+	}
+	return nil
+}
+
+// Booking schedule rules reported in RuleError
+const (
+	RuleInvalidDateTime = "invalid_date_time"
+	RuleMinLeadTime     = "min_lead_time"
+	RuleMaxAdvance      = "max_advance_window"
+	RuleOperatingHours  = "operating_hours"
+	RuleBlackoutDate    = "blackout_date"
+)
+
+// RuleError is a validation failure naming the service zone rule that was violated
+type RuleError struct {
+	Rule    string `json:"rule"`
+	Zone    string `json:"zone,omitempty"`
+	Message string `json:"error"`
+}
+
+func (e *RuleError) Error() string {
+	return e.Message
+}
+
+// ValidateBookingDateTime validates a pickup date and time against the rules of its service zone.
+// The date and time are interpreted in the zone's time zone.
+func ValidateBookingDateTime(zone *models.ServiceZone, dateStr, timeStr string, now time.Time) error {
+	if dateStr == "" || timeStr == "" {
+		return &RuleError{Rule: RuleInvalidDateTime, Zone: zone.Code, Message: "date and time are required"}
+	}
+
+	// Parse the booking date and time in the zone's local time
+	bookingDateTime, err := time.ParseInLocation(models.PickupDateTimeLayout, dateStr+" "+timeStr, zone.Location())
+	if err != nil {
+		return &RuleError{Rule: RuleInvalidDateTime, Zone: zone.Code, Message: "invalid date or time format"}
+	}
+
+	minBookingTime := now.Add(time.Duration(zone.MinLeadMinutes) * time.Minute)
 	if bookingDateTime.Before(minBookingTime) {
-		return errors.New("booking must be at least 24 hours in advance")
+		return &RuleError{Rule: RuleMinLeadTime, Zone: zone.Code,
+			Message: fmt.Sprintf("booking must be at least %s in advance in %s", formatMinutes(zone.MinLeadMinutes), zone.Name)}
+	}
+
+	maxBookingTime := now.AddDate(0, 0, zone.MaxAdvanceDays)
+	if bookingDateTime.After(maxBookingTime) {
+		return &RuleError{Rule: RuleMaxAdvance, Zone: zone.Code,
+			Message: fmt.Sprintf("bookings in %s can be made at most %d days in advance", zone.Name, zone.MaxAdvanceDays)}
+	}
+
+	if blackout := zone.BlackoutOn(dateStr); blackout != nil {
+		message := fmt.Sprintf("no pickups are available in %s on %s", zone.Name, dateStr)
+		if blackout.Reason != "" {
+			message += " (" + blackout.Reason + ")"
+		}
+		return &RuleError{Rule: RuleBlackoutDate, Zone: zone.Code, Message: message}
+	}
+
+	if !withinOperatingHours(zone.OpensAt, zone.ClosesAt, timeStr) {
+		return &RuleError{Rule: RuleOperatingHours, Zone: zone.Code,
+			Message: fmt.Sprintf("pickups in %s are only available between %s and %s", zone.Name, zone.OpensAt, zone.ClosesAt)}
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// withinOperatingHours reports whether an HH:MM time lies in [opensAt, closesAt),
+// handling hours that run past midnight; empty hours mean open around the clock
+func withinOperatingHours(opensAt, closesAt, timeStr string) bool {
+	if opensAt == "" || closesAt == "" {
+		return true
+	}
+	opens, ok1 := clockMinutes(opensAt)
+	closes, ok2 := clockMinutes(closesAt)
+	at, ok3 := clockMinutes(timeStr)
+	if !ok1 || !ok2 || !ok3 {
+		return false
+	}
+	if opens <= closes {
+		return at >= opens && at < closes
+	}
+	return at >= opens || at < closes
+}
+
+// clockMinutes parses a zero-padded HH:MM time into minutes after midnight
+func clockMinutes(clock string) (int, bool) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil || len(clock) != 5 {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// formatMinutes renders a lead time in whole hours when possible
+func formatMinutes(minutes int) string {
+	if minutes >= 60 && minutes%60 == 0 {
+		return fmt.Sprintf("%d hours", minutes/60)
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+// ValidateServiceZone validates the configuration of a service zone
+func ValidateServiceZone(zone *models.ServiceZone) error {
+	if zone.Code == "" {
+		return errors.New("zone code is required")
+	}
+	if zone.Name = strings.TrimSpace(zone.Name); zone.Name == "" {
+		return errors.New("zone name is required")
+	}
+	if zone.Timezone == "" {
+		zone.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(zone.Timezone); err != nil {
+		return errors.New("invalid timezone")
+	}
+	if zone.MinLeadMinutes < 0 {
+		return errors.New("minimum lead time cannot be negative")
+	}
+	if zone.MaxAdvanceDays <= 0 {
+		return errors.New("maximum advance booking window must be at least 1 day")
+	}
+	if (zone.OpensAt == "") != (zone.ClosesAt == "") {
+		return errors.New("opening and closing times must be set together")
+	}
+	for _, t := range []string{zone.OpensAt, zone.ClosesAt} {
+		if t == "" {
+			continue
+		}
+		if _, ok := clockMinutes(t); !ok {
+			return errors.New("invalid operating hours format; use HH:MM")
+		}
+	}
+	for _, blackout := range zone.BlackoutDates {
+		if _, err := time.Parse("2006-01-02", blackout.Date); err != nil {
+			return errors.New("invalid blackout date format; use YYYY-MM-DD")
+		}
+	}
+	for _, holiday := range zone.Holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+			return errors.New("invalid holiday date format; use YYYY-MM-DD")
+		}
+		if holiday.SurchargePercent < 0 || holiday.SurchargePercent > 100 {
+			return errors.New("holiday surcharge percent must be between 0 and 100")
+		}
+	}
+	if zone.Boundary != nil {
+		if _, err := geo.ParseGeometry(zone.Boundary); err != nil {
+			return fmt.Errorf("invalid boundary: %w", err)
+		}
+	}
+	return nil
+}
+
+// ValidateAddOn validates a catalog add-on and normalizes its compatible ride types
+func ValidateAddOn(addOn *models.AddOn) error {
+	if addOn.Code == "" {
+		return errors.New("add-on code is required")
+	}
+	if addOn.Name = strings.TrimSpace(addOn.Name); addOn.Name == "" {
+		return errors.New("add-on name is required")
+	}
+	if len(addOn.Name) > 100 {
+		return errors.New("add-on name must be no more than 100 characters long")
+	}
+	if len(addOn.Description) > 500 {
+		return errors.New("add-on description must be no more than 500 characters")
+	}
+	if addOn.Price < 0 {
+		return errors.New("add-on price cannot be negative")
+	}
+	if addOn.MaxQuantity < 1 {
+		return errors.New("maximum quantity must be at least 1")
+	}
+	for i, rideType := range addOn.RideTypes {
+		addOn.RideTypes[i] = strings.ToLower(strings.TrimSpace(rideType))
+		if addOn.RideTypes[i] == "" {
+			return errors.New("ride types cannot be empty")
+		}
+	}
+	if addOn.RideTypes == nil {
+		addOn.RideTypes = []string{}
+	}
+	return nil
+}
+
+// RatingTags lists the tags each rating direction may use
+var RatingTags = map[string][]string{
+	models.RatingRiderToDriver: {"punctual", "professional", "friendly", "clean_vehicle", "safe_driving", "smooth_ride", "knows_route",
+		"late", "rude", "dirty_vehicle", "unsafe_driving", "wrong_route"},
+	models.RatingDriverToRider: {"punctual", "polite", "respectful", "clear_instructions",
+		"late", "rude", "messy", "no_show"},
+}
+
+// MaxRatingTags is the largest number of tags on a single rating
+const MaxRatingTags = 5
+
+// ValidateRating validates a rating in the given direction and normalizes its tags
+func ValidateRating(req *models.CreateRatingRequest, direction string) error {
+	if req.Score < 1 || req.Score > 5 {
+		return errors.New("score must be between 1 and 5")
+	}
+	if len(req.Tags) > MaxRatingTags {
+		return fmt.Errorf("a rating can have at most %d tags", MaxRatingTags)
+	}
+
+	allowed := make(map[string]bool)
+	for _, tag := range RatingTags[direction] {
+		allowed[tag] = true
+	}
+	seen := make(map[string]bool, len(req.Tags))
+	tags := make([]string, 0, len(req.Tags))
+	for _, tag := range req.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !allowed[tag] {
+			return fmt.Errorf("unknown rating tag: %s", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	req.Tags = tags
+
+	if req.Comment = strings.TrimSpace(req.Comment); len(req.Comment) > 1000 {
+		return errors.New("comment must be no more than 1000 characters")
+	}
+	return nil
+}
+
+// MaxTipAmount and MaxRideExtraAmount cap post-ride adjustments, in minor units
+const (
+	MaxTipAmount       models.Money = 100000
+	MaxRideExtraAmount models.Money = 50000
+	MaxWaitingMinutes               = 240
+)
+
+// ValidateTip validates a tip added after a completed ride
+func ValidateTip(req *models.TipRequest) error {
+	if req.Amount <= 0 {
+		return errors.New("tip amount must be greater than zero")
+	}
+	if req.Amount > MaxTipAmount {
+		return fmt.Errorf("tip amount must be no more than %d", MaxTipAmount)
+	}
+	return nil
+}
+
+// ValidateRideExtra validates an extra recorded by a driver after a completed ride
+func ValidateRideExtra(req *models.RideExtraRequest) error {
+	req.Kind = strings.ToLower(strings.TrimSpace(req.Kind))
+	switch req.Kind {
+	case models.AdjustmentTolls, models.AdjustmentParking:
+		if req.Amount <= 0 {
+			return errors.New("amount must be greater than zero")
+		}
+		if req.Amount > MaxRideExtraAmount {
+			return fmt.Errorf("amount must be no more than %d", MaxRideExtraAmount)
+		}
+		if req.Minutes != 0 {
+			return errors.New("minutes are only recorded for waiting time")
+		}
+	case models.AdjustmentWaitingTime:
+		if req.Minutes <= 0 || req.Minutes > MaxWaitingMinutes {
+			return fmt.Errorf("waiting minutes must be between 1 and %d", MaxWaitingMinutes)
+		}
+		if req.Amount != 0 {
+			return errors.New("waiting time is priced from the rate card and cannot carry an amount")
+		}
+	default:
+		return errors.New("kind must be one of: tolls, parking, waiting_time")
+	}
+
+	if req.Description = strings.TrimSpace(req.Description); len(req.Description) > 500 {
+		return errors.New("description must be no more than 500 characters")
+	}
+	return nil
+}
+
+// ValidateLegalEntity validates the company details printed on receipts
+func ValidateLegalEntity(entity *models.LegalEntity) error {
+	entity.Code = strings.ToLower(strings.TrimSpace(entity.Code))
+	if entity.Code == "" {
+		return errors.New("code is required")
+	}
+	if entity.Name = strings.TrimSpace(entity.Name); entity.Name == "" {
+		return errors.New("name is required")
+	}
+	if entity.ReceiptPrefix = strings.ToUpper(strings.TrimSpace(entity.ReceiptPrefix)); entity.ReceiptPrefix == "" {
+		return errors.New("receipt prefix is required")
+	}
+	if len(entity.ReceiptPrefix) > 10 {
+		return errors.New("receipt prefix must be no more than 10 characters")
+	}
+	if entity.TaxRateBP < 0 || entity.TaxRateBP > 10000 {
+		return errors.New("tax rate must be between 0 and 10000 basis points")
+	}
+	if entity.TaxLabel = strings.TrimSpace(entity.TaxLabel); entity.TaxLabel == "" {
+		entity.TaxLabel = "Tax"
+	}
+	if entity.Email != "" {
+		if err := ValidateEmail(entity.Email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateCancellationPolicy validates the tiers of a cancellation policy
+func ValidateCancellationPolicy(policy *models.CancellationPolicy) error {
+	if policy.RideType == "" {
+		return errors.New("ride type is required")
+	}
+	if policy.FreeUntilHours < 0 || policy.LateUntilHours < 0 {
+		return errors.New("hours cannot be negative")
+	}
+	if policy.LateUntilHours > policy.FreeUntilHours {
+		return errors.New("late fee window must end at or before the free cancellation window")
+	}
+	if policy.LateFeePercent < 0 || policy.LateFeePercent > 100 {
+		return errors.New("late fee percent must be between 0 and 100")
+	}
+	if policy.NoShowFeePercent < 0 || policy.NoShowFeePercent > 100 {
+		return errors.New("no-show fee percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// ValidateOrganization validates a corporate account's billing details and approval rules
+func ValidateOrganization(org *models.Organization) error {
+	if org.Name = strings.TrimSpace(org.Name); org.Name == "" {
+		return errors.New("organization name is required")
+	}
+	if len(org.Name) > 200 {
+		return errors.New("organization name must be no more than 200 characters long")
+	}
+	if err := ValidateEmail(org.BillingEmail); err != nil {
+		return fmt.Errorf("billing email: %w", err)
+	}
+	if org.Currency = strings.ToUpper(strings.TrimSpace(org.Currency)); org.Currency == "" {
+		org.Currency = models.DefaultCurrency
+	}
+	if len(org.Currency) != 3 {
+		return errors.New("currency must be a 3-letter ISO 4217 code")
+	}
+	if org.ApprovalThreshold != nil && *org.ApprovalThreshold < 0 {
+		return errors.New("approval threshold cannot be negative")
+	}
+	return nil
+}
+
+// ValidateCostCenter validates a cost center of an organization
+func ValidateCostCenter(costCenter *models.CostCenter) error {
+	if costCenter.Code = strings.TrimSpace(costCenter.Code); costCenter.Code == "" {
+		return errors.New("cost center code is required")
+	}
+	if len(costCenter.Code) > 50 {
+		return errors.New("cost center code must be no more than 50 characters long")
+	}
+	if costCenter.Name = strings.TrimSpace(costCenter.Name); costCenter.Name == "" {
+		return errors.New("cost center name is required")
+	}
+	return nil
+}
+
+// ValidateOrgRole validates the role of an organization member
+func ValidateOrgRole(role string) error {
+	if role != models.OrgRoleAdmin && role != models.OrgRoleEmployee {
+		return fmt.Errorf("role must be one of: %s, %s", models.OrgRoleAdmin, models.OrgRoleEmployee)
+	}
+	return nil
+}
+
+// ValidateWebhookEndpoint validates a webhook endpoint. Deliveries carry booking details, so the
+// URL must use https unless it points at the local machine for testing.
+func ValidateWebhookEndpoint(req *models.WebhookEndpointRequest, allowPrivate bool) error {
+	req.URL = strings.TrimSpace(req.URL)
+	if req.URL == "" {
+		return errors.New("webhook URL is required")
+	}
+	if len(req.URL) > 2048 {
+		return errors.New("webhook URL must be no more than 2048 characters long")
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || u.Host == "" {
+		return errors.New("webhook URL must be an absolute URL")
+	}
+	// Plain http and private addresses are only for receivers on a developer's machine
+	if u.Scheme != "https" && !(u.Scheme == "http" && allowPrivate) {
+		return errors.New("webhook URL must use https")
+	}
+	if !allowPrivate {
+		host := strings.ToLower(u.Hostname())
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return errors.New("webhook URL must not point at localhost")
+		}
+		if addr, err := netip.ParseAddr(host); err == nil && !webhooks.PublicAddress(addr) {
+			return errors.New("webhook URL must not point at a private or reserved address")
+		}
+	}
+	if req.Description = strings.TrimSpace(req.Description); len(req.Description) > 500 {
+		return errors.New("webhook description must be no more than 500 characters long")
+	}
+	if len(req.EventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
+	for _, eventType := range req.EventTypes {
+		if !slices.Contains(models.BookingEventTypes, eventType) {
+			return fmt.Errorf("event type must be one of: %s", strings.Join(models.BookingEventTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// MaxAPIKeyRateLimit is the most requests per minute a partner API key can be allowed
+const MaxAPIKeyRateLimit = 6000
+
+// ValidateCreateAPIKey validates a new partner API key
+func ValidateCreateAPIKey(req *models.CreateAPIKeyRequest) error {
+	if req.OrganizationID <= 0 {
+		return errors.New("organization ID is required")
+	}
+	if req.UserID <= 0 {
+		return errors.New("user ID of the member the key acts as is required")
+	}
+	return validateAPIKeySettings(&req.Name, req.Scopes, &req.RateLimitPerMinute, req.ExpiresAt)
+}
+
+// ValidateUpdateAPIKey validates the new settings of a partner API key
+func ValidateUpdateAPIKey(req *models.UpdateAPIKeyRequest) error {
+	return validateAPIKeySettings(&req.Name, req.Scopes, &req.RateLimitPerMinute, req.ExpiresAt)
+}
+
+// validateAPIKeySettings validates the settings shared by new and updated keys, defaulting the rate limit
+func validateAPIKeySettings(name *string, scopes []string, rateLimit *int, expiresAt *time.Time) error {
+	if *name = strings.TrimSpace(*name); *name == "" {
+		return errors.New("API key name is required")
+	}
+	if len(*name) > 100 {
+		return errors.New("API key name must be no more than 100 characters long")
+	}
+	if len(scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if _, ok := models.LookupAPIKeyScope(scope); !ok {
+			return fmt.Errorf("unknown API key scope: %s", scope)
+		}
+	}
+	if *rateLimit == 0 {
+		*rateLimit = models.DefaultAPIKeyRateLimit
+	}
+	if *rateLimit < 1 || *rateLimit > MaxAPIKeyRateLimit {
+		return fmt.Errorf("rate limit must be between 1 and %d requests per minute", MaxAPIKeyRateLimit)
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return errors.New("expiry must be in the future")
+	}
+	return nil
+}
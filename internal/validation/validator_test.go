@@ -0,0 +1,233 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+)
+
+func TestClockMinutes(t *testing.T) {
+	tests := []struct {
+		clock  string
+		want   int
+		wantOK bool
+	}{
+		{"00:00", 0, true},
+		{"09:30", 570, true},
+		{"23:59", 1439, true},
+		{"9:30", 0, false},
+		{"09:5", 0, false},
+		{"24:00", 0, false},
+		{"12:60", 0, false},
+		{"0930", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.clock, func(t *testing.T) {
+			got, ok := clockMinutes(tt.clock)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("clockMinutes(%q) = %d, %v; want %d, %v", tt.clock, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWithinOperatingHours(t *testing.T) {
+	tests := []struct {
+		name              string
+		opensAt, closesAt string
+		at                string
+		want              bool
+	}{
+		{"open around the clock", "", "", "03:00", true},
+		{"before opening", "08:00", "20:00", "07:59", false},
+		{"at opening", "08:00", "20:00", "08:00", true},
+		{"at closing", "08:00", "20:00", "20:00", false},
+		// As strings "9:30" sorts after "20:00"
+		{"unpadded time", "08:00", "20:00", "9:30", false},
+		{"unpadded hours", "9:00", "17:00", "10:00", false},
+		{"overnight before midnight", "22:00", "06:00", "23:00", true},
+		{"overnight after midnight", "22:00", "06:00", "05:59", true},
+		{"overnight at closing", "22:00", "06:00", "06:00", false},
+		{"overnight daytime", "22:00", "06:00", "12:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinOperatingHours(tt.opensAt, tt.closesAt, tt.at); got != tt.want {
+				t.Errorf("withinOperatingHours(%q, %q, %q) = %v, want %v", tt.opensAt, tt.closesAt, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBookingDateTime(t *testing.T) {
+	if _, err := time.LoadLocation("America/Chicago"); err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	allDay := &models.ServiceZone{
+		Code:           "chicago",
+		Name:           "Chicago",
+		Timezone:       "America/Chicago",
+		MinLeadMinutes: 60,
+		MaxAdvanceDays: 30,
+		BlackoutDates:  models.ZoneBlackouts{{Date: "2025-11-01", Reason: "Marathon"}},
+	}
+	overnight := *allDay
+	overnight.OpensAt, overnight.ClosesAt = "22:00", "06:00"
+
+	// 07:00 in Chicago (CDT)
+	now := time.Date(2025, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		zone     *models.ServiceZone
+		date     string
+		time     string
+		wantRule string // empty when the pickup is allowed
+	}{
+		{"missing time", allDay, "2025-10-20", "", RuleInvalidDateTime},
+		{"invalid time", allDay, "2025-10-20", "25:00", RuleInvalidDateTime},
+		{"too soon", allDay, "2025-10-18", "07:30", RuleMinLeadTime},
+		{"exactly the lead time", allDay, "2025-10-18", "08:00", ""},
+		// 11:30 would already have passed in UTC
+		{"read in the zone's time zone", allDay, "2025-10-18", "11:30", ""},
+		{"too far ahead", allDay, "2025-11-18", "07:00", RuleMaxAdvance},
+		{"blackout date", allDay, "2025-11-01", "10:00", RuleBlackoutDate},
+		{"overnight hours late evening", &overnight, "2025-10-20", "23:00", ""},
+		{"overnight hours early morning", &overnight, "2025-10-20", "02:00", ""},
+		{"overnight hours at closing", &overnight, "2025-10-20", "06:00", RuleOperatingHours},
+		{"overnight hours daytime", &overnight, "2025-10-20", "12:00", RuleOperatingHours},
+		{"overnight hours unpadded time", &overnight, "2025-10-20", "9:30", RuleOperatingHours},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBookingDateTime(tt.zone, tt.date, tt.time, now)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Errorf("ValidateBookingDateTime(%s %s) = %v, want nil", tt.date, tt.time, err)
+				}
+				return
+			}
+			var ruleErr *RuleError
+			if !errors.As(err, &ruleErr) || ruleErr.Rule != tt.wantRule {
+				t.Errorf("ValidateBookingDateTime(%s %s) = %v, want rule %s", tt.date, tt.time, err, tt.wantRule)
+			}
+		})
+	}
+}
+
+func validBooking() *models.BookRide {
+	return &models.BookRide{
+		YourName:           "Ada Lovelace",
+		Email:              "ada@example.com",
+		PhoneNumber:        "212-555-0123",
+		RideType:           "standard",
+		PickupLocation:     "JFK Terminal 4",
+		DropoffLocation:    "Times Square",
+		Date:               "2025-10-20",
+		Time:               "09:30",
+		NumberOfPassengers: 2,
+	}
+}
+
+func TestValidateBookRideTimes(t *testing.T) {
+	tests := []struct {
+		name    string
+		time    string
+		ret     *models.ReturnTripRequest
+		wantErr bool
+	}{
+		{name: "padded time", time: "09:30"},
+		{name: "unpadded time", time: "9:30", wantErr: true},
+		{name: "time without minutes", time: "09", wantErr: true},
+		{name: "return later that day", time: "09:30", ret: &models.ReturnTripRequest{Date: "2025-10-20", Time: "17:00"}},
+		// As strings "10:00" sorts before "9:30"
+		{name: "return within the hour", time: "09:30", ret: &models.ReturnTripRequest{Date: "2025-10-20", Time: "10:00"}},
+		{name: "return the next day", time: "23:00", ret: &models.ReturnTripRequest{Date: "2025-10-21", Time: "01:00"}},
+		{name: "return at pickup time", time: "09:30", ret: &models.ReturnTripRequest{Date: "2025-10-20", Time: "09:30"}, wantErr: true},
+		{name: "return before pickup", time: "17:00", ret: &models.ReturnTripRequest{Date: "2025-10-20", Time: "09:30"}, wantErr: true},
+		{name: "unpadded return time", time: "09:30", ret: &models.ReturnTripRequest{Date: "2025-10-21", Time: "9:00"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := validBooking()
+			br.Time = tt.time
+			br.ReturnTrip = tt.ret
+			err := ValidateBookRide(br, "1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateBookRide = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateUpdateBookRideTime(t *testing.T) {
+	tests := []struct {
+		time    string
+		wantErr bool
+	}{
+		{"", false},
+		{"09:30", false},
+		{"23:59", false},
+		{"9:30", true},
+		{"09:5", true},
+		{"24:00", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.time, func(t *testing.T) {
+			err := ValidateUpdateBookRide(&models.UpdateBookRideRequest{Time: tt.time}, "1")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdateBookRide(time %q) = %v, want error %v", tt.time, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBookRidePhoneCountryCode(t *testing.T) {
+	tests := []struct {
+		phone       string
+		countryCode string
+		want        string
+	}{
+		{"212-555-0123", "1", "+12125550123"},
+		{"020 7946 0958", "44", "+442079460958"},
+		{"+1 212 555 0123", "44", "+12125550123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.phone, func(t *testing.T) {
+			br := validBooking()
+			br.PhoneNumber = tt.phone
+			if err := ValidateBookRide(br, tt.countryCode); err != nil {
+				t.Fatalf("ValidateBookRide: %v", err)
+			}
+			if br.PhoneNumber != tt.want {
+				t.Errorf("phone number = %s, want %s", br.PhoneNumber, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNotificationPreferencesQuietHours(t *testing.T) {
+	clock := func(s string) *string { return &s }
+	tests := []struct {
+		name       string
+		start, end *string
+		wantErr    bool
+	}{
+		{"none", nil, nil, false},
+		{"spanning midnight", clock("22:00"), clock("07:00"), false},
+		{"start only", clock("22:00"), nil, true},
+		{"unpadded start", clock("9:00"), clock("17:00"), true},
+		{"invalid end", clock("22:00"), clock("24:00"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &models.UpdateNotificationPreferencesRequest{QuietHoursStart: tt.start, QuietHoursEnd: tt.end}
+			if err := ValidateNotificationPreferences(req); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateNotificationPreferences = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
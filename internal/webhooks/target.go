@@ -0,0 +1,74 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+)
+
+// ErrPrivateTarget is returned for deliveries to addresses inside our own network
+var ErrPrivateTarget = errors.New("webhook target resolves to a private address")
+
+// blockedPrefixes are ranges that are not private by net/netip's definition but still reach
+// infrastructure rather than a partner
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, which can embed any IPv4 address
+}
+
+// PublicAddress reports whether an address is routable on the public internet. Loopback, private,
+// link-local (including the cloud metadata address 169.254.169.254), multicast and reserved
+// ranges are not.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// newClient returns the client deliveries are sent with. The address is checked when the
+// connection is made, after DNS resolution, so a hostname cannot be pointed at our network after
+// the endpoint was registered. Redirects are not followed; a 3xx response is a failed attempt.
+func newClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if allowPrivate {
+				return nil
+			}
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrPrivateTarget, address)
+			}
+			if !PublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", ErrPrivateTarget, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialled instead of the endpoint, bypassing the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
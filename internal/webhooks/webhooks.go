@@ -0,0 +1,277 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/logger"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+const (
+	// pollInterval is how often due deliveries are claimed
+	pollInterval = 5 * time.Second
+	// claimBatch is how many deliveries an instance claims at a time
+	claimBatch = 50
+	// requestTimeout bounds each attempt; the delivery lease outlasts it
+	requestTimeout = 10 * time.Second
+	leaseDuration  = time.Minute
+	// retention is how long delivered and failed deliveries stay in the log
+	retention = 30 * 24 * time.Hour
+	// cleanupInterval is how often deliveries past their retention are deleted
+	cleanupInterval = time.Hour
+	// maxErrorLength caps the response excerpt kept for a failed attempt
+	maxErrorLength = 512
+)
+
+// retryBackoff is how long to wait after each failed attempt. A delivery fails for good once it
+// has been attempted once more than there are waits, about a day after the event.
+var retryBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+}
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Service tells partner endpoints about changes to their organization's bookings. Booking events
+// are queued in webhook_deliveries as they are published and delivered by Run on any instance;
+// each delivery is leased to one instance per attempt.
+type Service struct {
+	repo         repository.WebhookRepository
+	client       *http.Client
+	disableAfter int
+	allowPrivate bool
+	logger       *logger.Logger
+}
+
+// NewService creates the webhook service. allowPrivate lets endpoints on localhost and private
+// networks receive deliveries, for local development only.
+func NewService(repo repository.WebhookRepository, disableAfter int, allowPrivate bool, logger *logger.Logger) *Service {
+	return &Service{
+		repo:         repo,
+		client:       newClient(allowPrivate),
+		disableAfter: disableAfter,
+		allowPrivate: allowPrivate,
+		logger:       logger,
+	}
+}
+
+// AllowsPrivateTargets reports whether endpoints on localhost and private networks are accepted
+func (s *Service) AllowsPrivateTargets() bool {
+	return s.allowPrivate
+}
+
+// Sign returns the hex HMAC-SHA256 of "timestamp.payload", as sent in the X-Webhook-Signature header
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret generates an endpoint's signing secret
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Enqueue queues a booking event for the subscribed endpoints of the booking's organization.
+// Bookings not billed to an organization have no webhooks.
+func (s *Service) Enqueue(ctx context.Context, event *models.BookingEvent, booking *models.BookRide) error {
+	if booking.OrganizationID == nil {
+		return nil
+	}
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:        event.ID,
+		Type:      event.Type,
+		CreatedAt: event.CreatedAt,
+		Data:      event.Booking,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	queued, err := s.repo.Enqueue(ctx, *booking.OrganizationID, event.ID, event.Type, payload)
+	if err != nil {
+		return err
+	}
+	if queued > 0 {
+		s.logger.Info(fmt.Sprintf("Queued %s event %d for %d webhook endpoints of organization %d", event.Type, event.ID, queued, *booking.OrganizationID))
+	}
+	return nil
+}
+
+// Run delivers due webhooks until the context is cancelled, and deletes deliveries past their retention
+func (s *Service) Run(ctx context.Context) {
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(cleanupInterval)
+	defer cleanup.Stop()
+
+	s.cleanup(ctx)
+	for {
+		s.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		case <-cleanup.C:
+			s.cleanup(ctx)
+		}
+	}
+}
+
+// deliverDue attempts claimed deliveries until none are due
+func (s *Service) deliverDue(ctx context.Context) {
+	for {
+		deliveries, err := s.repo.ClaimDue(ctx, time.Now().Add(leaseDuration), claimBatch)
+		if err != nil {
+			s.logger.Err(fmt.Sprintf("Failed to claim webhook deliveries: %s", err.Error()))
+			return
+		}
+		for _, delivery := range deliveries {
+			s.attempt(ctx, delivery)
+		}
+		if len(deliveries) < claimBatch {
+			return
+		}
+	}
+}
+
+// attempt POSTs a delivery to its endpoint and records the outcome. Any 2xx response is a success.
+func (s *Service) attempt(ctx context.Context, due *models.DueWebhookDelivery) {
+	delivery := &due.WebhookDelivery
+	statusCode, err := s.post(ctx, due)
+	now := time.Now()
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts > len(retryBackoff) {
+			delivery.Status = models.WebhookDeliveryFailed
+		} else {
+			delivery.NextAttemptAt = now.Add(retryBackoff[delivery.Attempts-1])
+		}
+		s.logger.Warn(fmt.Sprintf("Webhook delivery %d to endpoint %d failed (attempt %d): %s", delivery.ID, delivery.EndpointID, delivery.Attempts, err.Error()))
+	}
+	if err := s.repo.RecordAttempt(ctx, delivery); err != nil {
+		s.logger.Err(fmt.Sprintf("Failed to record attempt of webhook delivery %d: %s", delivery.ID, err.Error()))
+	}
+
+	disabled, recordErr := s.repo.RecordEndpointResult(ctx, delivery.EndpointID, err == nil, s.disableAfter)
+	if recordErr != nil {
+		s.logger.Err(fmt.Sprintf("Failed to record result of webhook endpoint %d: %s", delivery.EndpointID, recordErr.Error()))
+	} else if disabled {
+		s.logger.Warn(fmt.Sprintf("Disabled webhook endpoint %d after %d failed attempts in a row", delivery.EndpointID, s.disableAfter))
+	}
+}
+
+// post sends a signed delivery and returns the response status, if any
+func (s *Service) post(ctx context.Context, due *models.DueWebhookDelivery) (*int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, due.URL, bytes.NewReader(due.Payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "LuxSUV-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, due.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(due.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(due.Secret, timestamp, due.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	statusCode := resp.StatusCode
+	if statusCode >= 200 && statusCode < 300 {
+		return &statusCode, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	return &statusCode, fmt.Errorf("endpoint returned %d: %s", statusCode, bytes.TrimSpace(body))
+}
+
+// cleanup deletes finished deliveries older than the retention period
+func (s *Service) cleanup(ctx context.Context) {
+	deleted, err := s.repo.DeleteDeliveriesBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		s.logger.Err(fmt.Sprintf("Failed to delete expired webhook deliveries: %s", err.Error()))
+		return
+	}
+	if deleted > 0 {
+		s.logger.Info(fmt.Sprintf("Deleted %d expired webhook deliveries", deleted))
+	}
+}
+
+// CreateEndpoint registers an endpoint with a new signing secret
+func (s *Service) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	secret, err := NewSecret()
+	if err != nil {
+		return fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	endpoint.Secret = secret
+	return s.repo.CreateEndpoint(ctx, endpoint)
+}
+
+// GetEndpoint returns an endpoint of an organization
+func (s *Service) GetEndpoint(ctx context.Context, orgID, id int64) (*models.WebhookEndpoint, error) {
+	return s.repo.GetEndpoint(ctx, orgID, id)
+}
+
+// ListEndpoints returns an organization's endpoints
+func (s *Service) ListEndpoints(ctx context.Context, orgID int64) ([]*models.WebhookEndpoint, error) {
+	return s.repo.ListEndpoints(ctx, orgID)
+}
+
+// UpdateEndpoint replaces an endpoint's URL, subscriptions and status
+func (s *Service) UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	return s.repo.UpdateEndpoint(ctx, endpoint)
+}
+
+// DeleteEndpoint removes an endpoint and its delivery log
+func (s *Service) DeleteEndpoint(ctx context.Context, orgID, id int64) error {
+	return s.repo.DeleteEndpoint(ctx, orgID, id)
+}
+
+// Deliveries returns an endpoint's newest deliveries, optionally with one status
+func (s *Service) Deliveries(ctx context.Context, endpointID int64, status string, limit int) ([]*models.WebhookDelivery, error) {
+	return s.repo.ListDeliveries(ctx, endpointID, status, limit)
+}
+
+// Replay queues a delivery's payload again, whatever the outcome of the original
+func (s *Service) Replay(ctx context.Context, endpointID, deliveryID int64) (*models.WebhookDelivery, error) {
+	delivery, err := s.repo.GetDelivery(ctx, endpointID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Replay(ctx, delivery)
+}
@@ -0,0 +1,38 @@
+package webhooks
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		payload   string
+		want      string
+	}{
+		{"payload", "whsec_test", "1700000000", `{"id":"evt_1"}`, "c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925"},
+		{"empty payload", "whsec_test", "1700000000", "", "5967f3c560522fa40cf2876ebc3c3a08551dd6959aaade3b413460591895bdcc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.payload)); got != tt.want {
+				t.Errorf("Sign = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignCoversEveryPart(t *testing.T) {
+	base := Sign("whsec_test", "1700000000", []byte(`{"id":"evt_1"}`))
+	for name, signature := range map[string]string{
+		"secret":    Sign("whsec_other", "1700000000", []byte(`{"id":"evt_1"}`)),
+		"timestamp": Sign("whsec_test", "1700000001", []byte(`{"id":"evt_1"}`)),
+		"payload":   Sign("whsec_test", "1700000000", []byte(`{"id":"evt_2"}`)),
+		// The separator keeps the timestamp and payload apart
+		"boundary": Sign("whsec_test", "170000000", []byte(`0.{"id":"evt_1"}`)),
+	} {
+		if signature == base {
+			t.Errorf("changing the %s did not change the signature", name)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package zones
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/diagnosis/luxsuv-v4/internal/geo"
+	"github.com/diagnosis/luxsuv-v4/internal/models"
+	"github.com/diagnosis/luxsuv-v4/internal/repository"
+)
+
+var (
+	// ErrNoZone is returned when no zone (not even the default one) covers a pickup
+	ErrNoZone = errors.New("pickup location is outside our service area")
+	// ErrUnlocatedPickup is returned when zone boundaries are configured but the pickup could not be geocoded
+	ErrUnlocatedPickup = errors.New("pickup address could not be located on the map")
+)
+
+// Trip is the outcome of resolving both ends of a booking against the service zones
+type Trip struct {
+	PickupZone       *models.ServiceZone
+	DropoffZone      *models.ServiceZone
+	DistanceKm       float64
+	RequiresApproval bool
+	ApprovalReason   string
+}
+
+// PickupZoneID returns the ID of the pickup zone, or nil when unresolved
+func (t *Trip) PickupZoneID() *int64 {
+	if t.PickupZone == nil {
+		return nil
+	}
+	return &t.PickupZone.ID
+}
+
+// DropoffZoneID returns the ID of the drop-off zone, or nil when outside every zone
+func (t *Trip) DropoffZoneID() *int64 {
+	if t.DropoffZone == nil {
+		return nil
+	}
+	return &t.DropoffZone.ID
+}
+
+// Apply records the resolved zones and approval requirement on a new booking
+func (t *Trip) Apply(br *models.BookRide) {
+	br.PickupZoneID = t.PickupZoneID()
+	br.DropoffZoneID = t.DropoffZoneID()
+	br.RequiresApproval = t.RequiresApproval
+	br.ApprovalReason = t.ApprovalReason
+	br.ApprovedBy = nil
+	br.ApprovedAt = nil
+}
+
+// Resolver finds the service zones a trip falls in
+type Resolver struct {
+	repo            repository.ServiceZoneRepository
+	approvalAfterKm float64
+
+	mu    sync.Mutex
+	areas map[int64]parsedArea // Parsed boundaries by zone ID
+}
+
+// parsedArea is a zone boundary parsed as of the zone's last update
+type parsedArea struct {
+	updatedAt time.Time
+	area      geo.Area
+	err       error
+}
+
+// NewResolver creates a resolver; trips leaving their pickup zone that are longer than
+// approvalAfterKm are flagged for manual approval
+func NewResolver(repo repository.ServiceZoneRepository, approvalAfterKm float64) *Resolver {
+	return &Resolver{repo: repo, approvalAfterKm: approvalAfterKm, areas: make(map[int64]parsedArea)}
+}
+
+// ResolveTrip resolves the pickup and drop-off zones of a trip and decides whether
+// the trip needs manual approval. Once any boundary is configured, pickups must be
+// geocoded and fall inside a zone boundary; until then the pickup text is matched
+// against each zone's keywords, falling back to the default zone.
+func (r *Resolver) ResolveTrip(ctx context.Context, pickupText string, pickup, dropoff *models.Location) (*Trip, error) {
+	zones, err := r.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pickupZone, err := r.resolvePickup(zones, pickupText, pickup)
+	if err != nil {
+		return nil, err
+	}
+
+	trip := &Trip{PickupZone: pickupZone}
+	bounded := hasBoundaries(zones)
+
+	if !dropoff.HasCoordinates() {
+		return trip, nil
+	}
+	trip.DropoffZone = r.containing(zones, dropoff)
+	if pickup.HasCoordinates() {
+		trip.DistanceKm = geo.DistanceKm(pickup, dropoff)
+	}
+
+	if bounded && !sameZone(trip.PickupZone, trip.DropoffZone) && trip.DistanceKm > r.approvalAfterKm {
+		trip.RequiresApproval = true
+		destination := "outside our service area"
+		if trip.DropoffZone != nil {
+			destination = "in " + trip.DropoffZone.Name
+		}
+		trip.ApprovalReason = fmt.Sprintf("%.0f km trip from %s to a drop-off %s", trip.DistanceKm, trip.PickupZone.Name, destination)
+	}
+
+	return trip, nil
+}
+
+// PickupLocation returns the time zone a booking's pickup is scheduled in: that of its pickup
+// zone, or of the default zone for bookings without one
+func (r *Resolver) PickupLocation(ctx context.Context, booking *models.BookRide) (*time.Location, error) {
+	zones, err := r.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loc := time.UTC
+	for _, zone := range zones {
+		if booking.PickupZoneID != nil && zone.ID == *booking.PickupZoneID {
+			return zone.Location(), nil
+		}
+		if zone.Code == models.DefaultZoneCode {
+			loc = zone.Location()
+		}
+	}
+	return loc, nil
+}
+
+// resolvePickup picks the zone of a pickup from the configured zones
+func (r *Resolver) resolvePickup(zones []*models.ServiceZone, pickupText string, pickup *models.Location) (*models.ServiceZone, error) {
+	if hasBoundaries(zones) {
+		if !pickup.HasCoordinates() {
+			return nil, ErrUnlocatedPickup
+		}
+		if zone := r.containing(zones, pickup); zone != nil {
+			return zone, nil
+		}
+		return nil, ErrNoZone
+	}
+
+	normalized := strings.ToLower(pickupText)
+	var fallback *models.ServiceZone
+	for _, zone := range zones {
+		if zone.Code == models.DefaultZoneCode {
+			fallback = zone
+			continue
+		}
+		for _, keyword := range zone.MatchKeywords {
+			if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" && strings.Contains(normalized, keyword) {
+				return zone, nil
+			}
+		}
+	}
+
+	if fallback == nil {
+		return nil, ErrNoZone
+	}
+	return fallback, nil
+}
+
+// containing returns the first zone whose boundary contains the location
+func (r *Resolver) containing(zones []*models.ServiceZone, loc *models.Location) *models.ServiceZone {
+	for _, zone := range zones {
+		if zone.Boundary == nil {
+			continue
+		}
+		area, err := r.area(zone)
+		if err != nil {
+			continue
+		}
+		if area.Contains(*loc.Lat, *loc.Lng) {
+			return zone
+		}
+	}
+	return nil
+}
+
+// area returns the parsed boundary of a zone, parsing it again only after the zone changed
+func (r *Resolver) area(zone *models.ServiceZone) (geo.Area, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cached, ok := r.areas[zone.ID]; ok && cached.updatedAt.Equal(zone.UpdatedAt) {
+		return cached.area, cached.err
+	}
+	area, err := geo.ParseGeometry(zone.Boundary)
+	r.areas[zone.ID] = parsedArea{updatedAt: zone.UpdatedAt, area: area, err: err}
+	return area, err
+}
+
+// hasBoundaries reports whether any zone defines a boundary; until one does,
+// pickups are never rejected for being out of area
+func hasBoundaries(zones []*models.ServiceZone) bool {
+	for _, zone := range zones {
+		if zone.Boundary != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func sameZone(a, b *models.ServiceZone) bool {
+	return a != nil && b != nil && a.ID == b.ID
+}
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Fares are stored in integer minor units (cents), never as floating point
+ALTER TABLE book_rides
+ADD COLUMN quoted_amount BIGINT NOT NULL DEFAULT 0,
+ADD COLUMN final_amount BIGINT,
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'USD',
+ADD COLUMN line_items JSONB NOT NULL DEFAULT '[]'::jsonb,
+ADD COLUMN final_line_items JSONB;
+
+-- Audit trail for admin fare overrides
+CREATE TABLE booking_fare_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL REFERENCES book_rides(id) ON DELETE CASCADE,
+    admin_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    field TEXT NOT NULL CHECK (field IN ('quoted_amount', 'final_amount')),
+    previous_amount BIGINT NOT NULL,
+    new_amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_booking_fare_adjustments_booking_id ON booking_fare_adjustments(booking_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS booking_fare_adjustments;
+
+ALTER TABLE book_rides
+DROP COLUMN IF EXISTS final_line_items,
+DROP COLUMN IF EXISTS line_items,
+DROP COLUMN IF EXISTS currency,
+DROP COLUMN IF EXISTS final_amount,
+DROP COLUMN IF EXISTS quoted_amount;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE cancellation_policies (
+    ride_type TEXT PRIMARY KEY,
+    free_until_hours INTEGER NOT NULL CHECK (free_until_hours >= 0),
+    late_until_hours INTEGER NOT NULL CHECK (late_until_hours >= 0),
+    late_fee_percent INTEGER NOT NULL CHECK (late_fee_percent BETWEEN 0 AND 100),
+    no_show_fee_percent INTEGER NOT NULL CHECK (no_show_fee_percent BETWEEN 0 AND 100),
+    updated_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (late_until_hours <= free_until_hours)
+);
+
+-- Default policy: free until 24h before pickup, 50% until 2h before, full fare after
+INSERT INTO cancellation_policies (ride_type, free_until_hours, late_until_hours, late_fee_percent, no_show_fee_percent)
+VALUES ('default', 24, 2, 50, 100);
+
+-- Cancellation fee charged when a booking is cancelled
+ALTER TABLE book_rides ADD COLUMN cancellation_fee BIGINT;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE book_rides DROP COLUMN IF EXISTS cancellation_fee;
+DROP TABLE IF EXISTS cancellation_policies;
+
+-- +goose StatementEnd
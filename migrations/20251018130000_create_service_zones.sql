@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE service_zones (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    min_lead_minutes INTEGER NOT NULL DEFAULT 1440 CHECK (min_lead_minutes >= 0),
+    max_advance_days INTEGER NOT NULL DEFAULT 365 CHECK (max_advance_days > 0),
+    opens_at TEXT NOT NULL DEFAULT '',
+    closes_at TEXT NOT NULL DEFAULT '',
+    match_keywords TEXT[] NOT NULL DEFAULT '{}',
+    blackout_dates JSONB NOT NULL DEFAULT '[]'::jsonb,
+    holidays JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Fallback zone keeps the previous behaviour: 24 hours lead time, around the clock
+INSERT INTO service_zones (code, name, timezone, min_lead_minutes, max_advance_days)
+VALUES ('default', 'Default Service Area', 'UTC', 1440, 365);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS service_zones;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Resolved pickup/drop-off locations; the raw text stays in pickup_location/dropoff_location
+ALTER TABLE book_rides
+ADD COLUMN pickup_place JSONB,
+ADD COLUMN dropoff_place JSONB;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE book_rides
+DROP COLUMN IF EXISTS dropoff_place,
+DROP COLUMN IF EXISTS pickup_place;
+
+-- +goose StatementEnd
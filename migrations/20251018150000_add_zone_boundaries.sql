@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- GeoJSON Polygon/MultiPolygon geometry of each service zone
+ALTER TABLE service_zones ADD COLUMN boundary JSONB;
+
+-- Zones each booking resolved to, and manual approval for cross-zone trips
+ALTER TABLE book_rides
+ADD COLUMN pickup_zone_id BIGINT REFERENCES service_zones(id) ON DELETE SET NULL,
+ADD COLUMN dropoff_zone_id BIGINT REFERENCES service_zones(id) ON DELETE SET NULL,
+ADD COLUMN requires_approval BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN approval_reason TEXT NOT NULL DEFAULT '',
+ADD COLUMN approved_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN approved_at TIMESTAMP;
+
+CREATE INDEX idx_book_rides_pickup_zone_id ON book_rides(pickup_zone_id);
+CREATE INDEX idx_book_rides_requires_approval ON book_rides(requires_approval) WHERE approved_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_book_rides_requires_approval;
+DROP INDEX IF EXISTS idx_book_rides_pickup_zone_id;
+
+ALTER TABLE book_rides
+DROP COLUMN IF EXISTS approved_at,
+DROP COLUMN IF EXISTS approved_by,
+DROP COLUMN IF EXISTS approval_reason,
+DROP COLUMN IF EXISTS requires_approval,
+DROP COLUMN IF EXISTS dropoff_zone_id,
+DROP COLUMN IF EXISTS pickup_zone_id;
+
+ALTER TABLE service_zones DROP COLUMN IF EXISTS boundary;
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Intermediate stops of a booking, in driving order
+CREATE TABLE booking_stops (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL REFERENCES book_rides(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    address TEXT NOT NULL,
+    place JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (booking_id, position)
+);
+
+-- Round trips are two bookings linked to each other
+ALTER TABLE book_rides
+ADD COLUMN return_booking_id BIGINT REFERENCES book_rides(id) ON DELETE SET NULL,
+ADD COLUMN outbound_booking_id BIGINT REFERENCES book_rides(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_book_rides_outbound_booking_id ON book_rides(outbound_booking_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_book_rides_outbound_booking_id;
+
+ALTER TABLE book_rides
+DROP COLUMN IF EXISTS outbound_booking_id,
+DROP COLUMN IF EXISTS return_booking_id;
+
+DROP TABLE IF EXISTS booking_stops;
+
+-- +goose StatementEnd
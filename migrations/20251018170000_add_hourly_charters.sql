@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Hourly ("as directed") charters book a duration instead of a drop-off
+ALTER TABLE book_rides
+ADD COLUMN booking_mode TEXT NOT NULL DEFAULT 'point_to_point',
+ADD COLUMN duration_hours INT,
+ADD COLUMN actual_minutes INT;
+
+ALTER TABLE book_rides
+ADD CONSTRAINT book_rides_booking_mode_check CHECK (booking_mode IN ('point_to_point', 'hourly')),
+ADD CONSTRAINT book_rides_duration_hours_check CHECK (
+    (booking_mode = 'hourly' AND duration_hours > 0) OR (booking_mode = 'point_to_point' AND duration_hours IS NULL)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE book_rides
+DROP CONSTRAINT IF EXISTS book_rides_duration_hours_check,
+DROP CONSTRAINT IF EXISTS book_rides_booking_mode_check;
+
+ALTER TABLE book_rides
+DROP COLUMN IF EXISTS actual_minutes,
+DROP COLUMN IF EXISTS duration_hours,
+DROP COLUMN IF EXISTS booking_mode;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Inbound flight of airport pickups, including the tracked status
+ALTER TABLE book_rides ADD COLUMN flight JSONB;
+
+CREATE INDEX idx_book_rides_tracked_flights ON book_rides(id)
+WHERE flight IS NOT NULL AND book_status IN ('Pending', 'Accepted');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_book_rides_tracked_flights;
+
+ALTER TABLE book_rides DROP COLUMN IF EXISTS flight;
+
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Recurring bookings; occurrences are generated ahead into book_rides
+CREATE TABLE booking_series (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recurrence TEXT NOT NULL,
+    start_date TEXT NOT NULL,
+    time TEXT NOT NULL,
+    template JSONB NOT NULL,
+    payment_method TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cancelled', 'completed')),
+    generated_until TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_booking_series_user_id ON booking_series(user_id);
+CREATE INDEX idx_booking_series_active ON booking_series(status) WHERE status = 'active';
+
+-- Occurrences keep the date the rule generated them for, even if the rider moves them
+ALTER TABLE book_rides
+ADD COLUMN series_id BIGINT REFERENCES booking_series(id) ON DELETE SET NULL,
+ADD COLUMN occurrence_date TEXT;
+
+CREATE UNIQUE INDEX idx_book_rides_series_occurrence ON book_rides(series_id, occurrence_date)
+WHERE series_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_book_rides_series_occurrence;
+
+ALTER TABLE book_rides
+DROP COLUMN IF EXISTS occurrence_date,
+DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS booking_series;
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE add_ons (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price BIGINT NOT NULL CHECK (price >= 0),
+    max_quantity INTEGER NOT NULL DEFAULT 1 CHECK (max_quantity > 0),
+    ride_types TEXT[] NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO add_ons (code, name, description, price, max_quantity, ride_types) VALUES
+    ('child_seat', 'Child seat', 'Rear-facing or forward-facing seat for children up to 18 kg', 1500, 3, '{}'),
+    ('booster_seat', 'Booster seat', 'Booster seat for children from 15 kg', 1000, 3, '{}'),
+    ('extra_luggage_space', 'Extra luggage space', 'Roof box or cleared third row for oversized luggage', 2000, 1, '{standard,airport}'),
+    ('champagne', 'Champagne', 'Chilled bottle of champagne on board', 6500, 2, '{premium}');
+
+-- Add-ons selected on each booking, priced at booking time
+ALTER TABLE book_rides ADD COLUMN add_ons JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE book_rides DROP COLUMN IF EXISTS add_ons;
+
+DROP TABLE IF EXISTS add_ons;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE ratings (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL REFERENCES book_rides(id) ON DELETE CASCADE,
+    direction TEXT NOT NULL CHECK (direction IN ('rider_to_driver', 'driver_to_rider')),
+    rater_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    ratee_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    score INTEGER NOT NULL CHECK (score BETWEEN 1 AND 5),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    comment TEXT NOT NULL DEFAULT '',
+    flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    reviewed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP,
+    review_note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (booking_id, direction)
+);
+
+CREATE INDEX idx_ratings_ratee_id ON ratings(ratee_id);
+CREATE INDEX idx_ratings_flagged ON ratings(created_at) WHERE flagged = TRUE AND reviewed_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS ratings;
+
+-- +goose StatementEnd
@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Fare payments are captured, voided and refunded with the booking; post-ride charges are separate payments
+ALTER TABLE payments ADD COLUMN purpose TEXT NOT NULL DEFAULT 'fare' CHECK (purpose IN ('fare', 'post_ride'));
+ALTER TABLE payments ADD COLUMN payment_method TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE post_ride_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL REFERENCES book_rides(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('tip', 'tolls', 'parking', 'waiting_time')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    minutes INTEGER CHECK (minutes > 0),
+    description TEXT NOT NULL DEFAULT '',
+    submitted_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'rejected', 'charged', 'failed')),
+    requires_approval BOOLEAN NOT NULL DEFAULT FALSE,
+    decided_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    payment_id BIGINT REFERENCES payments(id) ON DELETE SET NULL,
+    failure_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_post_ride_adjustments_booking_id ON post_ride_adjustments(booking_id);
+CREATE INDEX idx_post_ride_adjustments_pending ON post_ride_adjustments(created_at) WHERE status = 'pending';
+
+-- One tip per booking
+CREATE UNIQUE INDEX idx_post_ride_adjustments_tip ON post_ride_adjustments(booking_id)
+WHERE kind = 'tip' AND status IN ('pending', 'charged');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS post_ride_adjustments;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS payment_method;
+ALTER TABLE payments DROP COLUMN IF EXISTS purpose;
+
+-- +goose StatementEnd
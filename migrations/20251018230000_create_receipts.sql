@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Companies that issue receipts; each numbers its receipts in its own sequence
+CREATE TABLE legal_entities (
+    id BIGSERIAL PRIMARY KEY,
+    code TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    tax_id TEXT NOT NULL DEFAULT '',
+    tax_label TEXT NOT NULL DEFAULT 'Tax',
+    tax_rate_bp INTEGER NOT NULL DEFAULT 0 CHECK (tax_rate_bp >= 0 AND tax_rate_bp <= 10000),
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    receipt_prefix TEXT NOT NULL,
+    next_receipt_number BIGINT NOT NULL DEFAULT 1 CHECK (next_receipt_number > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO legal_entities (code, name, address, tax_label, receipt_prefix) VALUES
+    ('default', 'LuxSUV Transportation LLC', '', 'Sales tax', 'LUX');
+
+-- Bookings are receipted by the legal entity of their pickup zone, or the default one
+ALTER TABLE service_zones ADD COLUMN legal_entity_code TEXT REFERENCES legal_entities(code) ON UPDATE CASCADE ON DELETE SET NULL;
+
+CREATE TABLE receipts (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL UNIQUE REFERENCES book_rides(id) ON DELETE CASCADE,
+    legal_entity_id BIGINT NOT NULL REFERENCES legal_entities(id),
+    sequence_number BIGINT NOT NULL,
+    number TEXT NOT NULL,
+    issued_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (legal_entity_id, sequence_number)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS receipts;
+
+ALTER TABLE service_zones DROP COLUMN IF EXISTS legal_entity_code;
+
+DROP TABLE IF EXISTS legal_entities;
+
+-- +goose StatementEnd
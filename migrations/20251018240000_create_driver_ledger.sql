@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE payout_batches (
+    id BIGSERIAL PRIMARY KEY,
+    period_start TIMESTAMP NOT NULL UNIQUE,
+    period_end TIMESTAMP NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    total_amount BIGINT NOT NULL DEFAULT 0,
+    payout_count INTEGER NOT NULL DEFAULT 0,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (period_end > period_start)
+);
+
+CREATE TABLE payouts (
+    id BIGSERIAL PRIMARY KEY,
+    batch_id BIGINT NOT NULL REFERENCES payout_batches(id) ON DELETE CASCADE,
+    driver_id BIGINT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'failed')),
+    reference TEXT NOT NULL DEFAULT '',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (batch_id, driver_id)
+);
+
+CREATE INDEX idx_payouts_driver_id ON payouts(driver_id);
+
+-- Double-entry ledger: the entries of each transaction sum to zero
+CREATE TABLE ledger_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    reference TEXT NOT NULL UNIQUE,
+    kind TEXT NOT NULL,
+    booking_id BIGINT REFERENCES book_rides(id) ON DELETE SET NULL,
+    payout_id BIGINT REFERENCES payouts(id) ON DELETE SET NULL,
+    description TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES ledger_transactions(id) ON DELETE CASCADE,
+    account TEXT NOT NULL,
+    driver_id BIGINT REFERENCES users(id) ON DELETE RESTRICT,
+    category TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'USD'
+);
+
+CREATE INDEX idx_ledger_entries_transaction_id ON ledger_entries(transaction_id);
+CREATE INDEX idx_ledger_entries_driver ON ledger_entries(driver_id, account) WHERE driver_id IS NOT NULL;
+CREATE INDEX idx_ledger_transactions_occurred_at ON ledger_transactions(occurred_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP TABLE IF EXISTS payouts;
+DROP TABLE IF EXISTS payout_batches;
+
+-- +goose StatementEnd
@@ -0,0 +1,99 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    billing_email TEXT NOT NULL,
+    billing_address TEXT NOT NULL DEFAULT '',
+    tax_id TEXT NOT NULL DEFAULT '',
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    approval_threshold BIGINT CHECK (approval_threshold >= 0), -- Bookings quoted above this need an org admin's approval
+    require_cost_center BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE cost_centers (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, code)
+);
+
+-- A user books for at most one organization
+CREATE TABLE organization_members (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'employee' CHECK (role IN ('org_admin', 'employee')),
+    default_cost_center_id BIGINT REFERENCES cost_centers(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_organization_members_organization_id ON organization_members(organization_id);
+
+ALTER TABLE book_rides
+    ADD COLUMN organization_id BIGINT REFERENCES organizations(id) ON DELETE RESTRICT,
+    ADD COLUMN cost_center_id BIGINT REFERENCES cost_centers(id) ON DELETE SET NULL,
+    ADD COLUMN org_approval_status TEXT NOT NULL DEFAULT 'not_required'
+        CHECK (org_approval_status IN ('not_required', 'pending', 'approved', 'rejected')),
+    ADD COLUMN org_approved_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN org_approved_at TIMESTAMP;
+
+CREATE INDEX idx_book_rides_organization_id ON book_rides(organization_id) WHERE organization_id IS NOT NULL;
+
+CREATE TABLE organization_invoices (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE RESTRICT,
+    number TEXT NOT NULL UNIQUE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL, -- Exclusive
+    currency CHAR(3) NOT NULL DEFAULT 'USD',
+    total_amount BIGINT NOT NULL DEFAULT 0,
+    booking_count INTEGER NOT NULL DEFAULT 0,
+    issued_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, period_start)
+);
+
+-- Each booking is invoiced once
+CREATE TABLE organization_invoice_lines (
+    id BIGSERIAL PRIMARY KEY,
+    invoice_id BIGINT NOT NULL REFERENCES organization_invoices(id) ON DELETE CASCADE,
+    booking_id BIGINT NOT NULL UNIQUE REFERENCES book_rides(id) ON DELETE RESTRICT,
+    cost_center_code TEXT NOT NULL DEFAULT '',
+    rider_name TEXT NOT NULL,
+    ride_date TEXT NOT NULL,
+    description TEXT NOT NULL,
+    amount BIGINT NOT NULL
+);
+
+CREATE INDEX idx_organization_invoice_lines_invoice_id ON organization_invoice_lines(invoice_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS organization_invoice_lines;
+DROP TABLE IF EXISTS organization_invoices;
+
+DROP INDEX IF EXISTS idx_book_rides_organization_id;
+ALTER TABLE book_rides
+    DROP COLUMN IF EXISTS org_approved_at,
+    DROP COLUMN IF EXISTS org_approved_by,
+    DROP COLUMN IF EXISTS org_approval_status,
+    DROP COLUMN IF EXISTS cost_center_id,
+    DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS cost_centers;
+DROP TABLE IF EXISTS organizations;
+
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The booker (your_name, email, phone_number) manages the booking; the passenger rides.
+-- Bookings without a passenger name are ridden by the booker.
+ALTER TABLE book_rides
+    ADD COLUMN passenger_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN passenger_email TEXT NOT NULL DEFAULT '',
+    ADD COLUMN passenger_phone TEXT NOT NULL DEFAULT '',
+    ADD COLUMN notifications JSONB;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE book_rides
+    DROP COLUMN IF EXISTS notifications,
+    DROP COLUMN IF EXISTS passenger_phone,
+    DROP COLUMN IF EXISTS passenger_email,
+    DROP COLUMN IF EXISTS passenger_name;
+
+-- +goose StatementEnd
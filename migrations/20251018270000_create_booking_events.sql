@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Booking lifecycle events streamed to riders, drivers and dispatchers. Rows are kept for
+-- EVENT_RETENTION_HOURS so reconnecting clients can replay what they missed.
+CREATE TABLE booking_events (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id BIGINT NOT NULL,
+    type TEXT NOT NULL,
+    user_id BIGINT,
+    driver_id BIGINT,
+    book_status TEXT NOT NULL,
+    ride_status TEXT NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_booking_events_user_id ON booking_events(user_id, id);
+CREATE INDEX idx_booking_events_driver_id ON booking_events(driver_id, id);
+CREATE INDEX idx_booking_events_created_at ON booking_events(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS booking_events;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- GPS pings posted by drivers while a ride is active; pruned after LOCATION_RETENTION_HOURS
+CREATE TABLE driver_locations (
+    id BIGSERIAL PRIMARY KEY,
+    driver_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    booking_id BIGINT NOT NULL REFERENCES book_rides(id) ON DELETE CASCADE,
+    lat DOUBLE PRECISION NOT NULL,
+    lng DOUBLE PRECISION NOT NULL,
+    speed_kmh DOUBLE PRECISION,
+    heading DOUBLE PRECISION,
+    accuracy_m DOUBLE PRECISION,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_driver_locations_booking_id ON driver_locations(booking_id, recorded_at DESC);
+CREATE INDEX idx_driver_locations_received_at ON driver_locations(received_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS driver_locations;
+
+-- +goose StatementEnd
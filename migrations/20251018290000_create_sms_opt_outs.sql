@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Phone numbers (E.164) that replied STOP or were opted out by an admin; nothing but the opt-out
+-- confirmation is texted to them until they reply START
+CREATE TABLE sms_opt_outs (
+    phone TEXT PRIMARY KEY,
+    source TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS sms_opt_outs;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Scheduled jobs; an instance runs a due job only while it holds the job's lease, so each run
+-- happens on one instance even when several are deployed
+CREATE TABLE job_leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL DEFAULT '',
+    leased_until TIMESTAMPTZ NOT NULL DEFAULT 'epoch',
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One row per notice sent about a booking (reminders, dispatch alerts), so none is sent twice
+CREATE TABLE booking_notices (
+    booking_id BIGINT NOT NULL REFERENCES book_rides(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (booking_id, kind)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS booking_notices;
+DROP TABLE IF EXISTS job_leases;
+
+-- +goose StatementEnd
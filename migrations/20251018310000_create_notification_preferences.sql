@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- What each user wants to be told and how. channels maps an event type to the channels (email, sms,
+-- push, webhook) chosen for it; events and channels missing from it use the defaults. Quiet hours
+-- are HH:MM in the user's time zone and hold back texts and pushes that are not urgent.
+CREATE TABLE notification_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    channels JSONB NOT NULL DEFAULT '{}',
+    quiet_hours_start TEXT,
+    quiet_hours_end TEXT,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    language TEXT NOT NULL DEFAULT 'en',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS notification_preferences;
+
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- First responses to mutating requests sent with an Idempotency-Key header, replayed to retries.
+-- A key belongs to a principal (user, API key, or guest booking token); request_hash covers the method, URL and
+-- body, so a key reused for a different request is refused. response_status is NULL while the
+-- first request is still being handled. Rows past expires_at are deleted by a scheduled job.
+CREATE TABLE idempotency_keys (
+    id BIGSERIAL PRIMARY KEY,
+    principal TEXT NOT NULL,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    response_status INTEGER,
+    response_content_type TEXT NOT NULL DEFAULT '',
+    response_body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    UNIQUE (principal, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS idempotency_keys;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Set when the booking's hold could not be captured, voided or raised to cover a higher quote,
+-- so admins can follow up; cleared once they resolve it.
+ALTER TABLE book_rides
+    ADD COLUMN payment_issue TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_book_rides_payment_issue ON book_rides(id) WHERE payment_issue <> '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_book_rides_payment_issue;
+
+ALTER TABLE book_rides
+    DROP COLUMN IF EXISTS payment_issue;
+
+-- +goose StatementEnd
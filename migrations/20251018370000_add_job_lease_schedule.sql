@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- The schedule a job was registered with, e.g. "daily at 06:00 America/Chicago". When an instance
+-- starts with a different schedule, the job's next run is moved to match it.
+ALTER TABLE job_leases
+    ADD COLUMN schedule TEXT NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE job_leases
+    DROP COLUMN IF EXISTS schedule;
+
+-- +goose StatementEnd
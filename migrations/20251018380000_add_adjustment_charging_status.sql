@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- An adjustment is claimed as charging before its card charge is made, so two approvals of the
+-- same extra cannot both charge it. It becomes charged or failed once the charge returns.
+ALTER TABLE post_ride_adjustments DROP CONSTRAINT post_ride_adjustments_status_check;
+ALTER TABLE post_ride_adjustments ADD CONSTRAINT post_ride_adjustments_status_check
+    CHECK (status IN ('pending', 'charging', 'rejected', 'charged', 'failed'));
+
+DROP INDEX IF EXISTS idx_post_ride_adjustments_tip;
+CREATE UNIQUE INDEX idx_post_ride_adjustments_tip ON post_ride_adjustments(booking_id)
+WHERE kind = 'tip' AND status IN ('pending', 'charging', 'charged');
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+UPDATE post_ride_adjustments SET status = 'failed', failure_reason = 'charge interrupted' WHERE status = 'charging';
+
+DROP INDEX IF EXISTS idx_post_ride_adjustments_tip;
+CREATE UNIQUE INDEX idx_post_ride_adjustments_tip ON post_ride_adjustments(booking_id)
+WHERE kind = 'tip' AND status IN ('pending', 'charged');
+
+ALTER TABLE post_ride_adjustments DROP CONSTRAINT post_ride_adjustments_status_check;
+ALTER TABLE post_ride_adjustments ADD CONSTRAINT post_ride_adjustments_status_check
+    CHECK (status IN ('pending', 'rejected', 'charged', 'failed'));
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Provider events already handled, so redelivered webhooks are applied only once
+CREATE TABLE payment_events (
+    provider TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    payment_id BIGINT NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    applied BOOLEAN NOT NULL DEFAULT FALSE,
+    received_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, event_id)
+);
+
+CREATE INDEX idx_payment_events_payment_id ON payment_events(payment_id);
+
+-- Time of the newest applied provider event; older events arriving late are ignored
+ALTER TABLE payments ADD COLUMN last_event_at TIMESTAMP;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE payments DROP COLUMN IF EXISTS last_event_at;
+DROP TABLE IF EXISTS payment_events;
+
+-- +goose StatementEnd